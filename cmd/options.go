@@ -28,3 +28,10 @@ func WithStreams(out, err io.Writer) Option {
 		c.errStream = err
 	}
 }
+
+// WithInStream overrides the default reader used by the stdio transport.
+func WithInStream(in io.Reader) Option {
+	return func(c *Command) {
+		c.inStream = in
+	}
+}
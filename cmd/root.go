@@ -21,16 +21,16 @@ import (
 	"io"
 	"os"
 	"os/signal"
-	"regexp"
 	"strings"
 	"syscall"
 	"time"
 
-	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/secrets"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/googleapis/genai-toolbox/toolbox"
 	"github.com/spf13/cobra"
 )
 
@@ -68,11 +68,22 @@ func Execute() {
 type Command struct {
 	*cobra.Command
 
-	cfg        server.ServerConfig
-	logger     log.Logger
-	tools_file string
-	outStream  io.Writer
-	errStream  io.Writer
+	cfg          server.ServerConfig
+	logger       log.Logger
+	tools_files  []string
+	tools_folder string
+	stdio        bool
+	// disableReload disables reloading the tools files when they change.
+	disableReload bool
+	inStream      io.Reader
+	outStream     io.Writer
+	errStream     io.Writer
+}
+
+// toolsFilePaths returns the paths of the tools files passed with
+// --tools-file.
+func (c *Command) toolsFilePaths() []string {
+	return toolsFilePaths(c.Flags(), c.tools_files, c.tools_folder)
 }
 
 // NewCommand returns a Command object representing an invocation of the CLI.
@@ -87,6 +98,7 @@ func NewCommand(opts ...Option) *Command {
 	}
 	cmd := &Command{
 		Command:   baseCmd,
+		inStream:  os.Stdin,
 		outStream: out,
 		errStream: err,
 	}
@@ -106,61 +118,38 @@ func NewCommand(opts ...Option) *Command {
 	flags.StringVarP(&cmd.cfg.Address, "address", "a", "127.0.0.1", "Address of the interface the server will listen on.")
 	flags.IntVarP(&cmd.cfg.Port, "port", "p", 5000, "Port the server will listen on.")
 
-	flags.StringVar(&cmd.tools_file, "tools_file", "tools.yaml", "File path specifying the tool configuration.")
+	flags.StringArrayVar(&cmd.tools_files, "tools_file", []string{"tools.yaml"}, "File path specifying the tool configuration.")
 	// deprecate tools_file
 	_ = flags.MarkDeprecated("tools_file", "please use --tools-file instead")
-	flags.StringVar(&cmd.tools_file, "tools-file", "tools.yaml", "File path specifying the tool configuration.")
+	flags.StringArrayVar(&cmd.tools_files, "tools-file", []string{"tools.yaml"}, "File path specifying the tool configuration. Can be repeated to merge several files.")
+	flags.StringVar(&cmd.tools_folder, "tools-folder", "", "Folder whose YAML files are merged into the tool configuration. Only the files passed with --tools-file are loaded in addition to them.")
 	flags.Var(&cmd.cfg.LogLevel, "log-level", "Specify the minimum level logged. Allowed: 'DEBUG', 'INFO', 'WARN', 'ERROR'.")
 	flags.Var(&cmd.cfg.LoggingFormat, "logging-format", "Specify logging format to use. Allowed: 'standard' or 'JSON'.")
 	flags.BoolVar(&cmd.cfg.TelemetryGCP, "telemetry-gcp", false, "Enable exporting directly to Google Cloud Monitoring.")
 	flags.StringVar(&cmd.cfg.TelemetryOTLP, "telemetry-otlp", "", "Enable exporting using OpenTelemetry Protocol (OTLP) to the specified endpoint (e.g. 'http://127.0.0.1:4318')")
 	flags.StringVar(&cmd.cfg.TelemetryServiceName, "telemetry-service-name", "toolbox", "Sets the value of the service.name resource attribute for telemetry data.")
+	flags.BoolVar(&cmd.stdio, "stdio", false, "Listens via MCP STDIO instead of acting as a remote HTTP server.")
+	flags.BoolVar(&cmd.disableReload, "disable-reload", false, "Disables reloading the tools files when they change. They are still reloaded on SIGHUP.")
+	flags.DurationVar(&cmd.cfg.SseKeepAliveInterval, "sse-keepalive-interval", 30*time.Second, "Interval between keepalive comments sent on idle MCP event streams. Set to 0 to disable.")
+	flags.DurationVar(&cmd.cfg.SseSessionIdleTimeout, "sse-session-idle-timeout", 5*time.Minute, "Duration after which MCP sessions without an open event stream expire. Set to 0 to never expire sessions.")
+	flags.IntVar(&cmd.cfg.SseReplayBufferSize, "sse-replay-buffer-size", 100, "Number of events kept by each MCP session for clients resuming its event stream.")
+	flags.StringVar(&cmd.cfg.SseSessionStore, "sse-session-store", "memory", "Where MCP sessions are stored. Allowed: 'memory' or the URL of a Redis server shared by every replica (e.g. 'redis://127.0.0.1:6379/0').")
 
 	// wrap RunE command so that we have access to original Command object
 	cmd.RunE = func(*cobra.Command, []string) error { return run(cmd) }
 
-	return cmd
-}
+	baseCmd.AddCommand(newValidateCommand())
+	baseCmd.AddCommand(newSchemaCommand())
 
-type ToolsFile struct {
-	Sources      server.SourceConfigs      `yaml:"sources"`
-	AuthSources  server.AuthServiceConfigs `yaml:"authSources"` // Deprecated: Kept for compatibility.
-	AuthServices server.AuthServiceConfigs `yaml:"authServices"`
-	Tools        server.ToolConfigs        `yaml:"tools"`
-	Toolsets     server.ToolsetConfigs     `yaml:"toolsets"`
+	return cmd
 }
 
-// parseEnv replaces environment variables ${ENV_NAME} with their values.
-func parseEnv(input string) string {
-	re := regexp.MustCompile(`\$\{(\w+)\}`)
-
-	return re.ReplaceAllStringFunc(input, func(match string) string {
-		parts := re.FindStringSubmatch(match)
-		if len(parts) < 2 {
-			// technically shouldn't happen
-			return match
-		}
-
-		// extract the variable name
-		variableName := parts[1]
-		if value, found := os.LookupEnv(variableName); found {
-			return value
-		}
-		return match
-	})
-}
+// ToolsFile is the configuration read from a tools file.
+type ToolsFile = toolbox.ToolsFile
 
 // parseToolsFile parses the provided yaml into appropriate configs.
 func parseToolsFile(ctx context.Context, raw []byte) (ToolsFile, error) {
-	var toolsFile ToolsFile
-	// Replace environment variables if found
-	raw = []byte(parseEnv(string(raw)))
-	// Parse contents
-	err := yaml.UnmarshalContext(ctx, raw, &toolsFile, yaml.Strict())
-	if err != nil {
-		return toolsFile, err
-	}
-	return toolsFile, nil
+	return toolbox.ParseToolsFile(ctx, raw)
 }
 
 func run(cmd *Command) error {
@@ -187,16 +176,23 @@ func run(cmd *Command) error {
 		cancel()
 	}(ctx)
 
+	// stdout is reserved for MCP messages when using stdio, so all logs are
+	// written to stderr instead.
+	logOut := cmd.outStream
+	if cmd.stdio {
+		logOut = cmd.errStream
+	}
+
 	// Handle logger separately from config
 	switch strings.ToLower(cmd.cfg.LoggingFormat.String()) {
 	case "json":
-		logger, err := log.NewStructuredLogger(cmd.outStream, cmd.errStream, cmd.cfg.LogLevel.String())
+		logger, err := log.NewStructuredLogger(logOut, cmd.errStream, cmd.cfg.LogLevel.String())
 		if err != nil {
 			return fmt.Errorf("unable to initialize logger: %w", err)
 		}
 		cmd.logger = logger
 	case "standard":
-		logger, err := log.NewStdLogger(cmd.outStream, cmd.errStream, cmd.cfg.LogLevel.String())
+		logger, err := log.NewStdLogger(logOut, cmd.errStream, cmd.cfg.LogLevel.String())
 		if err != nil {
 			return fmt.Errorf("unable to initialize logger: %w", err)
 		}
@@ -204,6 +200,8 @@ func run(cmd *Command) error {
 	default:
 		return fmt.Errorf("logging format invalid.")
 	}
+	// keep the secrets referenced by the tools files out of the logs
+	cmd.logger = log.NewRedactingLogger(cmd.logger, secrets.Redact)
 
 	ctx = util.WithLogger(ctx, cmd.logger)
 
@@ -222,25 +220,12 @@ func run(cmd *Command) error {
 		}
 	}()
 
-	// Read tool file contents
-	buf, err := os.ReadFile(cmd.tools_file)
+	toolsFile, toolsFiles, err := loadToolsFiles(ctx, cmd.logger, cmd.toolsFilePaths(), cmd.tools_folder)
 	if err != nil {
-		errMsg := fmt.Errorf("unable to read tool file at %q: %w", cmd.tools_file, err)
-		cmd.logger.ErrorContext(ctx, errMsg.Error())
-		return errMsg
-	}
-	toolsFile, err := parseToolsFile(ctx, buf)
-	cmd.cfg.SourceConfigs, cmd.cfg.AuthServiceConfigs, cmd.cfg.ToolConfigs, cmd.cfg.ToolsetConfigs = toolsFile.Sources, toolsFile.AuthServices, toolsFile.Tools, toolsFile.Toolsets
-	authSourceConfigs := toolsFile.AuthSources
-	if authSourceConfigs != nil {
-		cmd.logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` instead")
-		cmd.cfg.AuthServiceConfigs = authSourceConfigs
-	}
-	if err != nil {
-		errMsg := fmt.Errorf("unable to parse tool file at %q: %w", cmd.tools_file, err)
-		cmd.logger.ErrorContext(ctx, errMsg.Error())
-		return errMsg
+		cmd.logger.ErrorContext(ctx, err.Error())
+		return err
 	}
+	setToolsFileConfigs(&cmd.cfg, toolsFile)
 
 	// start server
 	s, err := server.NewServer(ctx, cmd.cfg, cmd.logger)
@@ -250,6 +235,20 @@ func run(cmd *Command) error {
 		return errMsg
 	}
 
+	// reload the tools files when they change
+	go watchToolsFiles(ctx, cmd, s, toolsFiles)
+
+	if cmd.stdio {
+		cmd.logger.InfoContext(ctx, "Server ready to serve via stdio!")
+		err = s.ServeStdio(ctx, cmd.inStream, cmd.outStream)
+		if err != nil {
+			errMsg := fmt.Errorf("toolbox crashed with the following error: %w", err)
+			cmd.logger.ErrorContext(ctx, errMsg.Error())
+			return errMsg
+		}
+		return nil
+	}
+
 	err = s.Listen(ctx)
 	if err != nil {
 		errMsg := fmt.Errorf("toolbox failed to start listener: %w", err)
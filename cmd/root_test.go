@@ -18,12 +18,15 @@ import (
 	"bytes"
 	_ "embed"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/googleapis/genai-toolbox/internal/auth/google"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	cloudsqlpgsrc "github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
 	httpsrc "github.com/googleapis/genai-toolbox/internal/sources/http"
@@ -46,6 +49,18 @@ func withDefaults(c server.ServerConfig) server.ServerConfig {
 	if c.TelemetryServiceName == "" {
 		c.TelemetryServiceName = "toolbox"
 	}
+	if c.SseKeepAliveInterval == 0 {
+		c.SseKeepAliveInterval = 30 * time.Second
+	}
+	if c.SseSessionIdleTimeout == 0 {
+		c.SseSessionIdleTimeout = 5 * time.Minute
+	}
+	if c.SseReplayBufferSize == 0 {
+		c.SseReplayBufferSize = 100
+	}
+	if c.SseSessionStore == "" {
+		c.SseSessionStore = "memory"
+	}
 	return c
 }
 
@@ -163,6 +178,22 @@ func TestServerConfigFlags(t *testing.T) {
 				TelemetryServiceName: "toolbox-custom",
 			}),
 		},
+		{
+			desc: "sse session limits",
+			args: []string{"--sse-keepalive-interval", "15s", "--sse-session-idle-timeout", "1h", "--sse-replay-buffer-size", "500"},
+			want: withDefaults(server.ServerConfig{
+				SseKeepAliveInterval:  15 * time.Second,
+				SseSessionIdleTimeout: time.Hour,
+				SseReplayBufferSize:   500,
+			}),
+		},
+		{
+			desc: "sse session store",
+			args: []string{"--sse-session-store", "redis://127.0.0.1:6379/0"},
+			want: withDefaults(server.ServerConfig{
+				SseSessionStore: "redis://127.0.0.1:6379/0",
+			}),
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -180,29 +211,47 @@ func TestServerConfigFlags(t *testing.T) {
 
 func TestToolFileFlag(t *testing.T) {
 	tcs := []struct {
-		desc string
-		args []string
-		want string
+		desc       string
+		args       []string
+		want       []string
+		wantFolder string
 	}{
 		{
 			desc: "default value",
 			args: []string{},
-			want: "tools.yaml",
+			want: []string{"tools.yaml"},
 		},
 		{
 			desc: "foo file",
 			args: []string{"--tools-file", "foo.yaml"},
-			want: "foo.yaml",
+			want: []string{"foo.yaml"},
 		},
 		{
 			desc: "address long",
 			args: []string{"--tools-file", "bar.yaml"},
-			want: "bar.yaml",
+			want: []string{"bar.yaml"},
 		},
 		{
 			desc: "deprecated flag",
 			args: []string{"--tools_file", "foo.yaml"},
-			want: "foo.yaml",
+			want: []string{"foo.yaml"},
+		},
+		{
+			desc: "multiple files",
+			args: []string{"--tools-file", "foo.yaml", "--tools-file", "bar.yaml"},
+			want: []string{"foo.yaml", "bar.yaml"},
+		},
+		{
+			desc:       "folder",
+			args:       []string{"--tools-folder", "tools"},
+			want:       nil,
+			wantFolder: "tools",
+		},
+		{
+			desc:       "folder and file",
+			args:       []string{"--tools-folder", "tools", "--tools-file", "foo.yaml"},
+			want:       []string{"foo.yaml"},
+			wantFolder: "tools",
 		},
 	}
 	for _, tc := range tcs {
@@ -211,13 +260,52 @@ func TestToolFileFlag(t *testing.T) {
 			if err != nil {
 				t.Fatalf("unexpected error invoking command: %s", err)
 			}
-			if c.tools_file != tc.want {
-				t.Fatalf("got %v, want %v", c.cfg, tc.want)
+			if diff := cmp.Diff(tc.want, c.toolsFilePaths()); diff != "" {
+				t.Fatalf("incorrect tools files: diff %v", diff)
+			}
+			if c.tools_folder != tc.wantFolder {
+				t.Fatalf("got %v, want %v", c.tools_folder, tc.wantFolder)
 			}
 		})
 	}
 }
 
+func TestStdioFlag(t *testing.T) {
+	c, _, err := invokeCommand([]string{})
+	if err != nil {
+		t.Fatalf("unexpected error invoking command: %s", err)
+	}
+	if c.stdio {
+		t.Fatalf("unexpected default stdio flag: got %v, want %v", c.stdio, false)
+	}
+
+	c, _, err = invokeCommand([]string{"--stdio"})
+	if err != nil {
+		t.Fatalf("unexpected error invoking command: %s", err)
+	}
+	if !c.stdio {
+		t.Fatalf("unexpected stdio flag: got %v, want %v", c.stdio, true)
+	}
+}
+
+func TestDisableReloadFlag(t *testing.T) {
+	c, _, err := invokeCommand([]string{})
+	if err != nil {
+		t.Fatalf("unexpected error invoking command: %s", err)
+	}
+	if c.disableReload {
+		t.Fatalf("unexpected default disable-reload flag: got %v, want %v", c.disableReload, false)
+	}
+
+	c, _, err = invokeCommand([]string{"--disable-reload"})
+	if err != nil {
+		t.Fatalf("unexpected error invoking command: %s", err)
+	}
+	if !c.disableReload {
+		t.Fatalf("unexpected disable-reload flag: got %v, want %v", c.disableReload, true)
+	}
+}
+
 func TestFailServerConfigFlags(t *testing.T) {
 	tcs := []struct {
 		desc string
@@ -338,6 +426,37 @@ func TestParseToolFile(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "with prompts",
+			in: `
+			prompts:
+				summarize_table:
+					description: Summarize the contents of a table.
+					arguments:
+						- name: table
+							type: string
+							description: name of the table
+					messages:
+						- content: Summarize the {{.table}} table.
+						- role: assistant
+							content: Which columns should I focus on?
+			`,
+			wantToolsFile: ToolsFile{
+				Prompts: server.PromptConfigs{
+					"summarize_table": prompts.PromptConfig{
+						Name:        "summarize_table",
+						Description: "Summarize the contents of a table.",
+						Arguments: tools.Parameters{
+							tools.NewStringParameter("table", "name of the table"),
+						},
+						Messages: []prompts.MessageConfig{
+							{Content: "Summarize the {{.table}} table."},
+							{Role: "assistant", Content: "Which columns should I focus on?"},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.description, func(t *testing.T) {
@@ -357,6 +476,9 @@ func TestParseToolFile(t *testing.T) {
 			if diff := cmp.Diff(tc.wantToolsFile.Toolsets, toolsFile.Toolsets); diff != "" {
 				t.Fatalf("incorrect tools parse: diff %v", diff)
 			}
+			if diff := cmp.Diff(tc.wantToolsFile.Prompts, toolsFile.Prompts); diff != "" {
+				t.Fatalf("incorrect prompts parse: diff %v", diff)
+			}
 		})
 	}
 
@@ -858,3 +980,61 @@ func TestEnvVarReplacement(t *testing.T) {
 	}
 
 }
+
+func TestSecretReplacement(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Setenv("PG_USER", "ACTUAL_USER")
+	passwordFile := filepath.Join(t.TempDir(), "pg_password")
+	if err := os.WriteFile(passwordFile, []byte("ACTUAL_PASSWORD\n"), 0o600); err != nil {
+		t.Fatalf("unable to write file: %s", err)
+	}
+
+	in := `
+	sources:
+		my-pg-instance:
+			kind: cloud-sql-postgres
+			project: ${PG_PROJECT:-my-project}
+			region: my-region
+			instance: my-instance
+			database: my_db
+			user: ${PG_USER}
+			password: ${file:` + passwordFile + `}
+	`
+	toolsFile, err := parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+	want := server.SourceConfigs{
+		"my-pg-instance": cloudsqlpgsrc.Config{
+			Name:     "my-pg-instance",
+			Kind:     cloudsqlpgsrc.SourceKind,
+			Project:  "my-project",
+			Region:   "my-region",
+			Instance: "my-instance",
+			IPType:   "public",
+			Database: "my_db",
+			User:     "ACTUAL_USER",
+			Password: "ACTUAL_PASSWORD",
+		},
+	}
+	if diff := cmp.Diff(want, toolsFile.Sources); diff != "" {
+		t.Fatalf("incorrect sources parse: diff %v", diff)
+	}
+
+	// a required variable that is not set is an error
+	if _, err := parseToolsFile(ctx, testutils.FormatYaml(strings.Replace(in, "${PG_USER}", "${PG_MISSING_USER}", 1))); err == nil || !strings.Contains(err.Error(), "${PG_MISSING_USER} is required") {
+		t.Fatalf("expected missing variable error, got %v", err)
+	}
+
+	// parsing errors do not include secrets
+	_, err = parseToolsFile(ctx, testutils.FormatYaml(in+"\t\t\tunknown: field\n"))
+	if err == nil {
+		t.Fatalf("expected parsing error")
+	}
+	if strings.Contains(err.Error(), "ACTUAL_PASSWORD") {
+		t.Fatalf("error %q contains a secret", err)
+	}
+}
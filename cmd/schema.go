@@ -0,0 +1,52 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/toolbox"
+	"github.com/spf13/cobra"
+)
+
+// newSchemaCommand returns the schema subcommand, which writes the JSON Schema
+// of tools files.
+func newSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Write the JSON Schema of tools files",
+		Long: `Write the JSON Schema of tools files to standard output.
+
+The schema describes every kind of source, tool and auth service known to this
+version of Toolbox. Editors can use it to complete and check tools files, for
+example by adding the following comment at the top of tools.yaml:
+
+  # yaml-language-server: $schema=./tools.schema.json`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, _ []string) error {
+			enc := json.NewEncoder(c.OutOrStdout())
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(toolbox.Schema()); err != nil {
+				// errors are silenced by the root command
+				err = fmt.Errorf("unable to write schema: %w", err)
+				fmt.Fprintln(c.ErrOrStderr(), err)
+				return err
+			}
+			return nil
+		},
+	}
+}
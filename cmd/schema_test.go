@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSchemaCommand(t *testing.T) {
+	_, output, err := invokeCommand([]string{"schema"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var schema struct {
+		Properties  map[string]json.RawMessage `json:"properties"`
+		Definitions map[string]struct {
+			Properties map[string]map[string]any `json:"properties"`
+			Required   []string                  `json:"required"`
+		} `json:"definitions"`
+	}
+	if err := json.Unmarshal([]byte(output), &schema); err != nil {
+		t.Fatalf("unable to parse schema: %s", err)
+	}
+
+	for _, section := range []string{"include", "sources", "authSources", "authServices", "tools", "toolsets", "prompts"} {
+		if _, ok := schema.Properties[section]; !ok {
+			t.Errorf("missing section %q", section)
+		}
+	}
+
+	tool, ok := schema.Definitions["tool-postgres-sql"]
+	if !ok {
+		t.Fatalf("missing definition of tool kind %q", "postgres-sql")
+	}
+	if diff := cmp.Diff([]string{"kind", "source", "description", "statement"}, tool.Required); diff != "" {
+		t.Errorf("incorrect required fields: diff %v", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"const": "postgres-sql"}, tool.Properties["kind"]); diff != "" {
+		t.Errorf("incorrect kind: diff %v", diff)
+	}
+	if _, ok := tool.Properties["name"]; ok {
+		t.Errorf("name should be set from the key of the tool")
+	}
+
+	source, ok := schema.Definitions["source-cloud-sql-postgres"]
+	if !ok {
+		t.Fatalf("missing definition of source kind %q", "cloud-sql-postgres")
+	}
+	want := map[string]any{"type": "string", "enum": []any{"public", "private"}, "default": "public"}
+	if diff := cmp.Diff(want, source.Properties["ipType"]); diff != "" {
+		t.Errorf("incorrect ipType: diff %v", diff)
+	}
+}
@@ -0,0 +1,341 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/spf13/pflag"
+)
+
+// isYAMLFile reports whether path has a YAML file extension.
+func isYAMLFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// toolsFileLoader merges tools files into a single ToolsFile, recording the
+// file each resource is defined in.
+type toolsFileLoader struct {
+	ctx    context.Context
+	logger log.Logger
+
+	merged ToolsFile
+	// origins maps the kind and name of each resource to the file and section
+	// defining it.
+	origins map[string]map[string]resourceOrigin
+	// files lists the files loaded, in order.
+	files  []string
+	loaded map[string]bool
+	// errs are the errors found while loading the files.
+	errs []error
+}
+
+func newToolsFileLoader(ctx context.Context, l log.Logger) *toolsFileLoader {
+	return &toolsFileLoader{
+		ctx:     ctx,
+		logger:  l,
+		origins: make(map[string]map[string]resourceOrigin),
+		loaded:  make(map[string]bool),
+	}
+}
+
+// loadToolsFiles reads the tools files at paths, the YAML files of folder and
+// the files they include, and merges them into a single ToolsFile. It also
+// returns the paths of every file read. Resources may only be defined once
+// across all files.
+func loadToolsFiles(ctx context.Context, l log.Logger, paths []string, folder string) (ToolsFile, []string, error) {
+	loader := newToolsFileLoader(ctx, l)
+	if err := loader.loadAll(paths, folder); err != nil {
+		return ToolsFile{}, nil, err
+	}
+	return loader.merged, loader.files, nil
+}
+
+// loadAll loads the tools files at paths and the YAML files of folder. A file
+// that fails to load does not stop the others from being loaded, and the
+// errors of every file are returned.
+func (l *toolsFileLoader) loadAll(paths []string, folder string) error {
+	for _, path := range paths {
+		l.load(path)
+	}
+	if folder != "" {
+		l.loadFolder(folder)
+	}
+	return errors.Join(l.errs...)
+}
+
+// loadFolder loads the YAML files of folder.
+func (l *toolsFileLoader) loadFolder(folder string) {
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("unable to read tools folder %q: %w", folder, err))
+		return
+	}
+	found := false
+	// entries are sorted by file name
+	for _, e := range entries {
+		if e.IsDir() || !isYAMLFile(e.Name()) {
+			continue
+		}
+		found = true
+		l.load(filepath.Join(folder, e.Name()))
+	}
+	if !found {
+		l.errs = append(l.errs, fmt.Errorf("no tools files found in folder %q", folder))
+	}
+}
+
+// origin returns the file a resource was loaded from, or "" if it was not
+// loaded.
+func (l *toolsFileLoader) origin(resource, name string) string {
+	return l.origins[resource][name].path
+}
+
+// resourceOrigin is the section of a tools file a resource is defined in.
+type resourceOrigin struct {
+	path    string
+	section string
+}
+
+// load reads a tools file and the files it includes. Files that were already
+// loaded are skipped, so that a file can be included several times. Errors
+// are recorded, and the rest of the file is loaded when possible.
+func (l *toolsFileLoader) load(path string) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("invalid tool file path %q: %w", path, err))
+		return
+	}
+	if l.loaded[abs] {
+		return
+	}
+	l.loaded[abs] = true
+	l.files = append(l.files, path)
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("unable to read tool file at %q: %w", path, err))
+		return
+	}
+	toolsFile, err := parseToolsFile(l.ctx, buf)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("unable to parse tool file at %q: %w", path, err))
+		return
+	}
+	if toolsFile.AuthSources != nil {
+		l.logger.WarnContext(l.ctx, "`authSources` is deprecated, use `authServices` instead")
+	}
+
+	mergeConfigs(l, server.ResourceSource, &l.merged.Sources, toolsFile.Sources, path, "sources")
+	mergeConfigs(l, server.ResourceAuthService, &l.merged.AuthServices, toolsFile.AuthSources, path, "authSources")
+	mergeConfigs(l, server.ResourceAuthService, &l.merged.AuthServices, toolsFile.AuthServices, path, "authServices")
+	mergeConfigs(l, server.ResourceTool, &l.merged.Tools, toolsFile.Tools, path, "tools")
+	mergeConfigs(l, server.ResourceToolset, &l.merged.Toolsets, toolsFile.Toolsets, path, "toolsets")
+	mergeConfigs(l, server.ResourcePrompt, &l.merged.Prompts, toolsFile.Prompts, path, "prompts")
+
+	// includes are relative to the file including them
+	for _, include := range toolsFile.Include {
+		pattern := include
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(path), pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("invalid include %q in %q: %w", include, path, err))
+			continue
+		}
+		if len(matches) == 0 {
+			l.errs = append(l.errs, fmt.Errorf("include %q in %q does not match any file", include, path))
+			continue
+		}
+		for _, m := range matches {
+			l.load(m)
+		}
+	}
+}
+
+// mergeConfigs adds the configs of a section of the tools file at path to dst.
+// An error naming both files, or both sections of the same file, is recorded
+// for each config whose name was already loaded.
+func mergeConfigs[M ~map[string]V, V any](l *toolsFileLoader, kind string, dst *M, src M, path, section string) {
+	origins, ok := l.origins[kind]
+	if !ok {
+		origins = make(map[string]resourceOrigin)
+		l.origins[kind] = origins
+	}
+	for _, name := range slices.Sorted(maps.Keys(src)) {
+		if origin, ok := origins[name]; ok {
+			if origin.path == path {
+				l.errs = append(l.errs, fmt.Errorf("%s %q is defined in both the %q and %q sections of %q", kind, name, origin.section, section, path))
+			} else {
+				l.errs = append(l.errs, fmt.Errorf("%s %q is defined in both %q and %q", kind, name, origin.path, path))
+			}
+			continue
+		}
+		origins[name] = resourceOrigin{path: path, section: section}
+		if *dst == nil {
+			*dst = make(M)
+		}
+		(*dst)[name] = src[name]
+	}
+}
+
+// toolsFilePaths returns the tools files to load from the --tools-file flag of
+// flags. The default tools file is not used when only a tools folder is
+// passed.
+func toolsFilePaths(flags *pflag.FlagSet, files []string, folder string) []string {
+	if folder != "" && !flags.Changed("tools-file") && !flags.Changed("tools_file") {
+		return nil
+	}
+	return files
+}
+
+// setToolsFileConfigs sets the resource configs of cfg to the ones of a tools
+// file.
+func setToolsFileConfigs(cfg *server.ServerConfig, toolsFile ToolsFile) {
+	cfg.SourceConfigs = toolsFile.Sources
+	cfg.AuthServiceConfigs = toolsFile.AuthServices
+	cfg.ToolConfigs = toolsFile.Tools
+	cfg.ToolsetConfigs = toolsFile.Toolsets
+	cfg.PromptConfigs = toolsFile.Prompts
+}
+
+// reloadDelay is the time waited after a tools file changes before reloading
+// it, as saving a file often produces several events.
+const reloadDelay = 100 * time.Millisecond
+
+// watchToolsFiles reloads the tools files whenever one of them changes, a YAML
+// file is added to or removed from the tools folder, or SIGHUP is received,
+// until ctx is done. files are the paths of the files currently loaded. If the
+// new configuration is invalid, the server keeps the current one.
+func watchToolsFiles(ctx context.Context, cmd *Command, s *server.Server, files []string) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var watcher *fsnotify.Watcher
+	var events <-chan fsnotify.Event
+	var watchErrs <-chan error
+	if !cmd.disableReload {
+		var err error
+		watcher, err = fsnotify.NewWatcher()
+		if err != nil {
+			cmd.logger.WarnContext(ctx, fmt.Sprintf("unable to watch tools files: %s", err))
+		} else {
+			defer watcher.Close()
+			events, watchErrs = watcher.Events, watcher.Errors
+		}
+	}
+
+	var folder string
+	if cmd.tools_folder != "" {
+		folder, _ = filepath.Abs(cmd.tools_folder)
+	}
+	// the directories of the files are watched, as editors often replace
+	// files rather than writing to them
+	watchedDirs := make(map[string]bool)
+	tracked := make(map[string]bool)
+	watch := func(files []string) {
+		if watcher == nil {
+			return
+		}
+		clear(tracked)
+		dirs := []string{}
+		if folder != "" {
+			dirs = append(dirs, folder)
+		}
+		for _, f := range files {
+			abs, err := filepath.Abs(f)
+			if err != nil {
+				continue
+			}
+			tracked[abs] = true
+			dirs = append(dirs, filepath.Dir(abs))
+		}
+		for _, dir := range dirs {
+			if watchedDirs[dir] {
+				continue
+			}
+			if err := watcher.Add(dir); err != nil {
+				cmd.logger.WarnContext(ctx, fmt.Sprintf("unable to watch %q: %s", dir, err))
+				continue
+			}
+			watchedDirs[dir] = true
+		}
+	}
+	watch(files)
+
+	reload := func() {
+		toolsFile, files, err := loadToolsFiles(ctx, cmd.logger, cmd.toolsFilePaths(), cmd.tools_folder)
+		if err == nil {
+			// watch the files included since the last reload
+			watch(files)
+			cfg := cmd.cfg
+			setToolsFileConfigs(&cfg, toolsFile)
+			err = s.Reload(ctx, cfg)
+		}
+		if err != nil {
+			cmd.logger.ErrorContext(ctx, fmt.Sprintf("unable to reload tools files, keeping the current configuration: %s", err))
+		}
+	}
+
+	timer := time.NewTimer(reloadDelay)
+	timer.Stop()
+	defer timer.Stop()
+	var changed string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			cmd.logger.InfoContext(ctx, "Received SIGHUP signal to reload the tools files.")
+			reload()
+		case e, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			name := filepath.Clean(e.Name)
+			inFolder := folder != "" && filepath.Dir(name) == folder && isYAMLFile(name)
+			if tracked[name] || inFolder {
+				changed = name
+				timer.Reset(reloadDelay)
+			}
+		case err, ok := <-watchErrs:
+			if !ok {
+				watchErrs = nil
+				continue
+			}
+			cmd.logger.WarnContext(ctx, fmt.Sprintf("error watching tools files: %s", err))
+		case <-timer.C:
+			cmd.logger.InfoContext(ctx, fmt.Sprintf("Tools file %q changed, reloading.", changed))
+			reload()
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+// writeToolsFiles writes files, mapping paths relative to dir to their YAML
+// contents.
+func writeToolsFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("unable to create directory: %s", err)
+		}
+		if err := os.WriteFile(path, testutils.FormatYaml(content), 0o644); err != nil {
+			t.Fatalf("unable to write %q: %s", name, err)
+		}
+	}
+}
+
+const sourceYaml = `
+sources:
+	my-sqlite:
+		kind: sqlite
+		database: my.db
+`
+
+func toolYaml(name string) string {
+	return `
+tools:
+	` + name + `:
+		kind: sqlite-sql
+		source: my-sqlite
+		description: some description
+		statement: SELECT 1;
+`
+}
+
+func TestLoadToolsFiles(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	logger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	dir := t.TempDir()
+	writeToolsFiles(t, dir, map[string]string{
+		"main.yaml": `
+include:
+	- teams/*.yaml
+` + sourceYaml,
+		"teams/a.yaml": toolYaml("a_tool") + `
+toolsets:
+	team_a:
+		- a_tool
+`,
+		// files included several times are only loaded once
+		"teams/b.yaml": `
+include:
+	- ../main.yaml
+` + toolYaml("b_tool"),
+		"extra.yaml":               toolYaml("extra_tool"),
+		"folder/c.yaml":            toolYaml("c_tool"),
+		"folder/d.yml":             toolYaml("d_tool"),
+		"folder/ignored.txt":       toolYaml("ignored_tool"),
+		"folder/nested/other.yaml": toolYaml("nested_tool"),
+	})
+
+	tcs := []struct {
+		desc      string
+		paths     []string
+		folder    string
+		wantTools []string
+		wantFiles []string
+	}{
+		{
+			desc:      "includes",
+			paths:     []string{filepath.Join(dir, "main.yaml")},
+			wantTools: []string{"a_tool", "b_tool"},
+			wantFiles: []string{"main.yaml", "teams/a.yaml", "teams/b.yaml"},
+		},
+		{
+			desc:      "multiple files",
+			paths:     []string{filepath.Join(dir, "main.yaml"), filepath.Join(dir, "extra.yaml")},
+			wantTools: []string{"a_tool", "b_tool", "extra_tool"},
+			wantFiles: []string{"main.yaml", "teams/a.yaml", "teams/b.yaml", "extra.yaml"},
+		},
+		{
+			desc:      "folder",
+			paths:     []string{filepath.Join(dir, "main.yaml")},
+			folder:    filepath.Join(dir, "folder"),
+			wantTools: []string{"a_tool", "b_tool", "c_tool", "d_tool"},
+			wantFiles: []string{"main.yaml", "teams/a.yaml", "teams/b.yaml", "folder/c.yaml", "folder/d.yml"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			toolsFile, files, err := loadToolsFiles(ctx, logger, tc.paths, tc.folder)
+			if err != nil {
+				t.Fatalf("unable to load tools files: %s", err)
+			}
+			if diff := cmp.Diff(tc.wantTools, slices.Sorted(maps.Keys(toolsFile.Tools))); diff != "" {
+				t.Fatalf("incorrect tools: diff %v", diff)
+			}
+			if _, ok := toolsFile.Sources["my-sqlite"]; !ok {
+				t.Fatalf("missing included source")
+			}
+			if _, ok := toolsFile.Toolsets["team_a"]; !ok {
+				t.Fatalf("missing included toolset")
+			}
+			gotFiles := make([]string, 0, len(files))
+			for _, f := range files {
+				rel, err := filepath.Rel(dir, f)
+				if err != nil {
+					t.Fatalf("unexpected file path %q: %s", f, err)
+				}
+				gotFiles = append(gotFiles, filepath.ToSlash(rel))
+			}
+			if diff := cmp.Diff(tc.wantFiles, gotFiles); diff != "" {
+				t.Fatalf("incorrect files: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestLoadToolsFilesErrors(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	logger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	dir := t.TempDir()
+	writeToolsFiles(t, dir, map[string]string{
+		"first.yaml":      sourceYaml + toolYaml("my_tool"),
+		"second.yaml":     toolYaml("my_tool"),
+		"source.yaml":     sourceYaml,
+		"include.yaml":    "include:\n\t- missing/*.yaml\n",
+		"auth.yaml":       "authServices:\n\tmy-auth:\n\t\tkind: google\n\t\tclientId: id\n",
+		"authSource.yaml": "authSources:\n\tmy-auth:\n\t\tkind: google\n\t\tclientId: id\n",
+		"bothAuth.yaml":   "authSources:\n\tmy-auth:\n\t\tkind: google\n\t\tclientId: id\nauthServices:\n\tmy-auth:\n\t\tkind: google\n\t\tclientId: id\n",
+		"empty/README.md": "nothing to load",
+	})
+
+	tcs := []struct {
+		desc   string
+		paths  []string
+		folder string
+		want   []string
+	}{
+		{
+			desc:  "duplicate tool",
+			paths: []string{"first.yaml", "second.yaml"},
+			want:  []string{`tool "my_tool" is defined in both`, "first.yaml", "second.yaml"},
+		},
+		{
+			desc:  "duplicate source",
+			paths: []string{"first.yaml", "source.yaml"},
+			want:  []string{`source "my-sqlite" is defined in both`, "first.yaml", "source.yaml"},
+		},
+		{
+			desc:  "duplicate auth service",
+			paths: []string{"auth.yaml", "authSource.yaml"},
+			want:  []string{`authService "my-auth" is defined in both`, "auth.yaml", "authSource.yaml"},
+		},
+		{
+			desc:  "duplicate auth service in the same file",
+			paths: []string{"bothAuth.yaml"},
+			want:  []string{`authService "my-auth" is defined in both the "authSources" and "authServices" sections of`, "bothAuth.yaml"},
+		},
+		{
+			desc:  "unmatched include",
+			paths: []string{"include.yaml"},
+			want:  []string{`include "missing/*.yaml"`, "does not match any file"},
+		},
+		{
+			desc:  "errors of every file",
+			paths: []string{"include.yaml", "first.yaml", "second.yaml"},
+			want:  []string{`include "missing/*.yaml"`, `tool "my_tool" is defined in both`},
+		},
+		{
+			desc:   "empty folder",
+			folder: "empty",
+			want:   []string{"no tools files found in folder"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			paths := make([]string, 0, len(tc.paths))
+			for _, p := range tc.paths {
+				paths = append(paths, filepath.Join(dir, p))
+			}
+			folder := tc.folder
+			if folder != "" {
+				folder = filepath.Join(dir, folder)
+			}
+			_, _, err := loadToolsFiles(ctx, logger, paths, folder)
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("error %q does not contain %q", err, want)
+				}
+			}
+		})
+	}
+}
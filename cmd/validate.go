@@ -0,0 +1,154 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// errInvalidConfig is returned by the validate command when errors are found.
+var errInvalidConfig = errors.New("invalid configuration")
+
+// validateIssue is a server.ConfigIssue along with the file it was found in.
+type validateIssue struct {
+	server.ConfigIssue
+	File string `json:"file,omitempty"`
+}
+
+// validateResult is the output of the validate command in JSON format.
+type validateResult struct {
+	Valid    bool            `json:"valid"`
+	Errors   int             `json:"errors"`
+	Warnings int             `json:"warnings"`
+	Files    []string        `json:"files"`
+	Issues   []validateIssue `json:"issues"`
+}
+
+// newValidateCommand returns the validate subcommand, which checks tools files
+// without connecting to any source.
+func newValidateCommand() *cobra.Command {
+	var files []string
+	var folder, format string
+	c := &cobra.Command{
+		Use:   "validate",
+		Short: "Check tools files without connecting to any source",
+		Long: `Check tools files without connecting to any source.
+
+Every problem found is reported: files that cannot be loaded, invalid names,
+references to sources, tools or auth services that do not exist, tools used
+with an incompatible source, invalid tool configs such as statement templates
+that cannot be parsed, and sources or auth services that no tool uses. The
+files that load are checked even if others do not. The command fails if any
+error is found. Unused resources are only reported as warnings.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+	}
+	flags := c.Flags()
+	flags.StringArrayVar(&files, "tools-file", []string{"tools.yaml"}, "File path specifying the tool configuration. Can be repeated to merge several files.")
+	flags.StringVar(&folder, "tools-folder", "", "Folder whose YAML files are merged into the tool configuration. Only the files passed with --tools-file are loaded in addition to them.")
+	flags.StringVar(&format, "format", "text", "Output format. Allowed: 'text' or 'json'.")
+
+	c.RunE = func(c *cobra.Command, _ []string) error {
+		err := runValidate(c, files, folder, format)
+		// errors are silenced by the root command
+		if err != nil && !errors.Is(err, errInvalidConfig) {
+			fmt.Fprintln(c.ErrOrStderr(), err)
+		}
+		return err
+	}
+	return c
+}
+
+// runValidate validates the tools files and writes the issues found to the
+// output of c in format.
+func runValidate(c *cobra.Command, files []string, folder, format string) error {
+	if format != "text" && format != "json" {
+		return fmt.Errorf("invalid format %q: must be 'text' or 'json'", format)
+	}
+	logger, err := log.NewStdLogger(c.ErrOrStderr(), c.ErrOrStderr(), "warn")
+	if err != nil {
+		return fmt.Errorf("unable to initialize logger: %w", err)
+	}
+	ctx := util.WithLogger(c.Context(), logger)
+
+	res := validateResult{Files: []string{}, Issues: []validateIssue{}}
+	loader := newToolsFileLoader(ctx, logger)
+	// the files that loaded are still validated when others fail to load
+	_ = loader.loadAll(toolsFilePaths(c.Flags(), files, folder), folder)
+	for _, err := range loader.errs {
+		res.Issues = append(res.Issues, validateIssue{ConfigIssue: server.ConfigIssue{Severity: server.SeverityError, Message: err.Error()}})
+	}
+	var cfg server.ServerConfig
+	setToolsFileConfigs(&cfg, loader.merged)
+	for _, issue := range server.ValidateConfigs(cfg) {
+		res.Issues = append(res.Issues, validateIssue{ConfigIssue: issue, File: loader.origin(issue.Resource, issue.Name)})
+	}
+	res.Files = append(res.Files, loader.files...)
+	for _, issue := range res.Issues {
+		if issue.Severity == server.SeverityError {
+			res.Errors++
+		} else {
+			res.Warnings++
+		}
+	}
+	res.Valid = res.Errors == 0
+
+	if format == "json" {
+		err = writeValidateJSON(c.OutOrStdout(), res)
+	} else {
+		err = writeValidateText(c.OutOrStdout(), res)
+	}
+	if err != nil {
+		return err
+	}
+	if !res.Valid {
+		return errInvalidConfig
+	}
+	return nil
+}
+
+func writeValidateJSON(w io.Writer, res validateResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(res); err != nil {
+		return fmt.Errorf("unable to write result: %w", err)
+	}
+	return nil
+}
+
+func writeValidateText(w io.Writer, res validateResult) error {
+	for _, issue := range res.Issues {
+		line := issue.String()
+		if issue.File != "" {
+			line = issue.File + ": " + line
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("unable to write result: %w", err)
+		}
+	}
+	summary := fmt.Sprintf("Checked %d file(s): %d error(s), %d warning(s).", len(res.Files), res.Errors, res.Warnings)
+	if _, err := fmt.Fprintln(w, summary); err != nil {
+		return fmt.Errorf("unable to write result: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+)
+
+func TestValidateCommand(t *testing.T) {
+	dir := t.TempDir()
+	writeToolsFiles(t, dir, map[string]string{
+		"valid.yaml": sourceYaml + toolYaml("my_tool"),
+		"unused.yaml": sourceYaml + `
+authServices:
+	my-auth:
+		kind: google
+		clientId: id
+`,
+		"invalid.yaml": sourceYaml + toolYaml("my_tool") + `
+toolsets:
+	my-toolset:
+		- my_tool
+		- missing_tool
+`,
+		"broken.yaml": "sources: [",
+		"template.yaml": sourceYaml + `
+tools:
+	my_tool:
+		kind: sqlite-sql
+		source: my-sqlite
+		description: some description
+		statement: SELECT * FROM {{.table}
+		parameters:
+			- name: table
+				type: identifier
+				description: the table
+				enum: [users]
+`,
+	})
+
+	tcs := []struct {
+		desc    string
+		files   []string
+		wantErr bool
+		want    []string
+	}{
+		{
+			desc:  "valid",
+			files: []string{"valid.yaml"},
+			want:  []string{"Checked 1 file(s): 0 error(s), 0 warning(s)."},
+		},
+		{
+			desc:  "warnings only",
+			files: []string{"unused.yaml"},
+			want: []string{
+				`unused.yaml: warning: source "my-sqlite": not used by any tool`,
+				`unused.yaml: warning: authService "my-auth": not used by any tool`,
+				"Checked 1 file(s): 0 error(s), 2 warning(s).",
+			},
+		},
+		{
+			desc:    "errors",
+			files:   []string{"invalid.yaml"},
+			wantErr: true,
+			want: []string{
+				`invalid.yaml: error: toolset "my-toolset": tool "missing_tool" does not exist`,
+				"Checked 1 file(s): 1 error(s), 0 warning(s).",
+			},
+		},
+		{
+			desc:    "unparsable file",
+			files:   []string{"broken.yaml"},
+			wantErr: true,
+			want:    []string{"error: unable to parse tool file at", "Checked 1 file(s): 1 error(s), 0 warning(s)."},
+		},
+		{
+			desc:    "unparsable file among others",
+			files:   []string{"broken.yaml", "unused.yaml"},
+			wantErr: true,
+			want: []string{
+				"error: unable to parse tool file at",
+				`unused.yaml: warning: source "my-sqlite": not used by any tool`,
+				"Checked 2 file(s): 1 error(s), 2 warning(s).",
+			},
+		},
+		{
+			desc:    "invalid statement template",
+			files:   []string{"template.yaml"},
+			wantErr: true,
+			want: []string{
+				`template.yaml: error: tool "my_tool": unable to parse statement template`,
+				"Checked 1 file(s): 1 error(s), 0 warning(s).",
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			args := []string{"validate"}
+			for _, f := range tc.files {
+				args = append(args, "--tools-file", filepath.Join(dir, f))
+			}
+			_, output, err := invokeCommand(args)
+			if tc.wantErr != errors.Is(err, errInvalidConfig) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(output, want) {
+					t.Fatalf("output %q does not contain %q", output, want)
+				}
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.yaml")
+		_, output, err := invokeCommand([]string{"validate", "--tools-file", path, "--format", "json"})
+		if !errors.Is(err, errInvalidConfig) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got validateResult
+		if err := json.Unmarshal([]byte(output), &got); err != nil {
+			t.Fatalf("unable to parse output %q: %s", output, err)
+		}
+		want := validateResult{
+			Valid:  false,
+			Errors: 1,
+			Files:  []string{path},
+			Issues: []validateIssue{{
+				ConfigIssue: server.ConfigIssue{
+					Severity: server.SeverityError,
+					Resource: server.ResourceToolset,
+					Name:     "my-toolset",
+					Message:  `tool "missing_tool" does not exist`,
+				},
+				File: path,
+			}},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("incorrect result: diff %v", diff)
+		}
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, _, err := invokeCommand([]string{"validate", "--tools-file", filepath.Join(dir, "valid.yaml"), "--format", "xml"})
+		if err == nil || errors.Is(err, errInvalidConfig) {
+			t.Fatalf("expected invalid format error, got %v", err)
+		}
+	})
+}
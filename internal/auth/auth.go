@@ -17,6 +17,9 @@ package auth
 import (
 	"context"
 	"net/http"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/registry"
 )
 
 // AuthServiceConfig is the interface for configuring authentication services.
@@ -31,3 +34,32 @@ type AuthService interface {
 	GetName() string
 	GetClaimsFromHeader(context.Context, http.Header) (map[string]any, error)
 }
+
+// AuthServiceConfigFactory decodes the config of the auth service named name
+// from decoder. It is registered for a kind of auth service with Register.
+type AuthServiceConfigFactory = registry.Factory[AuthServiceConfig]
+
+var kinds = registry.New[AuthServiceConfig]("auth source")
+
+// Register makes a kind of auth service available in tools files. It is
+// usually called from the init function of the package implementing the auth
+// service. It returns false if the kind is already registered.
+func Register(kind string, factory AuthServiceConfigFactory) bool {
+	return kinds.Register(kind, factory)
+}
+
+// MustRegister is like Register, but panics if the kind is already registered.
+func MustRegister(kind string, factory AuthServiceConfigFactory) {
+	kinds.MustRegister(kind, factory)
+}
+
+// DecodeConfig decodes the config of the auth service named name using the
+// factory registered for kind.
+func DecodeConfig(ctx context.Context, kind, name string, decoder *yaml.Decoder) (AuthServiceConfig, error) {
+	return kinds.DecodeConfig(ctx, kind, name, decoder)
+}
+
+// Kinds returns the registered kinds of auth services, sorted.
+func Kinds() []string {
+	return kinds.Kinds()
+}
@@ -19,12 +19,25 @@ import (
 	"fmt"
 	"net/http"
 
+	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/auth"
 	"google.golang.org/api/idtoken"
 )
 
 const AuthServiceKind string = "google"
 
+func init() {
+	auth.MustRegister(AuthServiceKind, newConfig)
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (auth.AuthServiceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
 // validate interface
 var _ auth.AuthServiceConfig = Config{}
 
@@ -0,0 +1,247 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonschema derives JSON Schemas from the `yaml` and `validate` struct
+// tags of configuration types, so that tools files can be checked by editors,
+// and validates values against the schemas configured by users.
+package jsonschema
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Draft is the version of JSON Schema generated.
+const Draft = "http://json-schema.org/draft-07/schema#"
+
+// Schemer is implemented by types describing their own schema, such as types
+// with a custom YAML decoding.
+type Schemer interface {
+	JSONSchema(r *Reflector) map[string]any
+}
+
+var schemerType = reflect.TypeOf((*Schemer)(nil)).Elem()
+
+// Reflector generates the schemas of Go types. Schemas shared by several types
+// are collected as definitions.
+type Reflector struct {
+	definitions map[string]any
+}
+
+// NewReflector returns a Reflector without any definition.
+func NewReflector() *Reflector {
+	return &Reflector{definitions: make(map[string]any)}
+}
+
+// Generate returns the schema of a document decoded into v.
+func Generate(v any) map[string]any {
+	r := NewReflector()
+	s := r.Reflect(v)
+	s["$schema"] = Draft
+	if len(r.definitions) > 0 {
+		s["definitions"] = r.definitions
+	}
+	return s
+}
+
+// Define returns a reference to the definition named name, built with build
+// the first time it is referred to. build may refer to the definition itself.
+func (r *Reflector) Define(name string, build func(*Reflector) map[string]any) map[string]any {
+	if _, ok := r.definitions[name]; !ok {
+		// reserve the name so that recursive definitions terminate
+		r.definitions[name] = map[string]any{}
+		r.definitions[name] = build(r)
+	}
+	return map[string]any{"$ref": "#/definitions/" + name}
+}
+
+// Reflect returns the schema of the type of v. The non-zero fields of v are
+// the defaults of the properties, which are not required.
+func (r *Reflector) Reflect(v any) map[string]any {
+	if v == nil {
+		return map[string]any{}
+	}
+	return r.reflectValue(reflect.ValueOf(v))
+}
+
+func (r *Reflector) reflectValue(v reflect.Value) map[string]any {
+	t := v.Type()
+	if t.Implements(schemerType) {
+		if t.Kind() == reflect.Pointer && v.IsNil() {
+			v = reflect.New(t.Elem())
+		}
+		return v.Interface().(Schemer).JSONSchema(r)
+	}
+	if reflect.PointerTo(t).Implements(schemerType) {
+		p := reflect.New(t)
+		p.Elem().Set(v)
+		return p.Interface().(Schemer).JSONSchema(r)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return r.reflectValue(reflect.New(t.Elem()).Elem())
+		}
+		return r.reflectValue(v.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": r.reflectValue(reflect.New(t.Elem()).Elem())}
+	case reflect.Map:
+		s := map[string]any{"type": "object"}
+		if t.Elem().Kind() != reflect.Interface {
+			s["additionalProperties"] = r.reflectValue(reflect.New(t.Elem()).Elem())
+		}
+		return s
+	case reflect.Struct:
+		properties := make(map[string]any)
+		required := make([]string, 0)
+		r.reflectFields(v, properties, &required)
+		s := map[string]any{
+			"type":       "object",
+			"properties": properties,
+			// tools files are decoded strictly
+			"additionalProperties": false,
+		}
+		if len(required) > 0 {
+			s["required"] = required
+		}
+		return s
+	}
+	// interfaces accept any value
+	return map[string]any{}
+}
+
+// reflectFields adds the fields of the struct v to properties, along with the
+// fields of the structs it inlines.
+func (r *Reflector) reflectFields(v reflect.Value, properties map[string]any, required *[]string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, inline, skip := fieldName(field)
+		if skip {
+			continue
+		}
+		fv := v.Field(i)
+		if inline {
+			if fv.Kind() == reflect.Pointer {
+				fv = reflect.New(field.Type.Elem()).Elem()
+			}
+			r.reflectFields(fv, properties, required)
+			continue
+		}
+		s := r.reflectValue(fv)
+		hasDefault := false
+		if !fv.IsZero() && isScalar(fv.Kind()) {
+			s["default"] = fv.Interface()
+			hasDefault = true
+		}
+		if applyValidation(s, field.Tag.Get("validate"), fv.Kind()) && !hasDefault {
+			*required = append(*required, name)
+		}
+		properties[name] = s
+	}
+}
+
+// fieldName returns the key of a field in YAML, as decoded by goccy/go-yaml.
+func fieldName(field reflect.StructField) (name string, inline, skip bool) {
+	tag := field.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false, true
+	}
+	opts := strings.Split(tag, ",")
+	for _, o := range opts[1:] {
+		if o == "inline" {
+			return "", true, false
+		}
+	}
+	if opts[0] != "" {
+		return opts[0], false, false
+	}
+	if field.Anonymous && field.Type.Kind() == reflect.Struct {
+		return "", true, false
+	}
+	return strings.ToLower(field.Name), false, false
+}
+
+// applyValidation adds the constraints of a validate tag to s and reports
+// whether the field is required. The constraints after "dive" apply to the
+// elements of the field and are ignored.
+func applyValidation(s map[string]any, tag string, kind reflect.Kind) (required bool) {
+	if tag == "" {
+		return false
+	}
+	for _, rule := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "dive":
+			return required
+		case "required":
+			required = true
+		case "min", "max":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			s[boundKeyword(key, kind)] = n
+		case "oneof":
+			s["enum"] = strings.Fields(value)
+		}
+	}
+	return required
+}
+
+// boundKeyword returns the JSON Schema keyword of a min or max validation for
+// a field of kind.
+func boundKeyword(key string, kind reflect.Kind) string {
+	var suffix string
+	switch kind {
+	case reflect.String:
+		suffix = "Length"
+	case reflect.Slice, reflect.Array:
+		suffix = "Items"
+	case reflect.Map:
+		suffix = "Properties"
+	default:
+		if key == "min" {
+			return "minimum"
+		}
+		return "maximum"
+	}
+	return key + suffix
+}
+
+func isScalar(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/jsonschema"
+)
+
+type color string
+
+func (color) JSONSchema(*jsonschema.Reflector) map[string]any {
+	return map[string]any{"type": "string", "enum": []string{"red", "blue"}}
+}
+
+type node struct {
+	Value    string `yaml:"value" validate:"required"`
+	Children []node `yaml:"children"`
+}
+
+func (node) JSONSchema(r *jsonschema.Reflector) map[string]any {
+	return r.Define("node", func(r *jsonschema.Reflector) map[string]any {
+		type plain node
+		return r.Reflect(plain{})
+	})
+}
+
+type Common struct {
+	Name string `yaml:"name" validate:"required"`
+}
+
+type config struct {
+	Common   `yaml:",inline"`
+	Host     string            `yaml:"host" validate:"required"`
+	Port     int               `yaml:"port" validate:"required"`
+	Timeout  string            `yaml:"timeout"`
+	Tags     []string          `yaml:"tags" validate:"required,min=1,dive"`
+	Labels   map[string]string `yaml:"labels"`
+	Extra    map[string]any    `yaml:"extra"`
+	Color    color             `yaml:"color"`
+	Tree     *node             `yaml:"tree"`
+	Verbose  bool
+	Ignored  string `yaml:"-"`
+	internal string
+}
+
+func TestGenerate(t *testing.T) {
+	got := jsonschema.Generate(config{Port: 5432, Timeout: "30s"})
+	want := map[string]any{
+		"$schema": jsonschema.Draft,
+		"type":    "object",
+		"properties": map[string]any{
+			"name":    map[string]any{"type": "string"},
+			"host":    map[string]any{"type": "string"},
+			"port":    map[string]any{"type": "integer", "default": 5432},
+			"timeout": map[string]any{"type": "string", "default": "30s"},
+			"tags":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "minItems": float64(1)},
+			"labels":  map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+			"extra":   map[string]any{"type": "object"},
+			"color":   map[string]any{"type": "string", "enum": []string{"red", "blue"}},
+			"tree":    map[string]any{"$ref": "#/definitions/node"},
+			"verbose": map[string]any{"type": "boolean"},
+		},
+		"required":             []string{"name", "host", "tags"},
+		"additionalProperties": false,
+		"definitions": map[string]any{
+			"node": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"value":    map[string]any{"type": "string"},
+					"children": map[string]any{"type": "array", "items": map[string]any{"$ref": "#/definitions/node"}},
+				},
+				"required":             []string{"value"},
+				"additionalProperties": false,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect schema: diff %v", diff)
+	}
+}
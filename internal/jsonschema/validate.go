@@ -0,0 +1,471 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// annotations are the keywords accepted in a schema without constraining the
+// values.
+var annotations = []string{
+	"$schema", "$id", "$comment", "title", "description", "default", "examples",
+	"format", "readOnly", "writeOnly", "deprecated",
+}
+
+var typeNames = []string{"null", "boolean", "object", "array", "number", "integer", "string"}
+
+// Schema is a compiled JSON Schema, validating values. Only the keywords
+// describing plain JSON values are supported; references are not.
+type Schema struct {
+	types                []string
+	enum                 []any
+	constant             *any
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	noAdditional         bool
+	items                *Schema
+	minItems, maxItems   *int
+	minLength, maxLength *int
+	minimum, maximum     *float64
+	exclusiveMinimum     *float64
+	exclusiveMaximum     *float64
+	pattern              *regexp.Regexp
+	allOf, anyOf, oneOf  []*Schema
+	not                  *Schema
+}
+
+// Compile returns the compiled schema s, as decoded from JSON or YAML. It
+// reports keywords that are unknown or have invalid values.
+func Compile(s map[string]any) (*Schema, error) {
+	return compile(s, "")
+}
+
+func compile(s map[string]any, path string) (*Schema, error) {
+	c := &Schema{}
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := c.compileKeyword(k, s[k], path+"/"+k); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *Schema) compileKeyword(k string, v any, path string) error {
+	var err error
+	switch k {
+	case "type":
+		c.types, err = compileTypes(v)
+	case "enum":
+		var ok bool
+		if c.enum, ok = v.([]any); !ok {
+			err = fmt.Errorf("must be an array")
+		}
+	case "const":
+		c.constant = &v
+	case "properties":
+		c.properties, err = compileSchemaMap(v, path)
+	case "required":
+		c.required, err = compileStrings(v)
+	case "additionalProperties":
+		if b, ok := v.(bool); ok {
+			c.noAdditional = !b
+			break
+		}
+		c.additionalProperties, err = compileSchema(v, path)
+	case "items":
+		c.items, err = compileSchema(v, path)
+	case "minItems":
+		c.minItems, err = compileCount(v)
+	case "maxItems":
+		c.maxItems, err = compileCount(v)
+	case "minLength":
+		c.minLength, err = compileCount(v)
+	case "maxLength":
+		c.maxLength, err = compileCount(v)
+	case "minimum":
+		c.minimum, err = compileNumber(v)
+	case "maximum":
+		c.maximum, err = compileNumber(v)
+	case "exclusiveMinimum":
+		c.exclusiveMinimum, err = compileNumber(v)
+	case "exclusiveMaximum":
+		c.exclusiveMaximum, err = compileNumber(v)
+	case "pattern":
+		p, ok := v.(string)
+		if !ok {
+			err = fmt.Errorf("must be a string")
+			break
+		}
+		c.pattern, err = regexp.Compile(p)
+	case "allOf":
+		c.allOf, err = compileSchemaList(v, path)
+	case "anyOf":
+		c.anyOf, err = compileSchemaList(v, path)
+	case "oneOf":
+		c.oneOf, err = compileSchemaList(v, path)
+	case "not":
+		c.not, err = compileSchema(v, path)
+	default:
+		if !slices.Contains(annotations, k) {
+			err = fmt.Errorf("unsupported keyword")
+		}
+	}
+	if err != nil {
+		// errors of nested schemas already name their keyword
+		if _, ok := err.(*SchemaError); ok {
+			return err
+		}
+		return &SchemaError{Path: path, Err: err}
+	}
+	return nil
+}
+
+// SchemaError reports an invalid keyword of a schema.
+type SchemaError struct {
+	// Path is the JSON pointer of the keyword in the schema.
+	Path string
+	Err  error
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err)
+}
+
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
+
+func compileSchema(v any, path string) (*Schema, error) {
+	s, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("must be a schema object")
+	}
+	return compile(s, path)
+}
+
+func compileSchemaMap(v any, path string) (map[string]*Schema, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("must be an object")
+	}
+	schemas := make(map[string]*Schema, len(m))
+	for name, s := range m {
+		c, err := compileSchema(s, path+"/"+name)
+		if err != nil {
+			return nil, err
+		}
+		schemas[name] = c
+	}
+	return schemas, nil
+}
+
+func compileSchemaList(v any, path string) ([]*Schema, error) {
+	l, ok := v.([]any)
+	if !ok || len(l) == 0 {
+		return nil, fmt.Errorf("must be a non-empty array")
+	}
+	schemas := make([]*Schema, len(l))
+	for i, s := range l {
+		c, err := compileSchema(s, path+"/"+strconv.Itoa(i))
+		if err != nil {
+			return nil, err
+		}
+		schemas[i] = c
+	}
+	return schemas, nil
+}
+
+func compileTypes(v any) ([]string, error) {
+	var types []string
+	switch v := v.(type) {
+	case string:
+		types = []string{v}
+	case []any:
+		var err error
+		if types, err = compileStrings(v); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("must be a string or an array of strings")
+	}
+	for _, t := range types {
+		if !slices.Contains(typeNames, t) {
+			return nil, fmt.Errorf("unknown type %q", t)
+		}
+	}
+	return types, nil
+}
+
+func compileStrings(v any) ([]string, error) {
+	l, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("must be an array of strings")
+	}
+	strs := make([]string, len(l))
+	for i, s := range l {
+		if strs[i], ok = s.(string); !ok {
+			return nil, fmt.Errorf("must be an array of strings")
+		}
+	}
+	return strs, nil
+}
+
+func compileNumber(v any) (*float64, error) {
+	f, ok := toFloat(v)
+	if !ok {
+		return nil, fmt.Errorf("must be a number")
+	}
+	return &f, nil
+}
+
+func compileCount(v any) (*int, error) {
+	f, ok := toFloat(v)
+	if !ok || f < 0 || f != math.Trunc(f) {
+		return nil, fmt.Errorf("must be a non-negative integer")
+	}
+	n := int(f)
+	return &n, nil
+}
+
+// toFloat returns the value of the numbers decoded from JSON or YAML.
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// ValidationError reports a value not matching a schema.
+type ValidationError struct {
+	// Path is the JSON pointer of the invalid value.
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Validate reports whether the JSON encoding of v matches the schema.
+func (c *Schema) Validate(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var doc any
+	if err := d.Decode(&doc); err != nil {
+		return err
+	}
+	return c.validate(doc, "")
+}
+
+func (c *Schema) validate(v any, path string) error {
+	fail := func(format string, args ...any) error {
+		return &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)}
+	}
+	if len(c.types) > 0 && !slices.ContainsFunc(c.types, func(t string) bool { return hasType(v, t) }) {
+		return fail("expected %s, got %s", strings.Join(c.types, " or "), typeOf(v))
+	}
+	if c.enum != nil && !slices.ContainsFunc(c.enum, func(e any) bool { return equal(v, e) }) {
+		return fail("value is not one of the enum values")
+	}
+	if c.constant != nil && !equal(v, *c.constant) {
+		return fail("value does not equal the const value")
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		for _, name := range c.required {
+			if _, ok := v[name]; !ok {
+				return fail("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			p, ok := c.properties[name]
+			switch {
+			case ok:
+			case c.noAdditional:
+				return fail("unexpected property %q", name)
+			case c.additionalProperties != nil:
+				p = c.additionalProperties
+			default:
+				continue
+			}
+			if err := p.validate(v[name], path+"/"+name); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if c.minItems != nil && len(v) < *c.minItems {
+			return fail("expected at least %d items, got %d", *c.minItems, len(v))
+		}
+		if c.maxItems != nil && len(v) > *c.maxItems {
+			return fail("expected at most %d items, got %d", *c.maxItems, len(v))
+		}
+		if c.items != nil {
+			for i, item := range v {
+				if err := c.items.validate(item, path+"/"+strconv.Itoa(i)); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		n := utf8.RuneCountInString(v)
+		if c.minLength != nil && n < *c.minLength {
+			return fail("expected at least %d characters, got %d", *c.minLength, n)
+		}
+		if c.maxLength != nil && n > *c.maxLength {
+			return fail("expected at most %d characters, got %d", *c.maxLength, n)
+		}
+		if c.pattern != nil && !c.pattern.MatchString(v) {
+			return fail("%q does not match pattern %q", v, c.pattern)
+		}
+	case json.Number:
+		f, _ := v.Float64()
+		if c.minimum != nil && f < *c.minimum {
+			return fail("%s is less than the minimum %g", v, *c.minimum)
+		}
+		if c.maximum != nil && f > *c.maximum {
+			return fail("%s is greater than the maximum %g", v, *c.maximum)
+		}
+		if c.exclusiveMinimum != nil && f <= *c.exclusiveMinimum {
+			return fail("%s is not greater than %g", v, *c.exclusiveMinimum)
+		}
+		if c.exclusiveMaximum != nil && f >= *c.exclusiveMaximum {
+			return fail("%s is not less than %g", v, *c.exclusiveMaximum)
+		}
+	}
+	for _, s := range c.allOf {
+		if err := s.validate(v, path); err != nil {
+			return err
+		}
+	}
+	if c.anyOf != nil && !slices.ContainsFunc(c.anyOf, func(s *Schema) bool { return s.validate(v, path) == nil }) {
+		return fail("value does not match any schema of anyOf")
+	}
+	if c.oneOf != nil {
+		matches := 0
+		for _, s := range c.oneOf {
+			if s.validate(v, path) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fail("value matches %d schemas of oneOf instead of one", matches)
+		}
+	}
+	if c.not != nil && c.not.validate(v, path) == nil {
+		return fail("value matches the schema of not")
+	}
+	return nil
+}
+
+// hasType reports whether v, as decoded from JSON with numbers, is of the JSON
+// Schema type t.
+func hasType(v any, t string) bool {
+	if t == "integer" {
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	}
+	return typeOf(v) == t
+}
+
+func typeOf(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// equal reports whether v, as decoded from JSON with numbers, equals the value
+// e of a schema keyword.
+func equal(v, e any) bool {
+	if n, ok := v.(json.Number); ok {
+		f, _ := n.Float64()
+		g, ok := toFloat(e)
+		return ok && f == g
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		m, ok := e.(map[string]any)
+		if !ok || len(m) != len(v) {
+			return false
+		}
+		for k, x := range v {
+			y, ok := m[k]
+			if !ok || !equal(x, y) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		l, ok := e.([]any)
+		return ok && slices.EqualFunc(v, l, equal)
+	}
+	return reflect.DeepEqual(v, e)
+}
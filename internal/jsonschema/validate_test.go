@@ -0,0 +1,161 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/jsonschema"
+)
+
+func TestCompileInvalid(t *testing.T) {
+	tcs := []struct {
+		desc   string
+		schema map[string]any
+		err    string
+	}{
+		{
+			desc:   "unknown type",
+			schema: map[string]any{"type": "text"},
+			err:    `/type: unknown type "text"`,
+		},
+		{
+			desc:   "misspelled keyword",
+			schema: map[string]any{"type": "object", "propertes": map[string]any{}},
+			err:    "/propertes: unsupported keyword",
+		},
+		{
+			desc: "invalid property",
+			schema: map[string]any{
+				"properties": map[string]any{"id": map[string]any{"type": 1}},
+			},
+			err: "/properties/id/type: must be a string or an array of strings",
+		},
+		{
+			desc:   "invalid pattern",
+			schema: map[string]any{"pattern": "("},
+			err:    "/pattern: error parsing regexp",
+		},
+		{
+			desc:   "negative length",
+			schema: map[string]any{"minLength": int64(-1)},
+			err:    "/minLength: must be a non-negative integer",
+		},
+		{
+			desc:   "references",
+			schema: map[string]any{"$ref": "#/definitions/row"},
+			err:    "/$ref: unsupported keyword",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := jsonschema.Compile(tc.schema)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !strings.HasPrefix(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %q, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema, err := jsonschema.Compile(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id":      map[string]any{"type": "integer", "minimum": uint64(1)},
+			"airline": map[string]any{"type": "string", "pattern": "^[A-Z]{2}$"},
+			"price":   map[string]any{"type": []any{"number", "null"}},
+			"class":   map[string]any{"enum": []any{"economy", "business"}},
+			"tags":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		},
+		"required":             []any{"id"},
+		"additionalProperties": false,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tcs := []struct {
+		desc  string
+		value any
+		err   string
+	}{
+		{
+			desc:  "valid",
+			value: map[string]any{"id": int64(1), "airline": "CY", "price": 10.5, "class": "economy", "tags": []string{"a"}},
+		},
+		{
+			desc:  "null price",
+			value: map[string]any{"id": 2, "price": nil},
+		},
+		{
+			desc:  "not an object",
+			value: []any{},
+			err:   "expected object, got array",
+		},
+		{
+			desc:  "missing property",
+			value: map[string]any{"airline": "CY"},
+			err:   `missing required property "id"`,
+		},
+		{
+			desc:  "not an integer",
+			value: map[string]any{"id": 1.5},
+			err:   "/id: expected integer, got number",
+		},
+		{
+			desc:  "below minimum",
+			value: map[string]any{"id": 0},
+			err:   "/id: 0 is less than the minimum 1",
+		},
+		{
+			desc:  "pattern",
+			value: map[string]any{"id": 1, "airline": "cy"},
+			err:   `/airline: "cy" does not match pattern "^[A-Z]{2}$"`,
+		},
+		{
+			desc:  "enum",
+			value: map[string]any{"id": 1, "class": "first"},
+			err:   "/class: value is not one of the enum values",
+		},
+		{
+			desc:  "item",
+			value: map[string]any{"id": 1, "tags": []any{"a", 2}},
+			err:   "/tags/1: expected string, got number",
+		},
+		{
+			desc:  "additional property",
+			value: map[string]any{"id": 1, "seats": 2},
+			err:   `unexpected property "seats"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := schema.Validate(tc.value)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
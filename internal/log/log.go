@@ -183,3 +183,55 @@ func (sl *StructuredLogger) WarnContext(ctx context.Context, msg string, keysAnd
 func (sl *StructuredLogger) ErrorContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
 	sl.errLogger.ErrorContext(ctx, msg, keysAndValues...)
 }
+
+// RedactingLogger passes messages through a redact function before logging
+// them with another Logger.
+type RedactingLogger struct {
+	logger Logger
+	redact func(string) string
+}
+
+// NewRedactingLogger creates a Logger that logs with l after replacing the
+// sensitive parts of messages and string values using redact.
+func NewRedactingLogger(l Logger, redact func(string) string) Logger {
+	return &RedactingLogger{logger: l, redact: redact}
+}
+
+func (rl *RedactingLogger) redactAll(msg string, keysAndValues []interface{}) (string, []interface{}) {
+	redacted := make([]interface{}, len(keysAndValues))
+	for i, v := range keysAndValues {
+		switch v := v.(type) {
+		case string:
+			redacted[i] = rl.redact(v)
+		case error:
+			redacted[i] = rl.redact(v.Error())
+		default:
+			redacted[i] = v
+		}
+	}
+	return rl.redact(msg), redacted
+}
+
+// DebugContext logs debug messages
+func (rl *RedactingLogger) DebugContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	msg, keysAndValues = rl.redactAll(msg, keysAndValues)
+	rl.logger.DebugContext(ctx, msg, keysAndValues...)
+}
+
+// InfoContext logs info messages
+func (rl *RedactingLogger) InfoContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	msg, keysAndValues = rl.redactAll(msg, keysAndValues)
+	rl.logger.InfoContext(ctx, msg, keysAndValues...)
+}
+
+// WarnContext logs warning messages
+func (rl *RedactingLogger) WarnContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	msg, keysAndValues = rl.redactAll(msg, keysAndValues)
+	rl.logger.WarnContext(ctx, msg, keysAndValues...)
+}
+
+// ErrorContext logs error messages
+func (rl *RedactingLogger) ErrorContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	msg, keysAndValues = rl.redactAll(msg, keysAndValues)
+	rl.logger.ErrorContext(ctx, msg, keysAndValues...)
+}
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"strings"
 	"testing"
@@ -483,3 +484,31 @@ func TestStructuredLoggerDebugLog(t *testing.T) {
 		})
 	}
 }
+
+func TestRedactingLogger(t *testing.T) {
+	outW := new(bytes.Buffer)
+	errW := new(bytes.Buffer)
+	stdLogger, err := NewStdLogger(outW, errW, "debug")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	logger := NewRedactingLogger(stdLogger, func(s string) string {
+		return strings.ReplaceAll(s, "hunter2", "[REDACTED]")
+	})
+
+	ctx := context.Background()
+	logger.DebugContext(ctx, "password is hunter2", "password", "hunter2")
+	logger.ErrorContext(ctx, "unable to connect", "err", errors.New("invalid password hunter2"), "attempts", 3)
+
+	for _, got := range []string{outW.String(), errW.String()} {
+		if strings.Contains(got, "hunter2") {
+			t.Fatalf("log %q contains a secret", got)
+		}
+		if !strings.Contains(got, "[REDACTED]") {
+			t.Fatalf("log %q is not redacted", got)
+		}
+	}
+	if !strings.Contains(errW.String(), " 3 ") {
+		t.Fatalf("log %q is missing a value", errW.String())
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// PromptConfig is the configuration of a prompt template defined in the
+// `prompts` section of a tools file.
+type PromptConfig struct {
+	Name        string           `yaml:"name" validate:"required"`
+	Description string           `yaml:"description"`
+	Arguments   tools.Parameters `yaml:"arguments"`
+	Messages    []MessageConfig  `yaml:"messages" validate:"required,min=1,dive"`
+}
+
+// MessageConfig is the configuration of a single message of a prompt. Content
+// is a Go text/template that is rendered with the prompt's arguments.
+type MessageConfig struct {
+	Role    string `yaml:"role"`
+	Content string `yaml:"content" validate:"required"`
+}
+
+// Prompt is an initialized prompt template.
+type Prompt struct {
+	Name        string
+	Description string
+	Arguments   tools.Parameters
+	messages    []messageTemplate
+	manifest    McpManifest
+}
+
+type messageTemplate struct {
+	role     string
+	template *template.Template
+}
+
+// Message is a rendered prompt message.
+type Message struct {
+	Role    string
+	Content string
+}
+
+// Definition for a prompt the MCP client can retrieve.
+type McpManifest struct {
+	// The name of the prompt.
+	Name string `json:"name"`
+	// A human-readable description of the prompt.
+	Description string `json:"description,omitempty"`
+	// A list of arguments to use for templating the prompt.
+	Arguments []McpArgument `json:"arguments,omitempty"`
+}
+
+// Describes an argument that a prompt can accept.
+type McpArgument struct {
+	// The name of the argument.
+	Name string `json:"name"`
+	// A human-readable description of the argument.
+	Description string `json:"description,omitempty"`
+	// Whether this argument must be provided.
+	Required bool `json:"required,omitempty"`
+}
+
+// Initialize validates the config and parses its message templates.
+func (c PromptConfig) Initialize() (Prompt, error) {
+	if !tools.IsValidName(c.Name) {
+		return Prompt{}, fmt.Errorf("invalid prompt name: %s", c.Name)
+	}
+
+	args := make([]McpArgument, 0, len(c.Arguments))
+	for _, p := range c.Arguments {
+		if len(p.GetAuthServices()) != 0 {
+			return Prompt{}, fmt.Errorf("prompt argument %q cannot use authServices", p.GetName())
+		}
+		args = append(args, McpArgument{
+			Name:        p.GetName(),
+			Description: p.McpManifest().Description,
+			Required:    p.IsRequired(),
+		})
+	}
+
+	messages := make([]messageTemplate, 0, len(c.Messages))
+	for i, m := range c.Messages {
+		role := m.Role
+		if role == "" {
+			role = RoleUser
+		}
+		if role != RoleUser && role != RoleAssistant {
+			return Prompt{}, fmt.Errorf("invalid role %q for message %d: must be one of %q or %q", m.Role, i, RoleUser, RoleAssistant)
+		}
+		tmpl, err := template.New(fmt.Sprintf("%s[%d]", c.Name, i)).Option("missingkey=error").Parse(m.Content)
+		if err != nil {
+			return Prompt{}, fmt.Errorf("unable to parse template for message %d: %w", i, err)
+		}
+		messages = append(messages, messageTemplate{role: role, template: tmpl})
+	}
+
+	return Prompt{
+		Name:        c.Name,
+		Description: c.Description,
+		Arguments:   c.Arguments,
+		messages:    messages,
+		manifest: McpManifest{
+			Name:        c.Name,
+			Description: c.Description,
+			Arguments:   args,
+		},
+	}, nil
+}
+
+// McpManifest returns the MCP representation of the prompt.
+func (p Prompt) McpManifest() McpManifest {
+	return p.manifest
+}
+
+// ParseArgs parses the arguments of a prompt request. MCP clients send every
+// argument as a string, so values of non-string arguments are decoded as JSON
+// before being parsed by their Parameter.
+func (p Prompt) ParseArgs(args map[string]string) (tools.ParamValues, error) {
+	data := make(map[string]any, len(args))
+	for _, param := range p.Arguments {
+		v, ok := args[param.GetName()]
+		if !ok {
+			continue
+		}
+		if param.GetType() == "string" {
+			data[param.GetName()] = v
+			continue
+		}
+		d := json.NewDecoder(strings.NewReader(v))
+		// specify JSON numbers should get parsed to json.Number instead of float64 by default.
+		d.UseNumber()
+		var decoded any
+		if err := d.Decode(&decoded); err != nil {
+			return nil, fmt.Errorf("unable to parse value for %q: %w", param.GetName(), err)
+		}
+		data[param.GetName()] = decoded
+	}
+	return tools.ParseParams(p.Arguments, data, nil)
+}
+
+// Render renders the prompt messages with the given argument values.
+func (p Prompt) Render(params tools.ParamValues) ([]Message, error) {
+	data := params.AsMap()
+	messages := make([]Message, 0, len(p.messages))
+	for i, m := range p.messages {
+		var buf bytes.Buffer
+		if err := m.template.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("unable to render message %d: %w", i, err)
+		}
+		messages = append(messages, Message{Role: m.role, Content: buf.String()})
+	}
+	return messages, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompts_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestPromptRender(t *testing.T) {
+	tcs := []struct {
+		name string
+		cfg  prompts.PromptConfig
+		args map[string]string
+		want []prompts.Message
+	}{
+		{
+			name: "no arguments",
+			cfg: prompts.PromptConfig{
+				Name:     "greeting",
+				Messages: []prompts.MessageConfig{{Content: "Say hello."}},
+			},
+			want: []prompts.Message{{Role: "user", Content: "Say hello."}},
+		},
+		{
+			name: "typed arguments",
+			cfg: prompts.PromptConfig{
+				Name: "top_rows",
+				Arguments: tools.Parameters{
+					tools.NewStringParameter("table", "name of the table"),
+					tools.NewIntParameter("limit", "number of rows"),
+					tools.NewBooleanParameter("verbose", "whether to explain"),
+				},
+				Messages: []prompts.MessageConfig{
+					{Content: "Show the top {{.limit}} rows of {{.table}}.{{if .verbose}} Explain each row.{{end}}"},
+					{Role: "assistant", Content: "Looking at {{.table}}."},
+				},
+			},
+			args: map[string]string{"table": "users", "limit": "5", "verbose": "true"},
+			want: []prompts.Message{
+				{Role: "user", Content: "Show the top 5 rows of users. Explain each row."},
+				{Role: "assistant", Content: "Looking at users."},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := tc.cfg.Initialize()
+			if err != nil {
+				t.Fatalf("unable to initialize prompt: %s", err)
+			}
+			params, err := p.ParseArgs(tc.args)
+			if err != nil {
+				t.Fatalf("unable to parse arguments: %s", err)
+			}
+			got, err := p.Render(params)
+			if err != nil {
+				t.Fatalf("unable to render prompt: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect messages: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestPromptFailures(t *testing.T) {
+	tcs := []struct {
+		name string
+		cfg  prompts.PromptConfig
+		args map[string]string
+		err  string
+	}{
+		{
+			name: "invalid role",
+			cfg: prompts.PromptConfig{
+				Name:     "my_prompt",
+				Messages: []prompts.MessageConfig{{Role: "system", Content: "hello"}},
+			},
+			err: `invalid role "system" for message 0: must be one of "user" or "assistant"`,
+		},
+		{
+			name: "invalid template",
+			cfg: prompts.PromptConfig{
+				Name:     "my_prompt",
+				Messages: []prompts.MessageConfig{{Content: "{{.table"}},
+			},
+			err: `unable to parse template for message 0: template: my_prompt[0]:1: unclosed action`,
+		},
+		{
+			name: "authenticated argument",
+			cfg: prompts.PromptConfig{
+				Name: "my_prompt",
+				Arguments: tools.Parameters{
+					tools.NewStringParameterWithAuth("email", "user email", []tools.ParamAuthService{{Name: "my-google-auth", Field: "email"}}),
+				},
+				Messages: []prompts.MessageConfig{{Content: "{{.email}}"}},
+			},
+			err: `prompt argument "email" cannot use authServices`,
+		},
+		{
+			name: "missing argument",
+			cfg: prompts.PromptConfig{
+				Name:      "my_prompt",
+				Arguments: tools.Parameters{tools.NewStringParameter("table", "name of the table")},
+				Messages:  []prompts.MessageConfig{{Content: "{{.table}}"}},
+			},
+			args: map[string]string{},
+			err:  `parameter "table" is required`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := tc.cfg.Initialize()
+			if err == nil {
+				_, err = p.ParseArgs(tc.args)
+			}
+			if err == nil {
+				t.Fatalf("expected error but got nil")
+			}
+			if err.Error() != tc.err {
+				t.Fatalf("unexpected error: got %q, want %q", err.Error(), tc.err)
+			}
+		})
+	}
+}
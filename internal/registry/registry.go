@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package registry holds the kinds of a resource, such as sources or tools,
+// along with the factories decoding their configs.
+package registry
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	yaml "github.com/goccy/go-yaml"
+)
+
+// Factory decodes the config of the resource named name from decoder.
+type Factory[T any] func(ctx context.Context, name string, decoder *yaml.Decoder) (T, error)
+
+// Registry maps the kinds of a resource to their factories. It is safe for
+// concurrent use.
+type Registry[T any] struct {
+	resource string
+
+	mu        sync.RWMutex
+	factories map[string]Factory[T]
+}
+
+// New returns an empty registry for the kinds of resource, such as "tool",
+// which is used in error messages.
+func New[T any](resource string) *Registry[T] {
+	return &Registry[T]{resource: resource, factories: make(map[string]Factory[T])}
+}
+
+// Register makes a kind available. It returns false if the kind is already
+// registered.
+func (r *Registry[T]) Register(kind string, factory Factory[T]) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.factories[kind]; ok {
+		return false
+	}
+	r.factories[kind] = factory
+	return true
+}
+
+// MustRegister makes a kind available, and panics if it is already
+// registered.
+func (r *Registry[T]) MustRegister(kind string, factory Factory[T]) {
+	if !r.Register(kind, factory) {
+		panic(fmt.Sprintf("%s kind %q already registered", r.resource, kind))
+	}
+}
+
+// DecodeConfig decodes the config of the resource named name using the
+// factory registered for kind.
+func (r *Registry[T]) DecodeConfig(ctx context.Context, kind, name string, decoder *yaml.Decoder) (T, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[kind]
+	r.mu.RUnlock()
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("%q is not a valid kind of %s", kind, r.resource)
+	}
+	cfg, err := factory(ctx, name, decoder)
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("unable to parse as %q: %w", kind, err)
+	}
+	return cfg, nil
+}
+
+// Kinds returns the registered kinds, sorted.
+func (r *Registry[T]) Kinds() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Sorted(maps.Keys(r.factories))
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/registry"
+)
+
+func TestRegistry(t *testing.T) {
+	r := registry.New[string]("widget")
+	factory := func(kind string) registry.Factory[string] {
+		return func(context.Context, string, *yaml.Decoder) (string, error) {
+			return kind, nil
+		}
+	}
+	if !r.Register("b", factory("b")) || !r.Register("a", factory("a")) {
+		t.Fatalf("unable to register kinds")
+	}
+	if r.Register("a", factory("other")) {
+		t.Fatalf("kind registered twice")
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, r.Kinds()); diff != "" {
+		t.Fatalf("incorrect kinds: diff %v", diff)
+	}
+
+	got, err := r.DecodeConfig(context.Background(), "a", "my-widget", nil)
+	if err != nil || got != "a" {
+		t.Fatalf("unexpected config %q: %v", got, err)
+	}
+	_, err = r.DecodeConfig(context.Background(), "c", "my-widget", nil)
+	if err == nil || err.Error() != `"c" is not a valid kind of widget` {
+		t.Fatalf("expected unknown kind error, got %v", err)
+	}
+
+	defer func() {
+		if p := recover(); p == nil || !strings.Contains(p.(string), `widget kind "a" already registered`) {
+			t.Fatalf("unexpected panic: %v", p)
+		}
+	}()
+	r.MustRegister("a", factory("other"))
+}
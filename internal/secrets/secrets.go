@@ -0,0 +1,244 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secrets replaces references such as ${VAR}, ${VAR:-default} and
+// ${file:/run/secrets/password} in configuration files with their values.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound is returned, possibly wrapped, by a Resolver when the value
+// referenced does not exist. The default value of the reference is used
+// instead, if it has one.
+var ErrNotFound = errors.New("not found")
+
+// Resolver resolves the references of a scheme, such as "file" in
+// ${file:/run/secrets/password}.
+type Resolver interface {
+	// Resolve returns the value ref refers to.
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// EnvResolver resolves references to environment variables.
+type EnvResolver struct{}
+
+func (EnvResolver) Resolve(_ context.Context, ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q: %w", ref, ErrNotFound)
+	}
+	return value, nil
+}
+
+// FileResolver resolves references to files, such as the secrets mounted by
+// Kubernetes or Docker. Trailing newlines are removed from the file contents.
+type FileResolver struct{}
+
+func (FileResolver) Resolve(_ context.Context, ref string) (string, error) {
+	buf, err := os.ReadFile(ref)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("file %q: %w", ref, ErrNotFound)
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to read file %q: %w", ref, err)
+	}
+	return strings.TrimRight(string(buf), "\r\n"), nil
+}
+
+var (
+	schemeRe = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	varRe    = regexp.MustCompile(`^\w+$`)
+	// refRe matches references, and escaped references starting with "$$".
+	refRe = regexp.MustCompile(`\$?\$\{([^{}]*)\}`)
+)
+
+var (
+	mu        sync.RWMutex
+	resolvers = map[string]Resolver{
+		"env":  EnvResolver{},
+		"file": FileResolver{},
+	}
+	// values are the values resolved by Expand, which are redacted by
+	// Redact.
+	values = map[string]bool{}
+	// redactor replaces values, longest first, or is nil if there are none.
+	redactor *strings.Replacer
+)
+
+// MinRedactedLength is the length below which resolved values are not
+// redacted, as they are likely to appear in unrelated log text.
+const MinRedactedLength = 4
+
+// Register makes a Resolver available for the references of scheme. Schemes
+// must be lowercase and may only contain letters, digits and '-'. It returns
+// an error if scheme is invalid or already registered.
+func Register(scheme string, r Resolver) error {
+	if !schemeRe.MatchString(scheme) {
+		return fmt.Errorf("invalid secret scheme %q", scheme)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := resolvers[scheme]; ok {
+		return fmt.Errorf("secret scheme %q is already registered", scheme)
+	}
+	resolvers[scheme] = r
+	return nil
+}
+
+// Expand replaces the references in input with their values:
+//
+//   - ${VAR} is replaced with the value of the environment variable VAR. It is
+//     an error if VAR is not set.
+//   - ${scheme:ref} is replaced with the value the Resolver registered for
+//     scheme returns for ref, such as ${file:/run/secrets/password}.
+//   - ${VAR:-default} and ${scheme:ref:-default} are replaced with default if
+//     the value does not exist.
+//   - $${...} is replaced with the literal text ${...}.
+//
+// Resolved values, but not defaults, are treated as secrets and removed from
+// the strings passed to Redact. Text between ${ and } that is not a valid
+// reference is left unchanged. References in YAML comments are expanded too,
+// but are left unchanged rather than failing if they cannot be resolved.
+func Expand(ctx context.Context, input string) (string, error) {
+	var b strings.Builder
+	var errs []error
+	for _, line := range strings.SplitAfter(input, "\n") {
+		comment := commentStart(line)
+		last := 0
+		for _, m := range refRe.FindAllStringSubmatchIndex(line, -1) {
+			b.WriteString(line[last:m[0]])
+			last = m[1]
+			match := line[m[0]:m[1]]
+			if strings.HasPrefix(match, "$$") {
+				b.WriteString(match[1:])
+				continue
+			}
+			value, err := resolve(ctx, line[m[2]:m[3]])
+			if err != nil && m[0] < comment {
+				errs = append(errs, err)
+			}
+			if value == nil {
+				b.WriteString(match)
+				continue
+			}
+			b.WriteString(*value)
+		}
+		b.WriteString(line[last:])
+	}
+	return b.String(), errors.Join(errs...)
+}
+
+// commentStart returns the index of the YAML comment of line, or its length
+// if it has none. Comments start with a '#' at the start of the line or after
+// a space, outside of quoted strings.
+func commentStart(line string) int {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"' && c == '\\':
+			// skip the escaped character
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return i
+		}
+	}
+	return len(line)
+}
+
+// resolve returns the value of the reference expr, or nil if expr is not a
+// reference.
+func resolve(ctx context.Context, expr string) (*string, error) {
+	expr, def, hasDefault := strings.Cut(expr, ":-")
+
+	var value string
+	var err error
+	if varRe.MatchString(expr) {
+		value, err = EnvResolver{}.Resolve(ctx, expr)
+	} else {
+		scheme, ref, ok := strings.Cut(expr, ":")
+		if !ok || ref == "" || !schemeRe.MatchString(scheme) {
+			return nil, nil
+		}
+		mu.RLock()
+		r, ok := resolvers[scheme]
+		mu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("unknown secret scheme %q in ${%s}", scheme, expr)
+		}
+		value, err = r.Resolve(ctx, ref)
+	}
+
+	switch {
+	case errors.Is(err, ErrNotFound) && hasDefault:
+		return &def, nil
+	case errors.Is(err, ErrNotFound):
+		return nil, fmt.Errorf("${%s} is required: %w", expr, err)
+	case err != nil:
+		return nil, fmt.Errorf("unable to resolve ${%s}: %w", expr, err)
+	}
+	addRedacted(value)
+	return &value, nil
+}
+
+// addRedacted records value to be redacted by Redact.
+func addRedacted(value string) {
+	if len(value) < MinRedactedLength {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if values[value] {
+		return
+	}
+	values[value] = true
+	// replace longer values first, in case a value contains another
+	sorted := slices.SortedFunc(maps.Keys(values), func(a, b string) int { return len(b) - len(a) })
+	pairs := make([]string, 0, 2*len(sorted))
+	for _, v := range sorted {
+		pairs = append(pairs, v, Redacted)
+	}
+	redactor = strings.NewReplacer(pairs...)
+}
+
+// Redacted replaces secret values in the strings returned by Redact.
+const Redacted = "[REDACTED]"
+
+// Redact returns s with every value resolved by Expand replaced with Redacted.
+// Values shorter than MinRedactedLength are kept.
+func Redact(s string) string {
+	mu.RLock()
+	r := redactor
+	mu.RUnlock()
+	if r == nil {
+		return s
+	}
+	return r.Replace(s)
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// mapResolver resolves references to the values of a map.
+type mapResolver map[string]string
+
+func (m mapResolver) Resolve(_ context.Context, ref string) (string, error) {
+	if v, ok := m[ref]; ok {
+		return v, nil
+	}
+	if ref == "broken" {
+		return "", errors.New("backend unavailable")
+	}
+	return "", fmt.Errorf("secret %q: %w", ref, ErrNotFound)
+}
+
+func TestExpand(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("SECRETS_TEST_USER", "alice")
+	t.Setenv("SECRETS_TEST_EMPTY", "")
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "pg_password")
+	if err := os.WriteFile(passwordFile, []byte("file-password\n"), 0o600); err != nil {
+		t.Fatalf("unable to write file: %s", err)
+	}
+	if err := Register("test-map", mapResolver{"api-key": "map-api-key"}); err != nil {
+		t.Fatalf("unable to register resolver: %s", err)
+	}
+
+	tcs := []struct {
+		desc string
+		in   string
+		want string
+	}{
+		{desc: "environment variable", in: "user: ${SECRETS_TEST_USER}", want: "user: alice"},
+		{desc: "empty environment variable", in: "user: '${SECRETS_TEST_EMPTY:-bob}'", want: "user: ''"},
+		{desc: "default", in: "user: ${SECRETS_TEST_MISSING:-bob}", want: "user: bob"},
+		{desc: "empty default", in: "user: '${SECRETS_TEST_MISSING:-}'", want: "user: ''"},
+		{desc: "default with colons", in: "url: ${SECRETS_TEST_MISSING:-http://localhost:8080}", want: "url: http://localhost:8080"},
+		{desc: "env scheme", in: "user: ${env:SECRETS_TEST_USER}", want: "user: alice"},
+		{desc: "file", in: "password: ${file:" + passwordFile + "}", want: "password: file-password"},
+		{desc: "missing file with default", in: "password: ${file:" + filepath.Join(dir, "missing") + ":-none}", want: "password: none"},
+		{desc: "registered scheme", in: "key: ${test-map:api-key}", want: "key: map-api-key"},
+		{desc: "escaped", in: "literal: $${SECRETS_TEST_USER}", want: "literal: ${SECRETS_TEST_USER}"},
+		{desc: "not a reference", in: "text: ${ not a reference }", want: "text: ${ not a reference }"},
+		{desc: "missing in comment", in: "# set ${SECRETS_TEST_MISSING}\nuser: ${SECRETS_TEST_USER} # or ${SECRETS_TEST_MISSING}", want: "# set ${SECRETS_TEST_MISSING}\nuser: alice # or ${SECRETS_TEST_MISSING}"},
+		{desc: "resolved in comment", in: "# user ${SECRETS_TEST_USER}", want: "# user alice"},
+		{desc: "hash in quotes", in: `user: "a # ${SECRETS_TEST_USER}"`, want: `user: "a # alice"`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := Expand(ctx, tc.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Fatalf("incorrect expansion: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExpandErrors(t *testing.T) {
+	ctx := context.Background()
+	if err := Register("test-broken", mapResolver{}); err != nil {
+		t.Fatalf("unable to register resolver: %s", err)
+	}
+
+	tcs := []struct {
+		desc string
+		in   string
+		want []string
+	}{
+		{
+			desc: "missing environment variables",
+			in:   "user: ${SECRETS_TEST_MISSING}\npassword: ${env:SECRETS_TEST_MISSING_TOO}",
+			want: []string{
+				`${SECRETS_TEST_MISSING} is required: environment variable "SECRETS_TEST_MISSING": not found`,
+				`${env:SECRETS_TEST_MISSING_TOO} is required`,
+			},
+		},
+		{
+			desc: "missing in quoted hash",
+			in:   `user: '# ${SECRETS_TEST_MISSING}'`,
+			want: []string{`${SECRETS_TEST_MISSING} is required`},
+		},
+		{
+			desc: "missing file",
+			in:   "password: ${file:/does/not/exist}",
+			want: []string{`${file:/does/not/exist} is required: file "/does/not/exist": not found`},
+		},
+		{
+			desc: "unknown scheme",
+			in:   "password: ${vault:secret/pg}",
+			want: []string{`unknown secret scheme "vault"`},
+		},
+		{
+			desc: "resolver error",
+			in:   "password: ${test-broken:broken:-ignored}",
+			want: []string{"unable to resolve ${test-broken:broken}: backend unavailable"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := Expand(ctx, tc.in)
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("error %q does not contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterErrors(t *testing.T) {
+	if err := Register("file", mapResolver{}); err == nil {
+		t.Fatalf("expected error registering a scheme twice")
+	}
+	if err := Register("Invalid_Scheme", mapResolver{}); err == nil {
+		t.Fatalf("expected error registering an invalid scheme")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("SECRETS_TEST_PASSWORD", "s3cr3t-password")
+	t.Setenv("SECRETS_TEST_PREFIX", "s3cr3t")
+	t.Setenv("SECRETS_TEST_SHORT", "a")
+	if _, err := Expand(ctx, "${SECRETS_TEST_PREFIX} ${SECRETS_TEST_PASSWORD} ${SECRETS_TEST_SHORT} ${SECRETS_TEST_MISSING:-my-project}"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// defaults and short values are kept, and longer values are replaced first
+	got := Redact("connecting to my-project as a with s3cr3t-password, s3cr3t")
+	want := "connecting to my-project as a with " + Redacted + ", " + Redacted
+	if got != want {
+		t.Fatalf("incorrect redaction: got %q, want %q", got, want)
+	}
+}
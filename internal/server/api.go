@@ -15,6 +15,7 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -75,7 +76,9 @@ func toolsetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		)
 	}()
 
-	toolset, ok := s.toolsets[toolsetName]
+	resources, release := s.resourceMgr.acquire()
+	defer release()
+	toolset, ok := resources.toolsets[toolsetName]
 	if !ok {
 		err = fmt.Errorf("Toolset %q does not exist", toolsetName)
 		s.logger.DebugContext(ctx, err.Error())
@@ -111,7 +114,9 @@ func toolGetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 			metric.WithAttributes(attribute.String("toolbox.operation.status", status)),
 		)
 	}()
-	tool, ok := s.tools[toolName]
+	resources, release := s.resourceMgr.acquire()
+	defer release()
+	tool, ok := resources.tools[toolName]
 	if !ok {
 		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
 		s.logger.DebugContext(ctx, err.Error())
@@ -156,7 +161,9 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		)
 	}()
 
-	tool, ok := s.tools[toolName]
+	resources, release := s.resourceMgr.acquire()
+	defer release()
+	tool, ok := resources.tools[toolName]
 	if !ok {
 		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
 		s.logger.DebugContext(ctx, err.Error())
@@ -164,29 +171,32 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Tool authentication
-	// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
-	claimsFromAuth := make(map[string]map[string]any)
-	for _, aS := range s.authServices {
-		claims, err := aS.GetClaimsFromHeader(ctx, r.Header)
-		if err != nil {
+	// Toolset membership check
+	// Callers may specify the toolset they are using, in which case the tool
+	// must be a part of it.
+	if toolsetName := r.URL.Query().Get("toolset"); r.URL.Query().Has("toolset") {
+		span.SetAttributes(attribute.String("toolset_name", toolsetName))
+		toolset, ok := resources.toolsets[toolsetName]
+		if !ok {
+			err = fmt.Errorf("Toolset %q does not exist", toolsetName)
 			s.logger.DebugContext(ctx, err.Error())
-			continue
+			_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+			return
 		}
-		if claims == nil {
-			// authService not present in header
-			continue
+		if _, ok := toolset.Manifest.ToolsManifest[toolName]; !ok {
+			err = fmt.Errorf("tool %q is not a part of toolset %q", toolName, toolsetName)
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusForbidden))
+			return
 		}
-		claimsFromAuth[aS.GetName()] = claims
 	}
 
+	// Tool authentication
+	// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
+	claimsFromAuth := getClaimsFromHeader(ctx, s, r.Header)
+
 	// Tool authorization check
-	verifiedAuthServices := make([]string, len(claimsFromAuth))
-	i := 0
-	for k := range claimsFromAuth {
-		verifiedAuthServices[i] = k
-		i++
-	}
+	verifiedAuthServices := verifiedAuthServiceNames(claimsFromAuth)
 
 	// Check if any of the specified auth services is verified
 	isAuthorized := tool.Authorized(verifiedAuthServices)
@@ -275,6 +285,38 @@ func (e *errResponse) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+// getClaimsFromHeader verifies the request headers against every configured
+// auth service. It returns a map of the name of each verified auth service to
+// the claims retrieved from it.
+func getClaimsFromHeader(ctx context.Context, s *Server, h http.Header) map[string]map[string]any {
+	resources, release := s.resourceMgr.acquire()
+	defer release()
+	claimsFromAuth := make(map[string]map[string]any)
+	for _, aS := range resources.authServices {
+		claims, err := aS.GetClaimsFromHeader(ctx, h)
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			continue
+		}
+		if claims == nil {
+			// authService not present in header
+			continue
+		}
+		claimsFromAuth[aS.GetName()] = claims
+	}
+	return claimsFromAuth
+}
+
+// verifiedAuthServiceNames returns the names of the auth services that
+// successfully verified the request.
+func verifiedAuthServiceNames(claimsFromAuth map[string]map[string]any) []string {
+	verifiedAuthServices := make([]string, 0, len(claimsFromAuth))
+	for k := range claimsFromAuth {
+		verifiedAuthServices = append(verifiedAuthServices, k)
+	}
+	return verifiedAuthServices
+}
+
 // decodeJSON decodes a given reader into an interface using the json decoder.
 func decodeJSON(r io.Reader, v interface{}) error {
 	defer io.Copy(io.Discard, r) //nolint:errcheck
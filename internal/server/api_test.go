@@ -222,6 +222,7 @@ func TestToolInvokeEndpoint(t *testing.T) {
 	testCases := []struct {
 		name        string
 		toolName    string
+		query       string
 		requestBody io.Reader
 		want        string
 		isErr       bool
@@ -247,11 +248,35 @@ func TestToolInvokeEndpoint(t *testing.T) {
 			want:        "",
 			isErr:       true,
 		},
+		{
+			name:        "tool2 in toolset",
+			toolName:    tool2.Name,
+			query:       "?toolset=tool2_only",
+			requestBody: bytes.NewBuffer([]byte(`{"param1": 1, "param2": 2}`)),
+			want:        "{result:[some_params]}\n",
+			isErr:       false,
+		},
+		{
+			name:        "tool2 outside of toolset",
+			toolName:    tool2.Name,
+			query:       "?toolset=tool1_only",
+			requestBody: bytes.NewBuffer([]byte(`{"param1": 1, "param2": 2}`)),
+			want:        "",
+			isErr:       true,
+		},
+		{
+			name:        "invalid toolset",
+			toolName:    tool1.Name,
+			query:       "?toolset=some_imaginary_toolset",
+			requestBody: bytes.NewBuffer([]byte(`{}`)),
+			want:        "",
+			isErr:       true,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tc.toolName), tc.requestBody)
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke%s", tc.toolName, tc.query), tc.requestBody)
 			if err != nil {
 				t.Fatalf("unexpected error during request: %s", err)
 			}
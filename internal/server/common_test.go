@@ -21,11 +21,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
-	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/sessions"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
@@ -37,13 +40,21 @@ var _ tools.Tool = &MockTool{}
 
 // MockTool is used to mock tools in tests
 type MockTool struct {
-	Name        string
-	Description string
-	Params      []tools.Parameter
-	manifest    tools.Manifest
+	Name         string
+	Description  string
+	Params       []tools.Parameter
+	AuthRequired []string
+	Annotations  *tools.ToolAnnotations
+	OutputSchema *tools.McpOutputSchema
+	// InvokeFunc overrides the result of Invoke when set
+	InvokeFunc func(context.Context, tools.ParamValues) ([]any, error)
+	manifest   tools.Manifest
 }
 
-func (t MockTool) Invoke(context.Context, tools.ParamValues) ([]any, error) {
+func (t MockTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	if t.InvokeFunc != nil {
+		return t.InvokeFunc(ctx, params)
+	}
 	mock := []any{t.Name}
 	return mock, nil
 }
@@ -61,7 +72,7 @@ func (t MockTool) Manifest() tools.Manifest {
 	return tools.Manifest{Description: t.Description, Parameters: pMs}
 }
 func (t MockTool) Authorized(verifiedAuthServices []string) bool {
-	return true
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
 }
 
 func (t MockTool) McpManifest() tools.McpManifest {
@@ -81,9 +92,11 @@ func (t MockTool) McpManifest() tools.McpManifest {
 	}
 
 	return tools.McpManifest{
-		Name:        t.Name,
-		Description: t.Description,
-		InputSchema: toolsSchema,
+		Name:         t.Name,
+		Description:  t.Description,
+		InputSchema:  toolsSchema,
+		Annotations:  t.Annotations,
+		OutputSchema: t.OutputSchema,
 	}
 }
 
@@ -108,6 +121,76 @@ var tool3 = MockTool{
 	},
 }
 
+var _ auth.AuthService = MockAuthService{}
+
+// MockAuthService is used to mock auth services in tests. It accepts any
+// non-empty "<name>_token" header and returns the token as the "sub" claim,
+// expiring after TokenLifetime, or an hour if it is 0.
+type MockAuthService struct {
+	Name          string
+	TokenLifetime time.Duration
+}
+
+func (a MockAuthService) AuthServiceKind() string {
+	return "mock"
+}
+
+func (a MockAuthService) GetName() string {
+	return a.Name
+}
+
+func (a MockAuthService) GetClaimsFromHeader(ctx context.Context, h http.Header) (map[string]any, error) {
+	if token := h.Get(a.Name + "_token"); token != "" {
+		lifetime := a.TokenLifetime
+		if lifetime == 0 {
+			lifetime = time.Hour
+		}
+		return map[string]any{"sub": token, "exp": float64(time.Now().Add(lifetime).Unix())}, nil
+	}
+	return nil, nil
+}
+
+var _ tools.ReferencingConfig = MockToolConfig{}
+
+// MockToolConfig is used to mock the configs of tools running against a
+// source in tests.
+type MockToolConfig struct {
+	Source string
+}
+
+func (c MockToolConfig) ToolConfigKind() string {
+	return "mock"
+}
+
+func (c MockToolConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return nil, fmt.Errorf("mock tool configs cannot be initialized")
+}
+
+func (c MockToolConfig) References() tools.ConfigReferences {
+	return tools.ConfigReferences{Source: c.Source}
+}
+
+var _ sources.Source = MockSource{}
+
+// MockSource is used to mock sources in tests
+type MockSource struct{}
+
+func (s MockSource) SourceKind() string {
+	return "mock"
+}
+
+var _ sources.SchemaSource = MockSchemaSource{}
+
+// MockSchemaSource is used to mock sources that are able to describe their schema in tests
+type MockSchemaSource struct {
+	MockSource
+	SchemaValue any
+}
+
+func (s MockSchemaSource) Schema(context.Context) (any, error) {
+	return s.SchemaValue, nil
+}
+
 // setUpResources setups resources to test against
 func setUpResources(t *testing.T, mockTools []MockTool) (map[string]tools.Tool, map[string]tools.Toolset) {
 	toolsMap := make(map[string]tools.Tool)
@@ -136,6 +219,29 @@ func setUpResources(t *testing.T, mockTools []MockTool) (map[string]tools.Tool,
 
 // setUpServer create a new server with tools and toolsets that are given
 func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, toolsets map[string]tools.Toolset) (chi.Router, func()) {
+	server, shutdown := setUpTestServer(t, tools, toolsets)
+
+	var r chi.Router
+	var err error
+	switch router {
+	case "api":
+		r, err = apiRouter(server)
+		if err != nil {
+			t.Fatalf("unable to initialize api router: %s", err)
+		}
+	case "mcp":
+		r, err = mcpRouter(server)
+		if err != nil {
+			t.Fatalf("unable to initialize mcp router: %s", err)
+		}
+	default:
+		t.Fatalf("unknown router")
+	}
+	return r, shutdown
+}
+
+// setUpTestServer creates a new Server struct with tools and toolsets that are given
+func setUpTestServer(t *testing.T, tools map[string]tools.Tool, toolsets map[string]tools.Toolset) (*Server, func()) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
@@ -153,27 +259,7 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 		t.Fatalf("unable to create custom metrics: %s", err)
 	}
 
-	sseManager := &sseManager{
-		mu:          sync.RWMutex{},
-		sseSessions: make(map[string]*sseSession),
-	}
-
-	server := Server{version: fakeVersionString, logger: testLogger, instrumentation: instrumentation, sseManager: sseManager, tools: tools, toolsets: toolsets}
-	var r chi.Router
-	switch router {
-	case "api":
-		r, err = apiRouter(&server)
-		if err != nil {
-			t.Fatalf("unable to initialize api router: %s", err)
-		}
-	case "mcp":
-		r, err = mcpRouter(&server)
-		if err != nil {
-			t.Fatalf("unable to initialize mcp router: %s", err)
-		}
-	default:
-		t.Fatalf("unknown router")
-	}
+	server := &Server{version: fakeVersionString, logger: testLogger, instrumentation: instrumentation, sessionStore: sessions.NewMemoryStore(sessions.Config{}), requestManager: newRequestManager(), resourceMgr: newResourceManager(&resourceSet{tools: tools, toolsets: toolsets}), listeners: newListenerRegistry()}
 	shutdown := func() {
 		// cancel context
 		cancel()
@@ -184,7 +270,7 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 		}
 	}
 
-	return r, shutdown
+	return server, shutdown
 }
 
 func runServer(r chi.Router, tls bool) *httptest.Server {
@@ -16,41 +16,15 @@ package server
 import (
 	"context"
 	"fmt"
+	"maps"
 	"strings"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/auth"
-	"github.com/googleapis/genai-toolbox/internal/auth/google"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/sources"
-	alloydbpgsrc "github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
-	bigquerysrc "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
-	bigtablesrc "github.com/googleapis/genai-toolbox/internal/sources/bigtable"
-	cloudsqlmssqlsrc "github.com/googleapis/genai-toolbox/internal/sources/cloudsqlmssql"
-	cloudsqlmysqlsrc "github.com/googleapis/genai-toolbox/internal/sources/cloudsqlmysql"
-	cloudsqlpgsrc "github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
-	couchbasesrc "github.com/googleapis/genai-toolbox/internal/sources/couchbase"
-	dgraphsrc "github.com/googleapis/genai-toolbox/internal/sources/dgraph"
-	httpsrc "github.com/googleapis/genai-toolbox/internal/sources/http"
-	mssqlsrc "github.com/googleapis/genai-toolbox/internal/sources/mssql"
-	mysqlsrc "github.com/googleapis/genai-toolbox/internal/sources/mysql"
-	neo4jsrc "github.com/googleapis/genai-toolbox/internal/sources/neo4j"
-	postgressrc "github.com/googleapis/genai-toolbox/internal/sources/postgres"
-	spannersrc "github.com/googleapis/genai-toolbox/internal/sources/spanner"
-	sqlitesrc "github.com/googleapis/genai-toolbox/internal/sources/sqlite"
 	"github.com/googleapis/genai-toolbox/internal/tools"
-	"github.com/googleapis/genai-toolbox/internal/tools/alloydbainl"
-	"github.com/googleapis/genai-toolbox/internal/tools/bigquery"
-	"github.com/googleapis/genai-toolbox/internal/tools/bigtable"
-	couchbasetool "github.com/googleapis/genai-toolbox/internal/tools/couchbase"
-	"github.com/googleapis/genai-toolbox/internal/tools/dgraph"
-	httptool "github.com/googleapis/genai-toolbox/internal/tools/http"
-	"github.com/googleapis/genai-toolbox/internal/tools/mssqlsql"
-	"github.com/googleapis/genai-toolbox/internal/tools/mysqlsql"
-	neo4jtool "github.com/googleapis/genai-toolbox/internal/tools/neo4j"
-	"github.com/googleapis/genai-toolbox/internal/tools/postgresexecutesql"
-	"github.com/googleapis/genai-toolbox/internal/tools/postgressql"
-	"github.com/googleapis/genai-toolbox/internal/tools/spanner"
-	"github.com/googleapis/genai-toolbox/internal/tools/sqlitesql"
 	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
@@ -69,6 +43,8 @@ type ServerConfig struct {
 	ToolConfigs ToolConfigs
 	// ToolsetConfigs defines what tools are available.
 	ToolsetConfigs ToolsetConfigs
+	// PromptConfigs defines what prompts are available.
+	PromptConfigs PromptConfigs
 	// LoggingFormat defines whether structured loggings are used.
 	LoggingFormat logFormat
 	// LogLevel defines the levels to log.
@@ -79,6 +55,18 @@ type ServerConfig struct {
 	TelemetryOTLP string
 	// TelemetryServiceName defines the value of service.name resource attribute.
 	TelemetryServiceName string
+	// SseKeepAliveInterval defines the interval between keepalive comments
+	// written to idle MCP event streams. 0 disables them.
+	SseKeepAliveInterval time.Duration
+	// SseSessionIdleTimeout defines how long MCP sessions without a stream
+	// are kept. 0 keeps them until they are terminated.
+	SseSessionIdleTimeout time.Duration
+	// SseReplayBufferSize defines the number of events kept by each MCP
+	// session for clients resuming its stream.
+	SseReplayBufferSize int
+	// SseSessionStore defines where MCP sessions are stored: "memory" or the
+	// URL of a Redis server shared by every replica.
+	SseSessionStore string
 }
 
 type logFormat string
@@ -153,111 +141,11 @@ func (c *SourceConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interf
 		if err := u.Unmarshal(&v); err != nil {
 			return fmt.Errorf("unable to unmarshal %q: %w", name, err)
 		}
-
-		kind, ok := v["kind"]
-		if !ok {
-			return fmt.Errorf("missing 'kind' field for %q", name)
-		}
-
-		dec, err := util.NewStrictDecoder(v)
+		actual, err := DecodeSourceConfig(ctx, name, v)
 		if err != nil {
-			return fmt.Errorf("error creating decoder: %w", err)
-		}
-		switch kind {
-		case alloydbpgsrc.SourceKind:
-			actual := alloydbpgsrc.Config{Name: name, IPType: "public"}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case bigtablesrc.SourceKind:
-			actual := bigtablesrc.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case cloudsqlpgsrc.SourceKind:
-			actual := cloudsqlpgsrc.Config{Name: name, IPType: "public"}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case postgressrc.SourceKind:
-			actual := postgressrc.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case cloudsqlmysqlsrc.SourceKind:
-			actual := cloudsqlmysqlsrc.Config{Name: name, IPType: "public"}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case mysqlsrc.SourceKind:
-			actual := mysqlsrc.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case spannersrc.SourceKind:
-			actual := spannersrc.Config{Name: name, Dialect: "googlesql"}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case neo4jsrc.SourceKind:
-			actual := neo4jsrc.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case cloudsqlmssqlsrc.SourceKind:
-			actual := cloudsqlmssqlsrc.Config{Name: name, IPType: "public"}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case mssqlsrc.SourceKind:
-			actual := mssqlsrc.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case dgraphsrc.SourceKind:
-			actual := dgraphsrc.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case httpsrc.SourceKind:
-			actual := httpsrc.DefaultConfig(name)
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case bigquerysrc.SourceKind:
-			actual := bigquerysrc.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case sqlitesrc.SourceKind:
-			actual := sqlitesrc.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case couchbasesrc.SourceKind:
-			actual := couchbasesrc.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		default:
-			return fmt.Errorf("%q is not a valid kind of data source", kind)
+			return err
 		}
-
+		(*c)[name] = actual
 	}
 	return nil
 }
@@ -281,26 +169,11 @@ func (c *AuthServiceConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(i
 		if err := u.Unmarshal(&v); err != nil {
 			return fmt.Errorf("unable to unmarshal %q: %w", name, err)
 		}
-
-		kind, ok := v["kind"]
-		if !ok {
-			return fmt.Errorf("missing 'kind' field for %q", name)
-		}
-
-		dec, err := util.NewStrictDecoder(v)
+		actual, err := DecodeAuthServiceConfig(ctx, name, v)
 		if err != nil {
-			return fmt.Errorf("error creating decoder: %w", err)
-		}
-		switch kind {
-		case google.AuthServiceKind:
-			actual := google.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		default:
-			return fmt.Errorf("%q is not a valid kind of auth source", kind)
+			return err
 		}
+		(*c)[name] = actual
 	}
 	return nil
 }
@@ -324,106 +197,66 @@ func (c *ToolConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interfac
 		if err := u.Unmarshal(&v); err != nil {
 			return fmt.Errorf("unable to unmarshal %q: %w", name, err)
 		}
-
-		// Make `authRequired` an empty list instead of nil for Tool manifest
-		if v["authRequired"] == nil {
-			v["authRequired"] = []string{}
+		actual, err := DecodeToolConfig(ctx, name, v)
+		if err != nil {
+			return err
 		}
+		(*c)[name] = actual
+	}
+	return nil
+}
 
-		kind, ok := v["kind"]
-		if !ok {
-			return fmt.Errorf("missing 'kind' field for %q", name)
-		}
+// decoderForKind returns the kind of the config v of the resource named
+// name, and a strict decoder of v.
+func decoderForKind(name string, v map[string]any) (string, *yaml.Decoder, error) {
+	k, ok := v["kind"]
+	if !ok {
+		return "", nil, fmt.Errorf("missing 'kind' field for %q", name)
+	}
+	kind, ok := k.(string)
+	if !ok {
+		return "", nil, fmt.Errorf("invalid 'kind' field for %q: must be a string", name)
+	}
+	dec, err := util.NewStrictDecoder(v)
+	if err != nil {
+		return "", nil, fmt.Errorf("error creating decoder: %w", err)
+	}
+	return kind, dec, nil
+}
 
-		dec, err := util.NewStrictDecoder(v)
-		if err != nil {
-			return fmt.Errorf("error creating decoder: %w", err)
-		}
-		switch kind {
-		case bigtable.ToolKind:
-			actual := bigtable.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case postgressql.ToolKind:
-			actual := postgressql.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case alloydbainl.ToolKind:
-			actual := alloydbainl.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case mysqlsql.ToolKind:
-			actual := mysqlsql.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case spanner.ToolKind:
-			actual := spanner.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case neo4jtool.ToolKind:
-			actual := neo4jtool.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case mssqlsql.ToolKind:
-			actual := mssqlsql.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case dgraph.ToolKind:
-			actual := dgraph.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case httptool.ToolKind:
-			actual := httptool.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case bigquery.ToolKind:
-			actual := bigquery.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case sqlitesql.ToolKind:
-			actual := sqlitesql.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case postgresexecutesql.ToolKind:
-			actual := postgresexecutesql.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		case couchbasetool.ToolKind:
-			actual := couchbasetool.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		default:
-			return fmt.Errorf("%q is not a valid kind of tool", kind)
-		}
+// DecodeSourceConfig decodes the config of the source named name from the
+// fields of its section in a tools file, including its kind.
+func DecodeSourceConfig(ctx context.Context, name string, v map[string]any) (sources.SourceConfig, error) {
+	kind, dec, err := decoderForKind(name, v)
+	if err != nil {
+		return nil, err
+	}
+	return sources.DecodeConfig(ctx, kind, name, dec)
+}
 
+// DecodeAuthServiceConfig decodes the config of the auth service named name
+// from the fields of its section in a tools file, including its kind.
+func DecodeAuthServiceConfig(ctx context.Context, name string, v map[string]any) (auth.AuthServiceConfig, error) {
+	kind, dec, err := decoderForKind(name, v)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return auth.DecodeConfig(ctx, kind, name, dec)
+}
+
+// DecodeToolConfig decodes the config of the tool named name from the fields
+// of its section in a tools file, including its kind. v is not modified.
+func DecodeToolConfig(ctx context.Context, name string, v map[string]any) (tools.ToolConfig, error) {
+	// Make `authRequired` an empty list instead of nil for Tool manifest
+	if v["authRequired"] == nil {
+		v = maps.Clone(v)
+		v["authRequired"] = []string{}
+	}
+	kind, dec, err := decoderForKind(name, v)
+	if err != nil {
+		return nil, err
+	}
+	return tools.DecodeConfig(ctx, kind, name, dec)
 }
 
 // ToolConfigs is a type used to allow unmarshal of the toolset configs
@@ -445,3 +278,36 @@ func (c *ToolsetConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(inter
 	}
 	return nil
 }
+
+// PromptConfigs is a type used to allow unmarshal of the prompt configs
+type PromptConfigs map[string]prompts.PromptConfig
+
+// validate interface
+var _ yaml.InterfaceUnmarshalerContext = &PromptConfigs{}
+
+func (c *PromptConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	*c = make(PromptConfigs)
+
+	var raw map[string]util.DelayedUnmarshaler
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	for name, u := range raw {
+		var v map[string]any
+		if err := u.Unmarshal(&v); err != nil {
+			return fmt.Errorf("unable to unmarshal %q: %w", name, err)
+		}
+
+		dec, err := util.NewStrictDecoder(v)
+		if err != nil {
+			return fmt.Errorf("error creating decoder: %w", err)
+		}
+		actual := prompts.PromptConfig{Name: name}
+		if err := dec.DecodeContext(ctx, &actual); err != nil {
+			return fmt.Errorf("unable to parse prompt %q as %q: %w", name, "prompt", err)
+		}
+		(*c)[name] = actual
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	// ErrToolNotFound is returned when invoking a tool that does not exist.
+	ErrToolNotFound = errors.New("tool not found")
+	// ErrToolsetNotFound is returned when listing a toolset that does not
+	// exist.
+	ErrToolsetNotFound = errors.New("toolset not found")
+	// ErrUnauthorized is returned when invoking a tool without the claims of
+	// any of the auth services it requires.
+	ErrUnauthorized = errors.New("tool invocation not authorized")
+)
+
+// Handler returns the handler serving the HTTP API and MCP endpoints of s, so
+// that they can be served by another HTTP server.
+func (s *Server) Handler() http.Handler {
+	return s.root
+}
+
+// InvokeTool invokes the tool named toolName with params, without going
+// through HTTP. claims maps the names of auth services to the claims of the
+// caller they verified. They are used to authorize the invocation and to fill
+// the parameters bound to auth services.
+func (s *Server) InvokeTool(ctx context.Context, toolName string, params map[string]any, claims map[string]map[string]any) (res []any, err error) {
+	ctx, span := s.instrumentation.Tracer.Start(ctx, "toolbox/server/tool/invoke")
+	span.SetAttributes(attribute.String("tool_name", toolName))
+	defer func() {
+		status := "success"
+		if err != nil {
+			status = "error"
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		s.instrumentation.ToolInvoke.Add(
+			ctx,
+			1,
+			metric.WithAttributes(attribute.String("toolbox.name", toolName)),
+			metric.WithAttributes(attribute.String("toolbox.operation.status", status)),
+		)
+	}()
+
+	resources, release := s.resourceMgr.acquire()
+	defer release()
+	tool, ok := resources.tools[toolName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrToolNotFound, toolName)
+	}
+	if claims == nil {
+		claims = make(map[string]map[string]any)
+	}
+	if !tool.Authorized(verifiedAuthServiceNames(claims)) {
+		return nil, fmt.Errorf("%w: %q", ErrUnauthorized, toolName)
+	}
+	if params == nil {
+		params = make(map[string]any)
+	}
+	values, err := tool.ParseParams(params, claims)
+	if err != nil {
+		return nil, fmt.Errorf("provided parameters were invalid: %w", err)
+	}
+	res, err = tool.Invoke(ctx, values)
+	if err != nil {
+		return nil, fmt.Errorf("error while invoking tool: %w", err)
+	}
+	return res, nil
+}
+
+// ListTools returns the MCP manifests of the tools of a toolset, sorted by
+// name. The empty name refers to the toolset of every tool.
+func (s *Server) ListTools(toolsetName string) ([]tools.McpManifest, error) {
+	resources, release := s.resourceMgr.acquire()
+	defer release()
+	toolset, ok := resources.toolsets[toolsetName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrToolsetNotFound, toolsetName)
+	}
+	manifests := make([]tools.McpManifest, 0, len(toolset.Manifest.ToolsManifest))
+	for _, name := range slices.Sorted(maps.Keys(toolset.Manifest.ToolsManifest)) {
+		manifests = append(manifests, resources.tools[name].McpManifest())
+	}
+	return manifests, nil
+}
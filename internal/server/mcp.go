@@ -16,55 +16,241 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	"github.com/google/uuid"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp"
+	"github.com/googleapis/genai-toolbox/internal/sessions"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/metric"
 )
 
-type sseSession struct {
-	sessionId  string
-	writer     http.ResponseWriter
-	flusher    http.Flusher
-	done       chan struct{}
-	eventQueue chan string
+// sessionNotifier returns a function publishing messages to the event stream
+// of a session.
+func sessionNotifier(ctx context.Context, s *Server, sessionId string) func(msg any) error {
+	// events are delivered even if the request that produced them is
+	// cancelled, such as when the client does not wait for a response.
+	ctx = context.WithoutCancel(ctx)
+	return func(msg any) error {
+		eventData, err := json.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("unable to marshal message: %w", err)
+		}
+		return s.sessionStore.Publish(ctx, sessionId, eventData)
+	}
+}
+
+// mcpConn describes the connection an MCP message was received on.
+type mcpConn struct {
+	// id scopes the ids of the requests received on the connection. Requests
+	// received without a connection id cannot be cancelled by notification.
+	id string
+	// notify sends a server-initiated message to the client. It is nil if the
+	// connection is unable to carry them.
+	notify func(msg any) error
+	// protocolVersion is the protocol version responses are shaped for.
+	protocolVersion string
+}
+
+type mcpConnKey struct{}
+
+// withMcpConn adds the mcpConn a message was received on into the context.
+func withMcpConn(ctx context.Context, conn *mcpConn) context.Context {
+	return context.WithValue(ctx, mcpConnKey{}, conn)
+}
+
+// mcpConnFromContext retrieves the mcpConn a message was received on. A
+// connection using the latest protocol version is returned if there is none.
+func mcpConnFromContext(ctx context.Context) *mcpConn {
+	if conn, ok := ctx.Value(mcpConnKey{}).(*mcpConn); ok {
+		return conn
+	}
+	return &mcpConn{protocolVersion: mcp.LATEST_PROTOCOL_VERSION}
 }
 
-// sseManager manages and control access to sse sessions
-type sseManager struct {
-	mu          sync.RWMutex
-	sseSessions map[string]*sseSession
+// requestManager keeps track of in-flight requests so that they can be
+// cancelled with a notifications/cancelled message.
+type requestManager struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
 }
 
-func (m *sseManager) get(id string) (*sseSession, bool) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	session, ok := m.sseSessions[id]
-	return session, ok
+func newRequestManager() *requestManager {
+	return &requestManager{cancels: make(map[string]context.CancelFunc)}
 }
 
-func (m *sseManager) add(id string, session *sseSession) {
+func requestKey(connId string, id mcp.RequestId) string {
+	return fmt.Sprintf("%s/%v", connId, id)
+}
+
+func (m *requestManager) add(connId string, id mcp.RequestId, cancel context.CancelFunc) {
 	m.mu.Lock()
-	m.sseSessions[id] = session
+	m.cancels[requestKey(connId, id)] = cancel
 	m.mu.Unlock()
 }
 
-func (m *sseManager) remove(id string) {
+func (m *requestManager) remove(connId string, id mcp.RequestId) {
 	m.mu.Lock()
-	delete(m.sseSessions, id)
+	delete(m.cancels, requestKey(connId, id))
 	m.mu.Unlock()
 }
 
+// cancel cancels an in-flight request. It returns false if the request does
+// not exist or has already completed.
+func (m *requestManager) cancel(connId string, id mcp.RequestId) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cancel, ok := m.cancels[requestKey(connId, id)]
+	if ok {
+		cancel()
+		delete(m.cancels, requestKey(connId, id))
+	}
+	return ok
+}
+
+// eventStreamWriter writes messages to an HTTP response as server-sent
+// events. The headers are sent along with the first message.
+type eventStreamWriter struct {
+	mu          sync.Mutex
+	w           http.ResponseWriter
+	wroteHeader bool
+}
+
+func (e *eventStreamWriter) send(msg any) error {
+	eventData, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("unable to marshal message: %w", err)
+	}
+	return e.write(fmt.Sprintf("event: message\ndata: %s\n\n", eventData))
+}
+
+func (e *eventStreamWriter) write(event string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.wroteHeader {
+		e.w.Header().Set("Content-Type", "text/event-stream")
+		e.w.Header().Set("Cache-Control", "no-cache")
+		e.wroteHeader = true
+	}
+	if _, err := fmt.Fprint(e.w, event); err != nil {
+		return err
+	}
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// keepAlive writes a comment to the stream every interval, so that proxies
+// do not close it while a request is processed, until the returned function
+// is called.
+func (e *eventStreamWriter) keepAlive(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := e.write(keepAliveComment); err != nil {
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// keepAliveComment is written to idle event streams. Clients ignore comments.
+const keepAliveComment = ": keepalive\n\n"
+
+// lastEventId returns the id of the last event received by a client resuming
+// a stream, or 0 if the client is not resuming one.
+func lastEventId(r *http.Request) (uint64, error) {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Last-Event-ID header: %q", v)
+	}
+	return id, nil
+}
+
+// writeSessionEvents subscribes to the events of a session and writes the
+// events following lastEventId to the stream, until the client disconnects,
+// the session is terminated or another stream is attached to the session.
+func writeSessionEvents(ctx context.Context, s *Server, w http.ResponseWriter, flusher http.Flusher, sessionId string, lastEventId uint64) {
+	sub, err := s.sessionStore.Subscribe(ctx, sessionId, lastEventId)
+	if err != nil {
+		s.logger.DebugContext(ctx, fmt.Sprintf("unable to subscribe to session: %s", err))
+		return
+	}
+	if sub.Missed {
+		s.logger.WarnContext(ctx, fmt.Sprintf("session %s: events after %d are no longer available", sessionId, lastEventId))
+	}
+	// notify the session when the lists served change while it is streaming
+	defer s.listeners.add(sessionId, sessionNotifier(ctx, s, sessionId))()
+
+	var keepAlive <-chan time.Time
+	if s.sseKeepAliveInterval > 0 {
+		ticker := time.NewTicker(s.sseKeepAliveInterval)
+		defer ticker.Stop()
+		keepAlive = ticker.C
+	}
+
+	// the subscription ends once ctx is done, which happens when the client
+	// disconnects.
+	for {
+		select {
+		case e, ok := <-sub.Events:
+			if !ok {
+				s.logger.DebugContext(ctx, fmt.Sprintf("stream ended: %s", sub.Err()))
+				return
+			}
+			s.logger.DebugContext(ctx, fmt.Sprintf("sending event %d: %s", e.Id, e.Data))
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: message\ndata: %s\n\n", e.Id, e.Data); err != nil {
+				s.logger.DebugContext(ctx, fmt.Sprintf("unable to write event: %s", err))
+				return
+			}
+			flusher.Flush()
+		case <-keepAlive:
+			if _, err := fmt.Fprint(w, keepAliveComment); err != nil {
+				s.logger.DebugContext(ctx, fmt.Sprintf("unable to write keepalive: %s", err))
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
 // mcpRouter creates a router that represents the routes under /mcp
 func mcpRouter(s *Server) (chi.Router, error) {
 	r := chi.NewRouter()
@@ -74,11 +260,15 @@ func mcpRouter(s *Server) (chi.Router, error) {
 	r.Use(render.SetContentType(render.ContentTypeJSON))
 
 	r.Get("/sse", func(w http.ResponseWriter, r *http.Request) { sseHandler(s, w, r) })
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) { streamHandler(s, w, r) })
 	r.Post("/", func(w http.ResponseWriter, r *http.Request) { mcpHandler(s, w, r) })
+	r.Delete("/", func(w http.ResponseWriter, r *http.Request) { sessionDeleteHandler(s, w, r) })
 
 	r.Route("/{toolsetName}", func(r chi.Router) {
 		r.Get("/sse", func(w http.ResponseWriter, r *http.Request) { sseHandler(s, w, r) })
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) { streamHandler(s, w, r) })
 		r.Post("/", func(w http.ResponseWriter, r *http.Request) { mcpHandler(s, w, r) })
+		r.Delete("/", func(w http.ResponseWriter, r *http.Request) { sessionDeleteHandler(s, w, r) })
 	})
 
 	return r, nil
@@ -124,16 +314,45 @@ func sseHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		err = fmt.Errorf("unable to retrieve flusher for sse")
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+		return
 	}
-	session := &sseSession{
-		sessionId:  sessionId,
-		writer:     w,
-		flusher:    flusher,
-		done:       make(chan struct{}),
-		eventQueue: make(chan string, 100),
+	var lastId uint64
+	if lastId, err = lastEventId(r); err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	// Clients resume the stream of an existing session by passing its id.
+	if resumedId := r.URL.Query().Get("sessionId"); resumedId != "" {
+		var status int
+		if _, status, err = lookupSession(ctx, s, resumedId, toolsetName); err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, status))
+			return
+		}
+		sessionId = resumedId
+		span.SetAttributes(attribute.String("session_id", sessionId))
+		s.logger.DebugContext(ctx, fmt.Sprintf("resuming session %s after event %d", sessionId, lastId))
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+		writeSessionEvents(ctx, s, w, flusher, sessionId, lastId)
+		return
+	}
+
+	session := sessions.Session{
+		Id:          sessionId,
+		ToolsetName: toolsetName,
+		Claims:      getClaimsFromHeader(ctx, s, r.Header),
+		// sessions that have not been initialized yet are served with the
+		// version assumed for HTTP requests.
+		ProtocolVersion: mcp.DEFAULT_PROTOCOL_VERSION,
+	}
+	if err = s.sessionStore.Create(ctx, session); err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+		return
 	}
-	s.sseManager.add(sessionId, session)
-	defer s.sseManager.remove(sessionId)
 
 	// https scheme formatting if (forwarded) request is a TLS request
 	proto := r.Header.Get("X-Forwarded-Proto")
@@ -155,21 +374,26 @@ func sseHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, "event: endpoint\ndata: %s\n\n", messageEndpoint)
 	flusher.Flush()
 
-	clientClose := r.Context().Done()
-	for {
-		select {
-		// Ensure that only a single responses are written at once
-		case event := <-session.eventQueue:
-			fmt.Fprint(w, event)
-			s.logger.DebugContext(ctx, fmt.Sprintf("sending event: %s", event))
-			flusher.Flush()
-			// channel for client disconnection
-		case <-clientClose:
-			close(session.done)
-			s.logger.DebugContext(ctx, "client disconnected")
-			return
-		}
+	// The session outlives the stream, so that the client can resume it
+	// until it expires.
+	writeSessionEvents(ctx, s, w, flusher, sessionId, lastId)
+}
+
+// lookupSession returns the session with the given id, or an error along
+// with the HTTP status to respond with. Sessions are bound to the toolset they
+// were established with, and cannot be used through another toolset.
+func lookupSession(ctx context.Context, s *Server, sessionId, toolsetName string) (sessions.Session, int, error) {
+	session, err := s.sessionStore.Get(ctx, sessionId)
+	if errors.Is(err, sessions.ErrNotFound) {
+		return sessions.Session{}, http.StatusNotFound, fmt.Errorf("session %q does not exist", sessionId)
 	}
+	if err != nil {
+		return sessions.Session{}, http.StatusInternalServerError, err
+	}
+	if session.ToolsetName != toolsetName {
+		return sessions.Session{}, http.StatusBadRequest, fmt.Errorf("session is bound to toolset %q", session.ToolsetName)
+	}
+	return session, http.StatusOK, nil
 }
 
 // mcpHandler handles all mcp messages.
@@ -181,20 +405,286 @@ func mcpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	s.logger.DebugContext(ctx, fmt.Sprintf("toolset name: %s", toolsetName))
 	span.SetAttributes(attribute.String("toolset_name", toolsetName))
 
-	var id, toolName, method string
 	var err error
 	defer func() {
 		if err != nil {
 			span.SetStatus(codes.Error, err.Error())
 		}
 		span.End()
+	}()
+
+	// Read and returns a body from io.Reader
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		// Generate a new uuid if unable to decode
+		id := uuid.New().String()
+		s.logger.DebugContext(ctx, err.Error())
+		render.JSON(w, r, newJSONRPCError(id, mcp.PARSE_ERROR, err.Error(), nil))
+		return
+	}
+
+	// Sessions created through the legacy HTTP+SSE transport are identified by
+	// the `sessionId` query parameter, while sessions created through the
+	// Streamable HTTP transport are identified by the `Mcp-Session-Id` header.
+	sseSessionId := r.URL.Query().Get("sessionId")
+	sessionId := r.Header.Get(mcp.SESSION_ID_HEADER)
+
+	// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
+	// Claims verified when the session was established are used until their
+	// token expires, unless the request itself carries auth headers for the
+	// same auth service.
+	claimsFromAuth := make(map[string]map[string]any)
+	var session *sessions.Session
+	if sessionId != "" {
+		span.SetAttributes(attribute.String("session_id", sessionId))
+		found, status, err := lookupSession(ctx, s, sessionId, toolsetName)
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			render.Status(r, status)
+			code := mcp.INVALID_REQUEST
+			if status == http.StatusInternalServerError {
+				code = mcp.INTERNAL_ERROR
+			}
+			render.JSON(w, r, newJSONRPCError(nil, code, err.Error(), nil))
+			return
+		}
+		session = &found
+	} else if sseSessionId != "" {
+		if found, err := s.sessionStore.Get(ctx, sseSessionId); err == nil {
+			session = &found
+		}
+	}
+	if session != nil {
+		// Sessions are bound to the toolset they were established with
+		if session.ToolsetName != toolsetName {
+			err = fmt.Errorf("session is bound to toolset %q", session.ToolsetName)
+			s.logger.DebugContext(ctx, err.Error())
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, newJSONRPCError(nil, mcp.INVALID_REQUEST, err.Error(), nil))
+			return
+		}
+		if err := s.sessionStore.Touch(ctx, session.Id); err != nil {
+			s.logger.DebugContext(ctx, fmt.Sprintf("unable to touch session: %s", err))
+		}
+		maps.Copy(claimsFromAuth, session.ValidClaims(time.Now()))
+	}
+	maps.Copy(claimsFromAuth, getClaimsFromHeader(ctx, s, r.Header))
+
+	// Sessions use the protocol version negotiated during initialization.
+	// Other requests send it in a header.
+	protocolVersion := mcp.DEFAULT_PROTOCOL_VERSION
+	if v := r.Header.Get(mcp.PROTOCOL_VERSION_HEADER); v != "" {
+		if !mcp.IsSupportedProtocolVersion(v) {
+			err = fmt.Errorf("unsupported protocol version: %q", v)
+			s.logger.DebugContext(ctx, err.Error())
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, newJSONRPCError(nil, mcp.INVALID_REQUEST, err.Error(), map[string]any{"supported": mcp.SUPPORTED_PROTOCOL_VERSIONS, "requested": v}))
+			return
+		}
+		protocolVersion = v
+	}
+	if session != nil {
+		protocolVersion = session.ProtocolVersion
+	}
+
+	// Server-initiated messages, such as progress notifications, are written
+	// to the response stream when the client accepts one and to the session's
+	// event stream otherwise.
+	conn := &mcpConn{protocolVersion: protocolVersion}
+	if session != nil {
+		conn.id = session.Id
+		conn.notify = sessionNotifier(ctx, s, session.Id)
+	}
+	var stream *eventStreamWriter
+	if sseSessionId == "" && prefersEventStream(r, body) {
+		stream = &eventStreamWriter{w: w}
+		conn.notify = stream.send
+	}
+	ctx = withMcpConn(ctx, conn)
+
+	var res mcp.JSONRPCMessage
+	if stream != nil {
+		stopKeepAlive := stream.keepAlive(s.sseKeepAliveInterval)
+		res, err = processMcpBody(ctx, s, body, toolsetName, claimsFromAuth)
+		stopKeepAlive()
+	} else {
+		res, err = processMcpBody(ctx, s, body, toolsetName, claimsFromAuth)
+	}
+
+	// Notifications and cancelled requests do not expect a response
+	if res == nil {
+		if stream == nil || !stream.wroteHeader {
+			w.WriteHeader(http.StatusAccepted)
+		}
+		return
+	}
+
+	negotiatedVersion, initialized := initializedProtocolVersion(body, res)
+	if sseSessionId != "" {
+		// the response is sent on the event stream of the sse session
+		if session == nil {
+			s.logger.DebugContext(ctx, "sse session not available")
+		} else {
+			if initialized {
+				if err := s.sessionStore.SetProtocolVersion(ctx, session.Id, negotiatedVersion); err != nil {
+					s.logger.DebugContext(ctx, fmt.Sprintf("unable to set protocol version: %s", err))
+				}
+			}
+			if err := sessionNotifier(ctx, s, session.Id)(res); err != nil {
+				s.logger.DebugContext(ctx, err.Error())
+			} else {
+				s.logger.DebugContext(ctx, "event published")
+			}
+		}
+	} else if sessionId == "" && initialized {
+		// start a new Streamable HTTP session for a successful initialization
+		newSession := sessions.Session{
+			Id:              uuid.New().String(),
+			ToolsetName:     toolsetName,
+			Claims:          claimsFromAuth,
+			ProtocolVersion: negotiatedVersion,
+		}
+		if createErr := s.sessionStore.Create(ctx, newSession); createErr != nil {
+			// the client cannot continue without a session
+			err = fmt.Errorf("unable to create session: %w", createErr)
+			s.logger.ErrorContext(ctx, err.Error())
+			res = newJSONRPCError(res.(mcp.JSONRPCResponse).Id, mcp.INTERNAL_ERROR, err.Error(), nil)
+			if stream == nil {
+				render.Status(r, http.StatusInternalServerError)
+			}
+		} else {
+			span.SetAttributes(attribute.String("session_id", newSession.Id))
+			s.logger.DebugContext(ctx, fmt.Sprintf("created streamable http session: %s", newSession.Id))
+			w.Header().Set(mcp.SESSION_ID_HEADER, newSession.Id)
+		}
+	}
+
+	// send HTTP response
+	if stream != nil {
+		if err := stream.send(res); err != nil {
+			s.logger.DebugContext(ctx, fmt.Sprintf("unable to write response: %s", err))
+		}
+		return
+	}
+	render.JSON(w, r, res)
+}
+
+// processMcpBody processes a request body that could either be a single
+// JSON-RPC message or a JSON-RPC batch. A nil response is returned when no
+// response is expected.
+func processMcpBody(ctx context.Context, s *Server, body []byte, toolsetName string, claimsFromAuth map[string]map[string]any) (mcp.JSONRPCMessage, error) {
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		return processMcpBatch(ctx, s, body, toolsetName, claimsFromAuth)
+	}
+	return processMcpMessage(ctx, s, body, toolsetName, claimsFromAuth)
+}
+
+// maxBatchConcurrency is the maximum number of messages of a JSON-RPC batch
+// that are processed concurrently.
+const maxBatchConcurrency = 10
+
+// processMcpBatch dispatches each message of a JSON-RPC batch concurrently.
+// The response is a list containing one entry per request, in the order the
+// requests were received. Notifications are omitted from the response.
+func processMcpBatch(ctx context.Context, s *Server, body []byte, toolsetName string, claimsFromAuth map[string]map[string]any) (mcp.JSONRPCMessage, error) {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		// Generate a new uuid if unable to decode
+		id := uuid.New().String()
+		s.logger.DebugContext(ctx, err.Error())
+		return newJSONRPCError(id, mcp.PARSE_ERROR, err.Error(), nil), err
+	}
+	if len(batch) == 0 {
+		err := fmt.Errorf("invalid request: empty batch")
+		s.logger.DebugContext(ctx, err.Error())
+		return newJSONRPCError(nil, mcp.INVALID_REQUEST, err.Error(), nil), err
+	}
+	s.logger.DebugContext(ctx, fmt.Sprintf("processing batch of %d messages", len(batch)))
+
+	results := make([]mcp.JSONRPCMessage, len(batch))
+	errs := make([]error, len(batch))
+	// sem limits the number of messages of the batch processed at once
+	sem := make(chan struct{}, maxBatchConcurrency)
+	// seenIds are the ids of the requests of the batch, which must be unique
+	// for their responses and cancellations to refer to a single request
+	seenIds := make(map[string]bool)
+	var wg sync.WaitGroup
+	for i, msg := range batch {
+		// Each element of a batch must be a request or notification object
+		if trimmed := bytes.TrimSpace(msg); len(trimmed) == 0 || trimmed[0] != '{' {
+			errs[i] = fmt.Errorf("invalid request: batch element is not an object")
+			s.logger.DebugContext(ctx, errs[i].Error())
+			results[i] = newJSONRPCError(nil, mcp.INVALID_REQUEST, errs[i].Error(), nil)
+			continue
+		}
+		if key, id, ok := batchRequestId(msg); ok {
+			if seenIds[key] {
+				errs[i] = fmt.Errorf("invalid request: duplicate id %s in batch", key)
+				s.logger.DebugContext(ctx, errs[i].Error())
+				results[i] = newJSONRPCError(id, mcp.INVALID_REQUEST, errs[i].Error(), nil)
+				continue
+			}
+			seenIds[key] = true
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i], errs[i] = processMcpMessage(ctx, s, msg, toolsetName, claimsFromAuth)
+		}()
+	}
+	wg.Wait()
+
+	res := make([]mcp.JSONRPCMessage, 0, len(results))
+	for _, r := range results {
+		// Notifications do not expect a response
+		if r != nil {
+			res = append(res, r)
+		}
+	}
+	err := errors.Join(errs...)
+	if len(res) == 0 {
+		return nil, err
+	}
+	return res, err
+}
+
+// batchRequestId returns the id of the request msg, both as its compact JSON
+// encoding and decoded. ok is false for notifications and invalid messages,
+// which are reported when processing them.
+func batchRequestId(msg []byte) (key string, id mcp.RequestId, ok bool) {
+	var header struct {
+		Id json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(msg, &header); err != nil || len(header.Id) == 0 {
+		return "", nil, false
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, header.Id); err != nil || compact.String() == "null" {
+		return "", nil, false
+	}
+	if err := json.Unmarshal(header.Id, &id); err != nil {
+		return "", nil, false
+	}
+	return compact.String(), id, true
+}
 
+// processMcpMessage parses a single JSON-RPC message and dispatches it to the
+// corresponding MCP method. claimsFromAuth maps the name of each verified auth
+// service to its claims. A nil response is returned for notifications.
+func processMcpMessage(ctx context.Context, s *Server, body []byte, toolsetName string, claimsFromAuth map[string]map[string]any) (mcp.JSONRPCMessage, error) {
+	var id, toolName, method string
+	var err error
+	defer func() {
 		status := "success"
 		if err != nil {
 			status = "error"
 		}
 		s.instrumentation.McpPost.Add(
-			r.Context(),
+			ctx,
 			1,
 			metric.WithAttributes(attribute.String("toolbox.sse.sessionId", id)),
 			metric.WithAttributes(attribute.String("toolbox.tool.name", toolName)),
@@ -204,15 +694,6 @@ func mcpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		)
 	}()
 
-	// Read and returns a body from io.Reader
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		// Generate a new uuid if unable to decode
-		id = uuid.New().String()
-		s.logger.DebugContext(ctx, err.Error())
-		render.JSON(w, r, newJSONRPCError(id, mcp.PARSE_ERROR, err.Error(), nil))
-	}
-
 	// Generic baseMessage could either be a JSONRPCNotification or JSONRPCRequest
 	var baseMessage struct {
 		Jsonrpc string        `json:"jsonrpc"`
@@ -223,168 +704,450 @@ func mcpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		// Generate a new uuid if unable to decode
 		id := uuid.New().String()
 		s.logger.DebugContext(ctx, err.Error())
-		render.JSON(w, r, newJSONRPCError(id, mcp.PARSE_ERROR, err.Error(), nil))
-		return
+		return newJSONRPCError(id, mcp.PARSE_ERROR, err.Error(), nil), err
 	}
 
 	// Check if method is present
 	if baseMessage.Method == "" {
 		err = fmt.Errorf("method not found")
 		s.logger.DebugContext(ctx, err.Error())
-		render.JSON(w, r, newJSONRPCError(baseMessage.Id, mcp.METHOD_NOT_FOUND, err.Error(), nil))
-		return
+		return newJSONRPCError(baseMessage.Id, mcp.METHOD_NOT_FOUND, err.Error(), nil), err
 	}
 
 	// Check for JSON-RPC 2.0
 	if baseMessage.Jsonrpc != mcp.JSONRPC_VERSION {
 		err = fmt.Errorf("invalid json-rpc version")
 		s.logger.DebugContext(ctx, err.Error())
-		render.JSON(w, r, newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil))
-		return
+		return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
 	}
 
+	conn := mcpConnFromContext(ctx)
+
 	// Check if message is a notification
 	if baseMessage.Id == nil {
-		id = ""
-		var notification mcp.JSONRPCNotification
-		if err = json.Unmarshal(body, &notification); err != nil {
-			err = fmt.Errorf("invalid notification request: %w", err)
-			s.logger.DebugContext(ctx, err.Error())
-			render.JSON(w, r, newJSONRPCError(baseMessage.Id, mcp.PARSE_ERROR, err.Error(), nil))
-		}
 		// Notifications do not expect a response
-		// Toolbox doesn't do anything with notifications yet
-		w.WriteHeader(http.StatusAccepted)
-		return
+		err = processMcpNotification(ctx, s, conn, baseMessage.Method, body)
+		return nil, err
 	}
+
+	// the resources are not replaced while the message is processed
+	resources, release := s.resourceMgr.acquire()
+	defer release()
+
 	id = fmt.Sprintf("%s", baseMessage.Id)
 	method = baseMessage.Method
 	s.logger.DebugContext(ctx, fmt.Sprintf("method is: %s", method))
 
-	var res mcp.JSONRPCMessage
+	// Requests received on a connection can be cancelled by the client
+	if conn.id != "" {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		s.requestManager.add(conn.id, baseMessage.Id, cancel)
+		defer func() {
+			s.requestManager.remove(conn.id, baseMessage.Id)
+			cancel()
+		}()
+	}
+
 	switch baseMessage.Method {
 	case "initialize":
 		var req mcp.InitializeRequest
 		if err = json.Unmarshal(body, &req); err != nil {
 			err = fmt.Errorf("invalid mcp initialize request: %w", err)
 			s.logger.DebugContext(ctx, err.Error())
-			res = newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil)
-			break
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
 		}
-		result := mcp.Initialize(s.version)
-		res = mcp.JSONRPCResponse{
+		var result mcp.InitializeResult
+		result, err = mcp.Initialize(s.version, req.Params.ProtocolVersion)
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			data := map[string]any{"supported": mcp.SUPPORTED_PROTOCOL_VERSIONS, "requested": req.Params.ProtocolVersion}
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), data), err
+		}
+		s.logger.DebugContext(ctx, fmt.Sprintf("negotiated protocol version: %s", result.ProtocolVersion))
+		return mcp.JSONRPCResponse{
 			Jsonrpc: mcp.JSONRPC_VERSION,
 			Id:      baseMessage.Id,
 			Result:  result,
-		}
+		}, nil
 	case "tools/list":
 		var req mcp.ListToolsRequest
 		if err = json.Unmarshal(body, &req); err != nil {
 			err = fmt.Errorf("invalid mcp tools list request: %w", err)
 			s.logger.DebugContext(ctx, err.Error())
-			res = newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil)
-			break
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
 		}
-		toolset, ok := s.toolsets[toolsetName]
+		toolset, ok := resources.toolsets[toolsetName]
 		if !ok {
 			err = fmt.Errorf("toolset does not exist")
 			s.logger.DebugContext(ctx, err.Error())
-			res = newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil)
-			break
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
 		}
-		result := mcp.ToolsList(toolset)
-		res = mcp.JSONRPCResponse{
+		result := mcp.ToolsList(toolset, conn.protocolVersion)
+		return mcp.JSONRPCResponse{
 			Jsonrpc: mcp.JSONRPC_VERSION,
 			Id:      baseMessage.Id,
 			Result:  result,
-		}
+		}, nil
 	case "tools/call":
 		var req mcp.CallToolRequest
 		if err = json.Unmarshal(body, &req); err != nil {
 			err = fmt.Errorf("invalid mcp tools call request: %w", err)
 			s.logger.DebugContext(ctx, err.Error())
-			res = newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil)
-			break
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
 		}
 		toolName = req.Params.Name
 		toolArgument := req.Params.Arguments
 		s.logger.DebugContext(ctx, fmt.Sprintf("tool name: %s", toolName))
-		tool, ok := s.tools[toolName]
+		toolset, ok := resources.toolsets[toolsetName]
+		if !ok {
+			err = fmt.Errorf("toolset does not exist")
+			s.logger.DebugContext(ctx, err.Error())
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+		// tools outside of the toolset are treated as if they do not exist
+		if _, ok = toolset.Manifest.ToolsManifest[toolName]; !ok {
+			err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
+			s.logger.DebugContext(ctx, err.Error())
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil), err
+		}
+		tool, ok := resources.tools[toolName]
 		if !ok {
 			err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
 			s.logger.DebugContext(ctx, err.Error())
-			res = newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil)
-			break
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil), err
 		}
 
 		// marshal arguments and decode it using decodeJSON instead to prevent loss between floats/int.
-		aMarshal, err := json.Marshal(toolArgument)
+		var aMarshal []byte
+		aMarshal, err = json.Marshal(toolArgument)
 		if err != nil {
 			err = fmt.Errorf("unable to marshal tools argument: %w", err)
 			s.logger.DebugContext(ctx, err.Error())
-			res = newJSONRPCError(baseMessage.Id, mcp.INTERNAL_ERROR, err.Error(), nil)
-			break
+			return newJSONRPCError(baseMessage.Id, mcp.INTERNAL_ERROR, err.Error(), nil), err
 		}
 		var data map[string]any
 		if err = decodeJSON(bytes.NewBuffer(aMarshal), &data); err != nil {
 			err = fmt.Errorf("unable to decode tools argument: %w", err)
 			s.logger.DebugContext(ctx, err.Error())
-			res = newJSONRPCError(baseMessage.Id, mcp.INTERNAL_ERROR, err.Error(), nil)
-			break
+			return newJSONRPCError(baseMessage.Id, mcp.INTERNAL_ERROR, err.Error(), nil), err
 		}
 
-		// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
-		// Since MCP doesn't support auth, an empty map will be use every time.
-		claimsFromAuth := make(map[string]map[string]any)
-
-		params, err := tool.ParseParams(data, claimsFromAuth)
+		var params tools.ParamValues
+		params, err = tool.ParseParams(data, claimsFromAuth)
 		if err != nil {
 			err = fmt.Errorf("provided parameters were invalid: %w", err)
 			s.logger.DebugContext(ctx, err.Error())
-			res = newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil)
-			break
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil), err
 		}
 		s.logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
 
-		if !tool.Authorized([]string{}) {
+		// Tool authorization check
+		if !tool.Authorized(verifiedAuthServiceNames(claimsFromAuth)) {
 			err = fmt.Errorf("unauthorized Tool call: `authRequired` is set for the target Tool")
 			s.logger.DebugContext(ctx, err.Error())
-			res = newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil)
-			break
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+
+		// Report progress if the client asked for it
+		if token := req.Params.Meta.ProgressToken; token != nil && conn.notify != nil {
+			ctx = tools.WithProgressReporter(ctx, func(progress, total float64, message string) {
+				notification := mcp.ProgressNotification{
+					Jsonrpc: mcp.JSONRPC_VERSION,
+					Method:  "notifications/progress",
+					Params: mcp.ProgressNotificationParams{
+						ProgressToken: token,
+						Progress:      progress,
+						Total:         total,
+						Message:       message,
+					},
+				}
+				if err := conn.notify(notification); err != nil {
+					s.logger.DebugContext(ctx, fmt.Sprintf("unable to send progress notification: %s", err))
+				}
+			})
 		}
 
-		result := mcp.ToolCall(ctx, tool, params)
-		res = mcp.JSONRPCResponse{
+		result := mcp.ToolCall(ctx, tool, params, conn.protocolVersion)
+		// Cancelled requests do not expect a response
+		if ctx.Err() != nil {
+			err = fmt.Errorf("tool call was cancelled: %w", ctx.Err())
+			s.logger.DebugContext(ctx, err.Error())
+			return nil, err
+		}
+		return mcp.JSONRPCResponse{
 			Jsonrpc: mcp.JSONRPC_VERSION,
 			Id:      baseMessage.Id,
 			Result:  result,
+		}, nil
+	case "resources/list":
+		var req mcp.ListResourcesRequest
+		if err = json.Unmarshal(body, &req); err != nil {
+			err = fmt.Errorf("invalid mcp resources list request: %w", err)
+			s.logger.DebugContext(ctx, err.Error())
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+		// only the sources of the toolset's tools are exposed
+		result := mcp.ResourcesList(resources.toolsetSources(toolsetName))
+		return mcp.JSONRPCResponse{
+			Jsonrpc: mcp.JSONRPC_VERSION,
+			Id:      baseMessage.Id,
+			Result:  result,
+		}, nil
+	case "resources/templates/list":
+		var req mcp.ListResourceTemplatesRequest
+		if err = json.Unmarshal(body, &req); err != nil {
+			err = fmt.Errorf("invalid mcp resource templates list request: %w", err)
+			s.logger.DebugContext(ctx, err.Error())
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
 		}
+		result := mcp.ResourceTemplatesList()
+		return mcp.JSONRPCResponse{
+			Jsonrpc: mcp.JSONRPC_VERSION,
+			Id:      baseMessage.Id,
+			Result:  result,
+		}, nil
+	case "resources/read":
+		var req mcp.ReadResourceRequest
+		if err = json.Unmarshal(body, &req); err != nil {
+			err = fmt.Errorf("invalid mcp resources read request: %w", err)
+			s.logger.DebugContext(ctx, err.Error())
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+		s.logger.DebugContext(ctx, fmt.Sprintf("resource uri: %s", req.Params.URI))
+		var result mcp.ReadResourceResult
+		result, err = mcp.ResourceRead(ctx, resources.toolsetSources(toolsetName), req.Params.URI)
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			var notFound mcp.ResourceNotFoundError
+			if errors.As(err, &notFound) {
+				return newJSONRPCError(baseMessage.Id, mcp.RESOURCE_NOT_FOUND, err.Error(), map[string]any{"uri": req.Params.URI}), err
+			}
+			return newJSONRPCError(baseMessage.Id, mcp.INTERNAL_ERROR, err.Error(), nil), err
+		}
+		return mcp.JSONRPCResponse{
+			Jsonrpc: mcp.JSONRPC_VERSION,
+			Id:      baseMessage.Id,
+			Result:  result,
+		}, nil
+	case "prompts/list":
+		var req mcp.ListPromptsRequest
+		if err = json.Unmarshal(body, &req); err != nil {
+			err = fmt.Errorf("invalid mcp prompts list request: %w", err)
+			s.logger.DebugContext(ctx, err.Error())
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+		result := mcp.PromptsList(resources.prompts)
+		return mcp.JSONRPCResponse{
+			Jsonrpc: mcp.JSONRPC_VERSION,
+			Id:      baseMessage.Id,
+			Result:  result,
+		}, nil
+	case "prompts/get":
+		var req mcp.GetPromptRequest
+		if err = json.Unmarshal(body, &req); err != nil {
+			err = fmt.Errorf("invalid mcp prompts get request: %w", err)
+			s.logger.DebugContext(ctx, err.Error())
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_REQUEST, err.Error(), nil), err
+		}
+		promptName := req.Params.Name
+		s.logger.DebugContext(ctx, fmt.Sprintf("prompt name: %s", promptName))
+		prompt, ok := resources.prompts[promptName]
+		if !ok {
+			err = fmt.Errorf("invalid prompt name: prompt with name %q does not exist", promptName)
+			s.logger.DebugContext(ctx, err.Error())
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil), err
+		}
+		var result mcp.GetPromptResult
+		result, err = mcp.PromptGet(prompt, req.Params.Arguments)
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			return newJSONRPCError(baseMessage.Id, mcp.INVALID_PARAMS, err.Error(), nil), err
+		}
+		return mcp.JSONRPCResponse{
+			Jsonrpc: mcp.JSONRPC_VERSION,
+			Id:      baseMessage.Id,
+			Result:  result,
+		}, nil
 	default:
 		err = fmt.Errorf("invalid method %s", baseMessage.Method)
 		s.logger.DebugContext(ctx, err.Error())
-		res = newJSONRPCError(baseMessage.Id, mcp.METHOD_NOT_FOUND, err.Error(), nil)
+		return newJSONRPCError(baseMessage.Id, mcp.METHOD_NOT_FOUND, err.Error(), nil), err
 	}
+}
 
-	// retrieve sse session
-	sessionId := r.URL.Query().Get("sessionId")
-	session, ok := s.sseManager.get(sessionId)
-	if !ok {
-		s.logger.DebugContext(ctx, "sse session not available")
-	} else {
-		// queue sse event
-		eventData, _ := json.Marshal(res)
-		select {
-		case session.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
-			s.logger.DebugContext(ctx, "event queue successful")
-		case <-session.done:
-			s.logger.DebugContext(ctx, "session is close")
-		default:
-			s.logger.DebugContext(ctx, "unable to add to event queue")
+// processMcpNotification handles a JSON-RPC notification received on conn.
+func processMcpNotification(ctx context.Context, s *Server, conn *mcpConn, method string, body []byte) error {
+	switch method {
+	case "notifications/cancelled":
+		var notification mcp.CancelledNotification
+		if err := decodeJSON(bytes.NewBuffer(body), &notification); err != nil {
+			err = fmt.Errorf("invalid cancelled notification: %w", err)
+			s.logger.DebugContext(ctx, err.Error())
+			return err
+		}
+		requestId := notification.Params.RequestId
+		// Requests might have completed before the notification arrived
+		if conn.id == "" || !s.requestManager.cancel(conn.id, requestId) {
+			s.logger.DebugContext(ctx, fmt.Sprintf("unable to cancel request %v: request is not in progress", requestId))
+			return nil
+		}
+		s.logger.DebugContext(ctx, fmt.Sprintf("cancelled request %v: %s", requestId, notification.Params.Reason))
+		return nil
+	default:
+		var notification mcp.JSONRPCNotification
+		if err := json.Unmarshal(body, &notification); err != nil {
+			err = fmt.Errorf("invalid notification request: %w", err)
+			s.logger.DebugContext(ctx, err.Error())
+			return err
 		}
+		return nil
 	}
+}
 
-	// send HTTP response
-	render.JSON(w, r, res)
+// streamHandler opens a stream for server-initiated messages on an existing
+// Streamable HTTP session.
+func streamHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/mcp/stream")
+	r = r.WithContext(ctx)
+	defer span.End()
+
+	sessionId := r.Header.Get(mcp.SESSION_ID_HEADER)
+	span.SetAttributes(attribute.String("session_id", sessionId))
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		err := fmt.Errorf("client must accept text/event-stream")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotAcceptable))
+		return
+	}
+	if sessionId == "" {
+		err := fmt.Errorf("missing %s header", mcp.SESSION_ID_HEADER)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	if _, status, err := lookupSession(ctx, s, sessionId, chi.URLParam(r, "toolsetName")); err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, status))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		err := fmt.Errorf("unable to retrieve flusher for sse")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	lastId, err := lastEventId(r)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	writeSessionEvents(ctx, s, w, flusher, sessionId, lastId)
+}
+
+// sessionDeleteHandler terminates a Streamable HTTP session.
+func sessionDeleteHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/mcp/delete")
+	r = r.WithContext(ctx)
+	defer span.End()
+
+	sessionId := r.Header.Get(mcp.SESSION_ID_HEADER)
+	span.SetAttributes(attribute.String("session_id", sessionId))
+	if sessionId == "" {
+		err := fmt.Errorf("missing %s header", mcp.SESSION_ID_HEADER)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	if _, status, err := lookupSession(ctx, s, sessionId, chi.URLParam(r, "toolsetName")); err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, status))
+		return
+	}
+	err := s.sessionStore.Delete(ctx, sessionId)
+	if errors.Is(err, sessions.ErrNotFound) {
+		err = fmt.Errorf("session %q does not exist", sessionId)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+		return
+	}
+	s.logger.DebugContext(ctx, fmt.Sprintf("terminated session: %s", sessionId))
+	w.WriteHeader(http.StatusOK)
+}
+
+// initializedProtocolVersion returns the protocol version negotiated by an
+// initialize request. It reports false if the message is not an initialize
+// request that was processed successfully.
+func initializedProtocolVersion(body []byte, res mcp.JSONRPCMessage) (string, bool) {
+	var baseMessage struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(body, &baseMessage); err != nil || baseMessage.Method != "initialize" {
+		return "", false
+	}
+	resp, ok := res.(mcp.JSONRPCResponse)
+	if !ok {
+		return "", false
+	}
+	result, ok := resp.Result.(mcp.InitializeResult)
+	if !ok {
+		return "", false
+	}
+	return result.ProtocolVersion, true
+}
+
+// prefersEventStream reports whether the response to body should be returned
+// as an SSE stream instead of a JSON object. Clients accepting both get a
+// stream for tool calls asking for progress, so that progress notifications
+// are sent with the response.
+func prefersEventStream(r *http.Request, body []byte) bool {
+	accept := r.Header.Get("Accept")
+	if !strings.Contains(accept, "text/event-stream") {
+		return false
+	}
+	return !strings.Contains(accept, "application/json") || requestsProgress(body)
+}
+
+// requestsProgress reports whether body, a JSON-RPC message or batch, has a
+// tool call with a progress token.
+func requestsProgress(body []byte) bool {
+	type progressRequest struct {
+		Method string `json:"method"`
+		Params struct {
+			Meta struct {
+				ProgressToken any `json:"progressToken"`
+			} `json:"_meta"`
+		} `json:"params"`
+	}
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		batch = []json.RawMessage{body}
+	}
+	for _, raw := range batch {
+		var req progressRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			continue
+		}
+		if req.Method == "tools/call" && req.Params.Meta.ProgressToken != nil {
+			return true
+		}
+	}
+	return false
 }
 
 // newJSONRPCError is the response sent back when an error has been encountered in mcp.
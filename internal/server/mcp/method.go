@@ -18,15 +18,68 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
-func Initialize(version string) InitializeResult {
-	toolsListChanged := false
+// UnsupportedProtocolVersionError is returned when toolbox does not support
+// any protocol version shared with the client.
+type UnsupportedProtocolVersionError struct {
+	Requested string
+}
+
+func (e UnsupportedProtocolVersionError) Error() string {
+	return fmt.Sprintf("unsupported protocol version: %q, supported versions are %q", e.Requested, SUPPORTED_PROTOCOL_VERSIONS)
+}
+
+// NegotiateProtocolVersion returns the protocol version requested by a client
+// if toolbox supports it. The latest version is returned if the client does
+// not request one.
+func NegotiateProtocolVersion(protocolVersion string) (string, error) {
+	if protocolVersion == "" {
+		return LATEST_PROTOCOL_VERSION, nil
+	}
+	if !IsSupportedProtocolVersion(protocolVersion) {
+		return "", UnsupportedProtocolVersionError{Requested: protocolVersion}
+	}
+	return protocolVersion, nil
+}
+
+// IsSupportedProtocolVersion reports whether toolbox is able to serve protocolVersion.
+func IsSupportedProtocolVersion(protocolVersion string) bool {
+	return slices.Contains(SUPPORTED_PROTOCOL_VERSIONS, protocolVersion)
+}
+
+// isOlderProtocolVersion reports whether protocolVersion was released before
+// other. Both versions must be supported by toolbox.
+func isOlderProtocolVersion(protocolVersion, other string) bool {
+	return slices.Index(SUPPORTED_PROTOCOL_VERSIONS, protocolVersion) > slices.Index(SUPPORTED_PROTOCOL_VERSIONS, other)
+}
+
+// Initialize returns an InitializeResult using the protocol version
+// negotiated with the client.
+func Initialize(version string, protocolVersion string) (InitializeResult, error) {
+	protocolVersion, err := NegotiateProtocolVersion(protocolVersion)
+	if err != nil {
+		return InitializeResult{}, err
+	}
+	// clients are notified when the lists change on reload
+	toolsListChanged := true
+	resourcesListChanged := true
+	promptsListChanged := true
 	result := InitializeResult{
-		ProtocolVersion: LATEST_PROTOCOL_VERSION,
+		ProtocolVersion: protocolVersion,
 		Capabilities: ServerCapabilities{
+			Prompts: &ListChanged{
+				ListChanged: &promptsListChanged,
+			},
+			Resources: &ListChanged{
+				ListChanged: &resourcesListChanged,
+			},
 			Tools: &ListChanged{
 				ListChanged: &toolsListChanged,
 			},
@@ -36,12 +89,25 @@ func Initialize(version string) InitializeResult {
 			Version: version,
 		},
 	}
-	return result
+	return result, nil
 }
 
-// ToolsList return a ListToolsResult
-func ToolsList(toolset tools.Toolset) ListToolsResult {
+// ToolsList return a ListToolsResult. Fields that were introduced after
+// protocolVersion are omitted from the tool definitions.
+func ToolsList(toolset tools.Toolset, protocolVersion string) ListToolsResult {
 	mcpManifest := toolset.McpManifest
+	if isOlderProtocolVersion(protocolVersion, PROTOCOL_VERSION_2025_06_18) {
+		mcpManifest = make([]tools.McpManifest, 0, len(toolset.McpManifest))
+		for _, m := range toolset.McpManifest {
+			// output schemas were added in 2025-06-18
+			m.OutputSchema = nil
+			if isOlderProtocolVersion(protocolVersion, PROTOCOL_VERSION_2025_03_26) {
+				// annotations were added in 2025-03-26
+				m.Annotations = nil
+			}
+			mcpManifest = append(mcpManifest, m)
+		}
+	}
 
 	result := ListToolsResult{
 		Tools: mcpManifest,
@@ -49,8 +115,11 @@ func ToolsList(toolset tools.Toolset) ListToolsResult {
 	return result
 }
 
-// ToolCall runs tool invocation and return a CallToolResult
-func ToolCall(ctx context.Context, tool tools.Tool, params tools.ParamValues) CallToolResult {
+// ToolCall runs tool invocation and return a CallToolResult. Every value
+// returned by the tool is sent as a JSON text content. Clients using protocol
+// version 2025-06-18 or later also receive them as structured content, and an
+// error if they do not match the output schema of the tool.
+func ToolCall(ctx context.Context, tool tools.Tool, params tools.ParamValues, protocolVersion string) CallToolResult {
 	res, err := tool.Invoke(ctx, params)
 	if err != nil {
 		text := TextContent{
@@ -71,5 +140,131 @@ func ToolCall(ctx context.Context, tool tools.Tool, params tools.ParamValues) Ca
 		}
 		content = append(content, text)
 	}
-	return CallToolResult{Content: content}
+	result := CallToolResult{Content: content}
+	if !isOlderProtocolVersion(protocolVersion, PROTOCOL_VERSION_2025_06_18) {
+		// the structured content must match the output schema listed for the tool
+		if schema := tool.McpManifest().OutputSchema; schema != nil {
+			if err := schema.Validate(res); err != nil {
+				text := TextContent{
+					Type: "text",
+					Text: err.Error(),
+				}
+				return CallToolResult{Content: []TextContent{text}, IsError: true}
+			}
+		}
+		result.StructuredContent = tools.StructuredResult(res)
+	}
+	return result
+}
+
+// schemaResourceTemplate is the URI template of resources describing the
+// schema of a source.
+const schemaResourceTemplate = "toolbox://sources/{sourceName}/schema"
+
+// schemaResourceURI returns the URI of the resource describing the schema of a source.
+func schemaResourceURI(sourceName string) string {
+	return strings.Replace(schemaResourceTemplate, "{sourceName}", sourceName, 1)
+}
+
+// ResourcesList returns a ListResourcesResult with a schema resource for every
+// source of srcs that is able to describe its schema.
+func ResourcesList(srcs map[string]sources.Source) ListResourcesResult {
+	resources := make([]Resource, 0)
+	for name, src := range srcs {
+		if _, ok := src.(sources.SchemaSource); !ok {
+			continue
+		}
+		resources = append(resources, Resource{
+			URI:         schemaResourceURI(name),
+			Name:        fmt.Sprintf("%s schema", name),
+			Description: fmt.Sprintf("Schema of the %q %s source.", name, src.SourceKind()),
+			MimeType:    "application/json",
+		})
+	}
+	slices.SortFunc(resources, func(a, b Resource) int { return strings.Compare(a.URI, b.URI) })
+	return ListResourcesResult{Resources: resources}
+}
+
+// ResourceTemplatesList returns a ListResourceTemplatesResult
+func ResourceTemplatesList() ListResourceTemplatesResult {
+	return ListResourceTemplatesResult{
+		ResourceTemplates: []ResourceTemplate{
+			{
+				URITemplate: schemaResourceTemplate,
+				Name:        "source schema",
+				Description: "Schema of a source, such as its tables and columns.",
+				MimeType:    "application/json",
+			},
+		},
+	}
+}
+
+// ResourceNotFoundError is returned when the requested resource does not exist.
+type ResourceNotFoundError struct {
+	URI string
+}
+
+func (e ResourceNotFoundError) Error() string {
+	return fmt.Sprintf("resource not found: %s", e.URI)
+}
+
+// ResourceRead reads the resource identified by uri and returns a ReadResourceResult
+func ResourceRead(ctx context.Context, srcs map[string]sources.Source, uri string) (ReadResourceResult, error) {
+	name, ok := strings.CutPrefix(uri, "toolbox://sources/")
+	if !ok {
+		return ReadResourceResult{}, ResourceNotFoundError{uri}
+	}
+	name, ok = strings.CutSuffix(name, "/schema")
+	if !ok {
+		return ReadResourceResult{}, ResourceNotFoundError{uri}
+	}
+	src, ok := srcs[name].(sources.SchemaSource)
+	if !ok {
+		return ReadResourceResult{}, ResourceNotFoundError{uri}
+	}
+
+	schema, err := src.Schema(ctx)
+	if err != nil {
+		return ReadResourceResult{}, fmt.Errorf("unable to retrieve schema of source %q: %w", name, err)
+	}
+	text, err := json.Marshal(schema)
+	if err != nil {
+		return ReadResourceResult{}, fmt.Errorf("unable to marshal schema of source %q: %w", name, err)
+	}
+	contents := TextResourceContents{
+		URI:      uri,
+		MimeType: "application/json",
+		Text:     string(text),
+	}
+	return ReadResourceResult{Contents: []TextResourceContents{contents}}, nil
+}
+
+// PromptsList returns a ListPromptsResult
+func PromptsList(ps map[string]prompts.Prompt) ListPromptsResult {
+	manifests := make([]prompts.McpManifest, 0, len(ps))
+	for _, p := range ps {
+		manifests = append(manifests, p.McpManifest())
+	}
+	slices.SortFunc(manifests, func(a, b prompts.McpManifest) int { return strings.Compare(a.Name, b.Name) })
+	return ListPromptsResult{Prompts: manifests}
+}
+
+// PromptGet renders a prompt with the given arguments and returns a GetPromptResult
+func PromptGet(prompt prompts.Prompt, args map[string]string) (GetPromptResult, error) {
+	params, err := prompt.ParseArgs(args)
+	if err != nil {
+		return GetPromptResult{}, fmt.Errorf("provided arguments failed validation: %w", err)
+	}
+	msgs, err := prompt.Render(params)
+	if err != nil {
+		return GetPromptResult{}, err
+	}
+	messages := make([]PromptMessage, 0, len(msgs))
+	for _, m := range msgs {
+		messages = append(messages, PromptMessage{
+			Role:    Role(m.Role),
+			Content: TextContent{Type: "text", Text: m.Content},
+		})
+	}
+	return GetPromptResult{Description: prompt.Description, Messages: messages}, nil
 }
@@ -15,14 +15,42 @@
 package mcp
 
 import (
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
 // SERVER_NAME is the server name used in Implementation.
 const SERVER_NAME = "Toolbox"
 
+// Revisions of the MCP protocol served by toolbox.
+const (
+	PROTOCOL_VERSION_2025_06_18 = "2025-06-18"
+	PROTOCOL_VERSION_2025_03_26 = "2025-03-26"
+	PROTOCOL_VERSION_2024_11_05 = "2024-11-05"
+)
+
 // LATEST_PROTOCOL_VERSION is the most recent version of the MCP protocol.
-const LATEST_PROTOCOL_VERSION = "2024-11-05"
+const LATEST_PROTOCOL_VERSION = PROTOCOL_VERSION_2025_06_18
+
+// DEFAULT_PROTOCOL_VERSION is assumed for HTTP requests that are not part of
+// a session and do not carry the MCP-Protocol-Version header.
+const DEFAULT_PROTOCOL_VERSION = PROTOCOL_VERSION_2024_11_05
+
+// SUPPORTED_PROTOCOL_VERSIONS lists every version of the MCP protocol that
+// toolbox is able to serve, from the most recent to the oldest.
+var SUPPORTED_PROTOCOL_VERSIONS = []string{
+	PROTOCOL_VERSION_2025_06_18,
+	PROTOCOL_VERSION_2025_03_26,
+	PROTOCOL_VERSION_2024_11_05,
+}
+
+// PROTOCOL_VERSION_HEADER is the HTTP header used by clients to send the
+// negotiated protocol version on requests following initialization.
+const PROTOCOL_VERSION_HEADER = "MCP-Protocol-Version"
+
+// SESSION_ID_HEADER is the HTTP header used by the Streamable HTTP transport
+// to identify a session.
+const SESSION_ID_HEADER = "Mcp-Session-Id"
 
 // JSONRPC_VERSION is the version of JSON-RPC used by MCP.
 const JSONRPC_VERSION = "2.0"
@@ -36,6 +64,19 @@ const (
 	INTERNAL_ERROR   = -32603
 )
 
+// Notifications sent when a list served by toolbox changes, such as when its
+// configuration is reloaded.
+const (
+	TOOLS_LIST_CHANGED     = "notifications/tools/list_changed"
+	RESOURCES_LIST_CHANGED = "notifications/resources/list_changed"
+	PROMPTS_LIST_CHANGED   = "notifications/prompts/list_changed"
+)
+
+// MCP specific error codes
+const (
+	RESOURCE_NOT_FOUND = -32002
+)
+
 // JSONRPCMessage represents either a JSONRPCRequest, JSONRPCNotification, JSONRPCResponse, or JSONRPCError.
 type JSONRPCMessage interface{}
 
@@ -117,6 +158,42 @@ type JSONRPCError struct {
 	Error   McpError  `json:"error"`
 }
 
+/* Notifications */
+
+// CancelledNotification can be sent by either side to indicate that it is
+// cancelling a previously-issued request.
+type CancelledNotification struct {
+	Jsonrpc string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  struct {
+		// The ID of the request to cancel.
+		RequestId RequestId `json:"requestId"`
+		// An optional string describing the reason for the cancellation.
+		Reason string `json:"reason,omitempty"`
+	} `json:"params"`
+}
+
+// ProgressNotificationParams are the params of a notifications/progress message.
+type ProgressNotificationParams struct {
+	// The progress token which was given in the initial request.
+	ProgressToken ProgressToken `json:"progressToken"`
+	// The progress thus far. This should increase every time progress is made,
+	// even if the total is unknown.
+	Progress float64 `json:"progress"`
+	// Total number of items to process, if known.
+	Total float64 `json:"total,omitempty"`
+	// An optional message describing the current progress.
+	Message string `json:"message,omitempty"`
+}
+
+// ProgressNotification is sent to inform the receiver of a progress update
+// for a long-running request.
+type ProgressNotification struct {
+	Jsonrpc string                     `json:"jsonrpc"`
+	Method  string                     `json:"method"`
+	Params  ProgressNotificationParams `json:"params"`
+}
+
 /* Empty result */
 
 // EmptyResult represents a response that indicates success but carries no data.
@@ -185,7 +262,9 @@ type ClientCapabilities struct {
 // capabilities are defined here, in this schema, but this is not a closed set: any
 // server can define its own, additional capabilities.
 type ServerCapabilities struct {
-	Tools *ListChanged `json:"tools,omitempty"`
+	Prompts   *ListChanged `json:"prompts,omitempty"`
+	Resources *ListChanged `json:"resources,omitempty"`
+	Tools     *ListChanged `json:"tools,omitempty"`
 }
 
 // Implementation describes the name and version of an MCP implementation.
@@ -234,6 +313,11 @@ type CallToolRequest struct {
 	Params struct {
 		Name      string         `json:"name"`
 		Arguments map[string]any `json:"arguments,omitempty"`
+		Meta      struct {
+			// If specified, the caller is requesting out-of-band progress
+			// notifications for this request.
+			ProgressToken ProgressToken `json:"progressToken,omitempty"`
+		} `json:"_meta,omitempty"`
 	} `json:"params,omitempty"`
 }
 
@@ -289,7 +373,124 @@ type CallToolResult struct {
 	// Could be either a TextContent, ImageContent, or EmbeddedResources
 	// For Toolbox, we will only be sending TextContent
 	Content []TextContent `json:"content"`
+	// An optional JSON object that represents the structured result of the
+	// tool call, matching the outputSchema of the tool.
+	StructuredContent map[string]any `json:"structuredContent,omitempty"`
 	// Whether the tool call ended in an error.
 	// If not set, this is assumed to be false (the call was successful).
 	IsError bool `json:"isError,omitempty"`
 }
+
+/* Resources */
+
+// A known resource that the server is capable of reading.
+type Resource struct {
+	Annotated
+	// The URI of this resource.
+	URI string `json:"uri"`
+	// A human-readable name for this resource.
+	Name string `json:"name"`
+	// A description of what this resource represents.
+	Description string `json:"description,omitempty"`
+	// The MIME type of this resource, if known.
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// A template description for resources available on the server.
+type ResourceTemplate struct {
+	Annotated
+	// A URI template (according to RFC 6570) that can be used to construct
+	// resource URIs.
+	URITemplate string `json:"uriTemplate"`
+	// A human-readable name for the type of resource this template refers to.
+	Name string `json:"name"`
+	// A description of what this template is for.
+	Description string `json:"description,omitempty"`
+	// The MIME type for all resources that match this template.
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// Sent from the client to request a list of resources the server has.
+type ListResourcesRequest struct {
+	PaginatedRequest
+}
+
+// The server's response to a resources/list request from the client.
+type ListResourcesResult struct {
+	PaginatedResult
+	Resources []Resource `json:"resources"`
+}
+
+// Sent from the client to request a list of resource templates the server has.
+type ListResourceTemplatesRequest struct {
+	PaginatedRequest
+}
+
+// The server's response to a resources/templates/list request from the client.
+type ListResourceTemplatesResult struct {
+	PaginatedResult
+	ResourceTemplates []ResourceTemplate `json:"resourceTemplates"`
+}
+
+// Sent from the client to the server, to read a specific resource URI.
+type ReadResourceRequest struct {
+	Request
+	Params struct {
+		// The URI of the resource to read.
+		URI string `json:"uri"`
+	} `json:"params,omitempty"`
+}
+
+// The contents of a specific resource, represented as text.
+type TextResourceContents struct {
+	// The URI of this resource.
+	URI string `json:"uri"`
+	// The MIME type of this resource, if known.
+	MimeType string `json:"mimeType,omitempty"`
+	// The text of the item.
+	Text string `json:"text"`
+}
+
+// The server's response to a resources/read request from the client.
+type ReadResourceResult struct {
+	Result
+	Contents []TextResourceContents `json:"contents"`
+}
+
+/* Prompts */
+
+// Sent from the client to request a list of prompts and prompt templates the server has.
+type ListPromptsRequest struct {
+	PaginatedRequest
+}
+
+// The server's response to a prompts/list request from the client.
+type ListPromptsResult struct {
+	PaginatedResult
+	Prompts []prompts.McpManifest `json:"prompts"`
+}
+
+// Used by the client to get a prompt provided by the server.
+type GetPromptRequest struct {
+	Request
+	Params struct {
+		// The name of the prompt or prompt template.
+		Name string `json:"name"`
+		// Arguments to use for templating the prompt.
+		Arguments map[string]string `json:"arguments,omitempty"`
+	} `json:"params,omitempty"`
+}
+
+// Describes a message returned as part of a prompt.
+type PromptMessage struct {
+	Role    Role        `json:"role"`
+	Content TextContent `json:"content"`
+}
+
+// The server's response to a prompts/get request from the client.
+type GetPromptResult struct {
+	Result
+	// An optional description for the prompt.
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
@@ -15,20 +15,31 @@
 package server
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/alicebob/miniredis/v2"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp"
+	"github.com/googleapis/genai-toolbox/internal/sessions"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/redis/go-redis/v9"
 )
 
 const jsonrpcVersion = "2.0"
-const protocolVersion = "2024-11-05"
+const protocolVersion = "2025-06-18"
 const serverName = "Toolbox"
 
 var tool1InputSchema = map[string]any{
@@ -89,12 +100,87 @@ func TestMcpEndpoint(t *testing.T) {
 				"result": map[string]any{
 					"protocolVersion": protocolVersion,
 					"capabilities": map[string]any{
-						"tools": map[string]any{"listChanged": false},
+						"prompts":   map[string]any{"listChanged": true},
+						"resources": map[string]any{"listChanged": true},
+						"tools":     map[string]any{"listChanged": true},
 					},
 					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
 				},
 			},
 		},
+		{
+			name: "initialize with older protocol version",
+			url:  "/",
+			body: mcp.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "mcp-initialize-old",
+				Request: mcp.Request{
+					Method: "initialize",
+				},
+				Params: map[string]any{"protocolVersion": "2024-11-05"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "mcp-initialize-old",
+				"result": map[string]any{
+					"protocolVersion": "2024-11-05",
+					"capabilities": map[string]any{
+						"prompts":   map[string]any{"listChanged": true},
+						"resources": map[string]any{"listChanged": true},
+						"tools":     map[string]any{"listChanged": true},
+					},
+					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+				},
+			},
+		},
+		{
+			name: "initialize with newer protocol version",
+			url:  "/",
+			body: mcp.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "mcp-initialize-new",
+				Request: mcp.Request{
+					Method: "initialize",
+				},
+				Params: map[string]any{"protocolVersion": "2099-01-01"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "mcp-initialize-new",
+				"error": map[string]any{
+					"code":    -32602.0,
+					"message": `unsupported protocol version: "2099-01-01", supported versions are ["2025-06-18" "2025-03-26" "2024-11-05"]`,
+					"data": map[string]any{
+						"requested": "2099-01-01",
+						"supported": []any{"2025-06-18", "2025-03-26", "2024-11-05"},
+					},
+				},
+			},
+		},
+		{
+			name: "initialize with unsupported protocol version",
+			url:  "/",
+			body: mcp.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "mcp-initialize-unsupported",
+				Request: mcp.Request{
+					Method: "initialize",
+				},
+				Params: map[string]any{"protocolVersion": "2024-01-01"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "mcp-initialize-unsupported",
+				"error": map[string]any{
+					"code":    -32602.0,
+					"message": `unsupported protocol version: "2024-01-01", supported versions are ["2025-06-18" "2025-03-26" "2024-11-05"]`,
+					"data": map[string]any{
+						"requested": "2024-01-01",
+						"supported": []any{"2025-06-18", "2025-03-26", "2024-11-05"},
+					},
+				},
+			},
+		},
 		{
 			name: "basic notification",
 			url:  "/",
@@ -180,6 +266,48 @@ func TestMcpEndpoint(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "tools/call on tool1_only",
+			url:  "/tool1_only",
+			body: mcp.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "tools-call-tool1",
+				Request: mcp.Request{
+					Method: "tools/call",
+				},
+				Params: map[string]any{"name": "no_params"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "tools-call-tool1",
+				"result": map[string]any{
+					"content": []any{
+						map[string]any{"type": "text", "text": `"no_params"`},
+					},
+				},
+			},
+		},
+		{
+			name:  "tools/call outside of toolset",
+			url:   "/tool1_only",
+			isErr: true,
+			body: mcp.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      "tools-call-outside-toolset",
+				Request: mcp.Request{
+					Method: "tools/call",
+				},
+				Params: map[string]any{"name": "some_params", "arguments": map[string]any{"param1": 1, "param2": 2}},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "tools-call-outside-toolset",
+				"error": map[string]any{
+					"code":    -32602.0,
+					"message": `invalid tool name: tool with name "some_params" does not exist`,
+				},
+			},
+		},
 		{
 			name:  "missing method",
 			url:   "/",
@@ -269,6 +397,402 @@ func TestMcpEndpoint(t *testing.T) {
 	}
 }
 
+func TestMcpBatch(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+		want       any
+	}{
+		{
+			name: "mixed batch",
+			body: `[
+				{"jsonrpc":"2.0","id":"list","method":"tools/list"},
+				{"jsonrpc":"2.0","method":"notifications/initialized"},
+				{"jsonrpc":"2.0","id":"call","method":"tools/call","params":{"name":"no_params"}},
+				{"jsonrpc":"2.0","id":"foo","method":"foo"}
+			]`,
+			statusCode: http.StatusOK,
+			want: []any{
+				map[string]any{"id": "list"},
+				map[string]any{"id": "call"},
+				map[string]any{"id": "foo"},
+			},
+		},
+		{
+			name: "batch with non-object elements",
+			body: `[
+				{"jsonrpc":"2.0","id":"list","method":"tools/list"},
+				1,
+				{"jsonrpc":"2.0","method":"notifications/initialized"},
+				"foo"
+			]`,
+			statusCode: http.StatusOK,
+			want: []any{
+				map[string]any{"id": "list"},
+				map[string]any{"id": nil, "code": float64(mcp.INVALID_REQUEST)},
+				map[string]any{"id": nil, "code": float64(mcp.INVALID_REQUEST)},
+			},
+		},
+		{
+			name: "batch larger than the concurrency limit",
+			body: func() string {
+				msgs := make([]string, 2*maxBatchConcurrency+1)
+				for i := range msgs {
+					msgs[i] = fmt.Sprintf(`{"jsonrpc":"2.0","id":"list-%d","method":"tools/list"}`, i)
+				}
+				return "[" + strings.Join(msgs, ",") + "]"
+			}(),
+			statusCode: http.StatusOK,
+			want: func() []any {
+				want := make([]any, 2*maxBatchConcurrency+1)
+				for i := range want {
+					want[i] = map[string]any{"id": fmt.Sprintf("list-%d", i)}
+				}
+				return want
+			}(),
+		},
+		{
+			name: "batch with duplicate ids",
+			body: `[
+				{"jsonrpc":"2.0","id":1,"method":"tools/list"},
+				{"jsonrpc":"2.0","id":"1","method":"tools/list"},
+				{"jsonrpc":"2.0","id": 1,"method":"tools/call","params":{"name":"no_params"}},
+				{"jsonrpc":"2.0","method":"notifications/initialized"},
+				{"jsonrpc":"2.0","method":"notifications/initialized"}
+			]`,
+			statusCode: http.StatusOK,
+			want: []any{
+				map[string]any{"id": float64(1)},
+				map[string]any{"id": "1"},
+				map[string]any{"id": float64(1), "code": float64(mcp.INVALID_REQUEST)},
+			},
+		},
+		{
+			name:       "notifications only",
+			body:       `[{"jsonrpc":"2.0","method":"notifications/initialized"}]`,
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:       "empty batch",
+			body:       `[]`,
+			statusCode: http.StatusOK,
+			want:       map[string]any{"id": nil},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.statusCode {
+				t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, tc.statusCode)
+			}
+			if tc.want == nil {
+				return
+			}
+
+			var got any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			// only compare ids, the content of each response is covered by other tests
+			switch want := tc.want.(type) {
+			case []any:
+				gotList, ok := got.([]any)
+				if !ok || len(gotList) != len(want) {
+					t.Fatalf("unexpected response: got %+v, want %d responses", got, len(want))
+				}
+				for i, w := range want {
+					gotMap, wantMap := gotList[i].(map[string]any), w.(map[string]any)
+					if id, ok := gotMap["id"]; !ok || id != wantMap["id"] {
+						t.Fatalf("unexpected response #%d: got %+v, want id %v", i, gotList[i], w)
+					}
+					if code, ok := wantMap["code"]; ok {
+						if gotError, _ := gotMap["error"].(map[string]any); gotError == nil || gotError["code"] != code {
+							t.Fatalf("unexpected response #%d: got %+v, want error code %v", i, gotList[i], code)
+						}
+					}
+				}
+			case map[string]any:
+				gotMap, ok := got.(map[string]any)
+				if !ok || gotMap["id"] != want["id"] || gotMap["error"] == nil {
+					t.Fatalf("unexpected response: got %+v, want an error with id %v", got, want["id"])
+				}
+			}
+		})
+	}
+}
+
+func TestMcpResources(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	s, shutdown := setUpTestServer(t, toolsMap, toolsets)
+	defer shutdown()
+	s.resourceMgr.current.sources = map[string]sources.Source{
+		"my-schema-source": MockSchemaSource{SchemaValue: map[string]any{"tables": []any{"users"}}},
+		"my-source":        MockSource{},
+		"my-unused-source": MockSchemaSource{SchemaValue: map[string]any{"tables": []any{"secrets"}}},
+	}
+	s.resourceMgr.current.toolConfigs = ToolConfigs{
+		tool1.Name: MockToolConfig{Source: "my-schema-source"},
+		tool2.Name: MockToolConfig{Source: "my-source"},
+	}
+	r, err := mcpRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name   string
+		path   string
+		method string
+		params map[string]any
+		want   map[string]any
+	}{
+		{
+			name:   "resources/list",
+			path:   "/",
+			method: "resources/list",
+			want: map[string]any{
+				"resources": []any{
+					map[string]any{
+						"uri":         "toolbox://sources/my-schema-source/schema",
+						"name":        "my-schema-source schema",
+						"description": `Schema of the "my-schema-source" mock source.`,
+						"mimeType":    "application/json",
+					},
+				},
+			},
+		},
+		{
+			name:   "resources/list of a toolset without schema sources",
+			path:   "/tool2_only",
+			method: "resources/list",
+			want:   map[string]any{"resources": []any{}},
+		},
+		{
+			name:   "resources/templates/list",
+			path:   "/",
+			method: "resources/templates/list",
+			want: map[string]any{
+				"resourceTemplates": []any{
+					map[string]any{
+						"uriTemplate": "toolbox://sources/{sourceName}/schema",
+						"name":        "source schema",
+						"description": "Schema of a source, such as its tables and columns.",
+						"mimeType":    "application/json",
+					},
+				},
+			},
+		},
+		{
+			name:   "resources/read",
+			path:   "/",
+			method: "resources/read",
+			params: map[string]any{"uri": "toolbox://sources/my-schema-source/schema"},
+			want: map[string]any{
+				"contents": []any{
+					map[string]any{
+						"uri":      "toolbox://sources/my-schema-source/schema",
+						"mimeType": "application/json",
+						"text":     `{"tables":["users"]}`,
+					},
+				},
+			},
+		},
+		{
+			name:   "resources/read without schema",
+			path:   "/",
+			method: "resources/read",
+			params: map[string]any{"uri": "toolbox://sources/my-source/schema"},
+			want: map[string]any{
+				"code":    -32002.0,
+				"message": "resource not found: toolbox://sources/my-source/schema",
+				"data":    map[string]any{"uri": "toolbox://sources/my-source/schema"},
+			},
+		},
+		{
+			name:   "resources/read of a source outside the toolset",
+			path:   "/tool2_only",
+			method: "resources/read",
+			params: map[string]any{"uri": "toolbox://sources/my-schema-source/schema"},
+			want: map[string]any{
+				"code":    -32002.0,
+				"message": "resource not found: toolbox://sources/my-schema-source/schema",
+				"data":    map[string]any{"uri": "toolbox://sources/my-schema-source/schema"},
+			},
+		},
+		{
+			name:   "resources/read of a source without tools",
+			path:   "/",
+			method: "resources/read",
+			params: map[string]any{"uri": "toolbox://sources/my-unused-source/schema"},
+			want: map[string]any{
+				"code":    -32002.0,
+				"message": "resource not found: toolbox://sources/my-unused-source/schema",
+				"data":    map[string]any{"uri": "toolbox://sources/my-unused-source/schema"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqMarshal, err := json.Marshal(mcp.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      tc.name,
+				Request: mcp.Request{Method: tc.method},
+				Params:  tc.params,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			_, body, err := runRequest(ts, http.MethodPost, tc.path, bytes.NewBuffer(reqMarshal))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			gotResult, ok := got["result"]
+			if !ok {
+				gotResult = got["error"]
+			}
+			if !reflect.DeepEqual(gotResult, tc.want) {
+				t.Fatalf("unexpected response: got %+v, want %+v", gotResult, tc.want)
+			}
+		})
+	}
+}
+
+func TestMcpPrompts(t *testing.T) {
+	s, shutdown := setUpTestServer(t, nil, nil)
+	defer shutdown()
+	prompt, err := prompts.PromptConfig{
+		Name:        "summarize_table",
+		Description: "Summarize the contents of a table.",
+		Arguments: tools.Parameters{
+			tools.NewStringParameter("table", "name of the table"),
+			tools.NewIntParameter("limit", "number of rows to look at"),
+		},
+		Messages: []prompts.MessageConfig{
+			{Content: "Summarize the first {{.limit}} rows of the {{.table}} table."},
+		},
+	}.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize prompt: %s", err)
+	}
+	s.resourceMgr.current.prompts = map[string]prompts.Prompt{"summarize_table": prompt}
+	r, err := mcpRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name   string
+		method string
+		params map[string]any
+		want   map[string]any
+	}{
+		{
+			name:   "prompts/list",
+			method: "prompts/list",
+			want: map[string]any{
+				"prompts": []any{
+					map[string]any{
+						"name":        "summarize_table",
+						"description": "Summarize the contents of a table.",
+						"arguments": []any{
+							map[string]any{"name": "table", "description": "name of the table", "required": true},
+							map[string]any{"name": "limit", "description": "number of rows to look at", "required": true},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "prompts/get",
+			method: "prompts/get",
+			params: map[string]any{
+				"name":      "summarize_table",
+				"arguments": map[string]any{"table": "users", "limit": "10"},
+			},
+			want: map[string]any{
+				"description": "Summarize the contents of a table.",
+				"messages": []any{
+					map[string]any{
+						"role": "user",
+						"content": map[string]any{
+							"type": "text",
+							"text": "Summarize the first 10 rows of the users table.",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "prompts/get with invalid argument",
+			method: "prompts/get",
+			params: map[string]any{
+				"name":      "summarize_table",
+				"arguments": map[string]any{"table": "users", "limit": "ten"},
+			},
+			want: map[string]any{
+				"code":    -32602.0,
+				"message": `provided arguments failed validation: unable to parse value for "limit": invalid character 'e' in literal true (expecting 'r')`,
+			},
+		},
+		{
+			name:   "prompts/get with invalid prompt",
+			method: "prompts/get",
+			params: map[string]any{"name": "some_imaginary_prompt"},
+			want: map[string]any{
+				"code":    -32602.0,
+				"message": `invalid prompt name: prompt with name "some_imaginary_prompt" does not exist`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqMarshal, err := json.Marshal(mcp.JSONRPCRequest{
+				Jsonrpc: jsonrpcVersion,
+				Id:      tc.name,
+				Request: mcp.Request{Method: tc.method},
+				Params:  tc.params,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal))
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			gotResult, ok := got["result"]
+			if !ok {
+				gotResult = got["error"]
+			}
+			if !reflect.DeepEqual(gotResult, tc.want) {
+				t.Fatalf("unexpected response: got %+v, want %+v", gotResult, tc.want)
+			}
+		})
+	}
+}
+
 func TestSseEndpoint(t *testing.T) {
 	r, shutdown := setUpServer(t, "mcp", nil, nil)
 	defer shutdown()
@@ -365,6 +889,685 @@ func TestSseEndpoint(t *testing.T) {
 	}
 }
 
+func TestStreamableHttpSession(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	send := func(method, sessionId, accept string, body any) (*http.Response, []byte) {
+		var reader io.Reader
+		if body != nil {
+			b, err := json.Marshal(body)
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body: %s", err)
+			}
+			reader = bytes.NewBuffer(b)
+		}
+		req, err := http.NewRequest(method, ts.URL+"/", reader)
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if sessionId != "" {
+			req.Header.Set(mcp.SESSION_ID_HEADER, sessionId)
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		defer resp.Body.Close()
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response body: %s", err)
+		}
+		return resp, respBody
+	}
+
+	initialize := mcp.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "mcp-initialize",
+		Request: mcp.Request{Method: "initialize"},
+	}
+	toolsList := mcp.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "tools-list",
+		Request: mcp.Request{Method: "tools/list"},
+	}
+
+	resp, _ := send(http.MethodPost, "", "application/json, text/event-stream", initialize)
+	sessionId := resp.Header.Get(mcp.SESSION_ID_HEADER)
+	if sessionId == "" {
+		t.Fatalf("expected %s header on initialize response", mcp.SESSION_ID_HEADER)
+	}
+
+	resp, body := send(http.MethodPost, sessionId, "application/json, text/event-stream", toolsList)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "application/json" {
+		t.Fatalf("unexpected content-type header: want %s, got %s", "application/json", contentType)
+	}
+	if !strings.Contains(string(body), `"tools-list"`) {
+		t.Fatalf("unexpected response: %s", body)
+	}
+
+	resp, body = send(http.MethodPost, sessionId, "text/event-stream", toolsList)
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("unexpected content-type header: want %s, got %s", "text/event-stream", contentType)
+	}
+	if !strings.HasPrefix(string(body), "event: message\ndata: ") {
+		t.Fatalf("unexpected event: %s", body)
+	}
+
+	resp, _ = send(http.MethodGet, "", "text/event-stream", nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected status code for GET without session: got %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+
+	// the session cannot be used through another toolset
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		req, err := http.NewRequest(method, ts.URL+"/tool1_only", nil)
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("Accept", "text/event-stream")
+		req.Header.Set(mcp.SESSION_ID_HEADER, sessionId)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("unexpected status code for %s through another toolset: got %d, want %d", method, resp.StatusCode, http.StatusBadRequest)
+		}
+	}
+
+	resp, _ = send(http.MethodDelete, sessionId, "", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code for DELETE: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	resp, _ = send(http.MethodPost, sessionId, "application/json, text/event-stream", toolsList)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected status code for terminated session: got %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+// failingSessionStore is a session store that is unable to create sessions.
+type failingSessionStore struct {
+	sessions.Store
+}
+
+func (failingSessionStore) Create(context.Context, sessions.Session) error {
+	return fmt.Errorf("store unavailable")
+}
+
+func TestStreamableHttpSessionCreateFailure(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	s, shutdown := setUpTestServer(t, toolsMap, toolsets)
+	defer shutdown()
+	s.sessionStore = failingSessionStore{s.sessionStore}
+	r, err := mcpRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, "/", strings.NewReader(`{"jsonrpc":"2.0","id":"mcp-initialize","method":"initialize"}`))
+	if err != nil {
+		t.Fatalf("unable to run request: %s", err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusInternalServerError)
+	}
+	if sessionId := resp.Header.Get(mcp.SESSION_ID_HEADER); sessionId != "" {
+		t.Fatalf("unexpected %s header: %s", mcp.SESSION_ID_HEADER, sessionId)
+	}
+	var got mcp.JSONRPCError
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unable to unmarshal response: %s", err)
+	}
+	if got.Id != "mcp-initialize" || got.Error.Code != mcp.INTERNAL_ERROR || !strings.Contains(got.Error.Message, "store unavailable") {
+		t.Fatalf("unexpected response: %s", body)
+	}
+}
+
+func TestStreamableHttpResume(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	s, shutdown := setUpTestServer(t, toolsMap, toolsets)
+	defer shutdown()
+	s.sseKeepAliveInterval = 10 * time.Millisecond
+	r, err := mcpRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, _, err := runRequest(ts, http.MethodPost, "/", strings.NewReader(`{"jsonrpc":"2.0","id":"mcp-initialize","method":"initialize"}`))
+	if err != nil {
+		t.Fatalf("unable to initialize session: %s", err)
+	}
+	sessionId := resp.Header.Get(mcp.SESSION_ID_HEADER)
+	// events sent while no stream is attached are kept for the client
+	for i := 1; i <= 3; i++ {
+		if err := s.sessionStore.Publish(context.Background(), sessionId, []byte(fmt.Sprintf(`{"event":%d}`, i))); err != nil {
+			t.Fatalf("unable to send event: %s", err)
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/", nil)
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(mcp.SESSION_ID_HEADER, sessionId)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to open stream: %s", err)
+	}
+	defer resp.Body.Close()
+
+	want := []string{
+		"id: 2", "event: message", `data: {"event":2}`, "",
+		"id: 3", "event: message", `data: {"event":3}`, "",
+		": keepalive", "",
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	var got []string
+	for len(got) < len(want) && scanner.Scan() {
+		got = append(got, scanner.Text())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected stream: got %q, want %q", got, want)
+	}
+
+	req.Header.Set("Last-Event-ID", "foo")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to open stream: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected status code for invalid Last-Event-ID: got %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestSseSharedSessionStore(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	mr := miniredis.RunT(t)
+	// runReplica runs a server storing its sessions in the shared redis
+	runReplica := func() *httptest.Server {
+		s, shutdown := setUpTestServer(t, toolsMap, toolsets)
+		t.Cleanup(shutdown)
+		s.sessionStore = sessions.NewRedisStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}), sessions.Config{})
+		t.Cleanup(func() { _ = s.sessionStore.Close() })
+		r, err := mcpRouter(s)
+		if err != nil {
+			t.Fatalf("unable to initialize mcp router: %s", err)
+		}
+		ts := runServer(r, false)
+		t.Cleanup(ts.Close)
+		return ts
+	}
+	replica1, replica2 := runReplica(), runReplica()
+
+	resp, err := runSseRequest(replica1, "/sse", "")
+	if err != nil {
+		t.Fatalf("unable to run sse request: %s", err)
+	}
+	defer resp.Body.Close()
+	scanner := bufio.NewScanner(resp.Body)
+	var sessionId string
+	for sessionId == "" && scanner.Scan() {
+		_, sessionId, _ = strings.Cut(scanner.Text(), "?sessionId=")
+	}
+	if sessionId == "" {
+		t.Fatalf("missing endpoint event")
+	}
+
+	// the response to a message sent to another replica is delivered on the stream
+	resp, _, err = runRequest(replica2, http.MethodPost, "/?sessionId="+sessionId, strings.NewReader(`{"jsonrpc":"2.0","id":"tools-list","method":"tools/list"}`))
+	if err != nil {
+		t.Fatalf("unable to send message: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var got []string
+	for len(got) < 3 && scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			got = append(got, line)
+		}
+	}
+	if len(got) != 3 || got[0] != "id: 1" || got[1] != "event: message" || !strings.Contains(got[2], `"id":"tools-list"`) {
+		t.Fatalf("unexpected event: %q", got)
+	}
+}
+
+func TestMcpProgressAndCancellation(t *testing.T) {
+	cancelled := make(chan struct{})
+	slowTool := MockTool{
+		Name:   "slow",
+		Params: []tools.Parameter{},
+		InvokeFunc: func(ctx context.Context, _ tools.ParamValues) ([]any, error) {
+			tools.ReportProgress(ctx, 1, 0, "started")
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, slowTool})
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	post := func(sessionId, accept, body string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", accept)
+		if sessionId != "" {
+			req.Header.Set(mcp.SESSION_ID_HEADER, sessionId)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		return resp
+	}
+
+	resp := post("", "application/json", `{"jsonrpc":"2.0","id":"mcp-initialize","method":"initialize"}`)
+	resp.Body.Close()
+	sessionId := resp.Header.Get(mcp.SESSION_ID_HEADER)
+	if sessionId == "" {
+		t.Fatalf("expected %s header on initialize response", mcp.SESSION_ID_HEADER)
+	}
+
+	// progress notifications are streamed in the response to the tool call,
+	// although the client also accepts JSON
+	resp = post(sessionId, "application/json, text/event-stream", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow","_meta":{"progressToken":2}}}`)
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	var event string
+	for !strings.HasPrefix(event, "data: ") {
+		var err error
+		if event, err = reader.ReadString('\n'); err != nil {
+			t.Fatalf("unable to read event: %s", err)
+		}
+	}
+	want := `{"jsonrpc":"2.0","method":"notifications/progress","params":{"progressToken":2,"progress":1,"message":"started"}}`
+	if got := strings.TrimSpace(strings.TrimPrefix(event, "data: ")); got != want {
+		t.Fatalf("unexpected progress notification: got %s, want %s", got, want)
+	}
+
+	resp = post(sessionId, "application/json", `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1}}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusAccepted)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("tool invocation was not cancelled")
+	}
+
+	// the cancelled request does not get a response
+	rest, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("unable to read response body: %s", err)
+	}
+	if strings.Contains(string(rest), `"id":1`) {
+		t.Fatalf("unexpected response to a cancelled request: %s", rest)
+	}
+}
+
+func TestPrefersEventStream(t *testing.T) {
+	call := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow"}}`
+	progressCall := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow","_meta":{"progressToken":"p"}}}`
+	tcs := []struct {
+		name   string
+		accept string
+		body   string
+		want   bool
+	}{
+		{"json only", "application/json", progressCall, false},
+		{"event stream only", "text/event-stream", call, true},
+		{"both without progress", "application/json, text/event-stream", call, false},
+		{"both with progress", "application/json, text/event-stream", progressCall, true},
+		{"both with progress in batch", "application/json, text/event-stream", "[" + call + "," + progressCall + "]", true},
+		{"both with progress on another method", "application/json, text/event-stream", `{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{"_meta":{"progressToken":"p"}}}`, false},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.Header.Set("Accept", tc.accept)
+			if got := prefersEventStream(r, []byte(tc.body)); got != tc.want {
+				t.Fatalf("unexpected result: got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMcpProtocolVersion(t *testing.T) {
+	readOnly := true
+	annotatedTool := MockTool{
+		Name:        "annotated",
+		Params:      []tools.Parameter{},
+		Annotations: &tools.ToolAnnotations{ReadOnlyHint: &readOnly},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, annotatedTool})
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	send := func(headers map[string]string, body string) (*http.Response, map[string]any) {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		defer resp.Body.Close()
+		var got map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("unable to decode response: %s", err)
+		}
+		return resp, got
+	}
+	// hasAnnotations reports whether the annotated tool is listed with its annotations
+	hasAnnotations := func(res map[string]any) bool {
+		result, _ := res["result"].(map[string]any)
+		listed, _ := result["tools"].([]any)
+		for _, l := range listed {
+			if m, _ := l.(map[string]any); m["name"] == annotatedTool.Name {
+				_, ok := m["annotations"]
+				return ok
+			}
+		}
+		t.Fatalf("tool %q is not listed: %+v", annotatedTool.Name, res)
+		return false
+	}
+	toolsList := `{"jsonrpc":"2.0","id":"tools-list","method":"tools/list"}`
+
+	t.Run("session uses negotiated version", func(t *testing.T) {
+		resp, _ := send(nil, `{"jsonrpc":"2.0","id":"mcp-initialize","method":"initialize","params":{"protocolVersion":"2024-11-05"}}`)
+		sessionId := resp.Header.Get(mcp.SESSION_ID_HEADER)
+		_, got := send(map[string]string{mcp.SESSION_ID_HEADER: sessionId}, toolsList)
+		if hasAnnotations(got) {
+			t.Fatalf("unexpected annotations for protocol version 2024-11-05: %+v", got)
+		}
+
+		resp, _ = send(nil, `{"jsonrpc":"2.0","id":"mcp-initialize","method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+		sessionId = resp.Header.Get(mcp.SESSION_ID_HEADER)
+		_, got = send(map[string]string{mcp.SESSION_ID_HEADER: sessionId}, toolsList)
+		if !hasAnnotations(got) {
+			t.Fatalf("missing annotations for protocol version 2025-06-18: %+v", got)
+		}
+	})
+	t.Run("header sets version of stateless requests", func(t *testing.T) {
+		_, got := send(map[string]string{mcp.PROTOCOL_VERSION_HEADER: "2024-11-05"}, toolsList)
+		if hasAnnotations(got) {
+			t.Fatalf("unexpected annotations for protocol version 2024-11-05: %+v", got)
+		}
+		// requests without the header use 2024-11-05
+		_, got = send(nil, toolsList)
+		if hasAnnotations(got) {
+			t.Fatalf("unexpected annotations for the default protocol version: %+v", got)
+		}
+	})
+	t.Run("unsupported header", func(t *testing.T) {
+		resp, _ := send(map[string]string{mcp.PROTOCOL_VERSION_HEADER: "2024-01-01"}, toolsList)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusBadRequest)
+		}
+	})
+}
+
+func TestMcpStructuredContent(t *testing.T) {
+	outputSchema, err := tools.NewRowsOutputSchema(map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"id": map[string]any{"type": "integer"}},
+	})
+	if err != nil {
+		t.Fatalf("unable to compile output schema: %s", err)
+	}
+	rowsTool := MockTool{
+		Name:         "rows",
+		Params:       []tools.Parameter{},
+		OutputSchema: outputSchema,
+		InvokeFunc: func(context.Context, tools.ParamValues) ([]any, error) {
+			return []any{map[string]any{"id": 1}, map[string]any{"id": 2}}, nil
+		},
+	}
+	mismatchedTool := MockTool{
+		Name:         "mismatched",
+		Params:       []tools.Parameter{},
+		OutputSchema: outputSchema,
+		InvokeFunc: func(context.Context, tools.ParamValues) ([]any, error) {
+			return []any{map[string]any{"id": 1}, map[string]any{"id": "two"}}, nil
+		},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, rowsTool, mismatchedTool})
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	send := func(version, body string) map[string]any {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(mcp.PROTOCOL_VERSION_HEADER, version)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		defer resp.Body.Close()
+		var got map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("unable to decode response: %s", err)
+		}
+		result, ok := got["result"].(map[string]any)
+		if !ok {
+			t.Fatalf("unexpected response: %+v", got)
+		}
+		return result
+	}
+	// listedOutputSchema returns the output schema of the rows tool
+	listedOutputSchema := func(result map[string]any) any {
+		listed, _ := result["tools"].([]any)
+		for _, l := range listed {
+			if m, _ := l.(map[string]any); m["name"] == rowsTool.Name {
+				return m["outputSchema"]
+			}
+		}
+		t.Fatalf("tool %q is not listed: %+v", rowsTool.Name, result)
+		return nil
+	}
+	toolsList := `{"jsonrpc":"2.0","id":"tools-list","method":"tools/list"}`
+	toolsCall := `{"jsonrpc":"2.0","id":"tools-call","method":"tools/call","params":{"name":"rows"}}`
+
+	t.Run("latest protocol version", func(t *testing.T) {
+		wantSchema := map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"result": map[string]any{
+					"type": "array",
+					"items": map[string]any{
+						"type":       "object",
+						"properties": map[string]any{"id": map[string]any{"type": "integer"}},
+					},
+				},
+			},
+			"required": []any{"result"},
+		}
+		if got := listedOutputSchema(send(protocolVersion, toolsList)); !reflect.DeepEqual(got, wantSchema) {
+			t.Fatalf("unexpected output schema: got %+v, want %+v", got, wantSchema)
+		}
+
+		want := map[string]any{
+			"content": []any{
+				map[string]any{"type": "text", "text": `{"id":1}`},
+				map[string]any{"type": "text", "text": `{"id":2}`},
+			},
+			"structuredContent": map[string]any{
+				"result": []any{map[string]any{"id": 1.0}, map[string]any{"id": 2.0}},
+			},
+		}
+		if got := send(protocolVersion, toolsCall); !reflect.DeepEqual(got, want) {
+			t.Fatalf("unexpected tool result: got %+v, want %+v", got, want)
+		}
+	})
+	t.Run("result not matching the output schema", func(t *testing.T) {
+		call := `{"jsonrpc":"2.0","id":"tools-call","method":"tools/call","params":{"name":"mismatched"}}`
+		want := map[string]any{
+			"content": []any{
+				map[string]any{"type": "text", "text": "result 1 does not match the output schema: /id: expected integer, got string"},
+			},
+			"isError": true,
+		}
+		if got := send(protocolVersion, call); !reflect.DeepEqual(got, want) {
+			t.Fatalf("unexpected tool result: got %+v, want %+v", got, want)
+		}
+	})
+	t.Run("older protocol version", func(t *testing.T) {
+		if got := listedOutputSchema(send(mcp.PROTOCOL_VERSION_2025_03_26, toolsList)); got != nil {
+			t.Fatalf("unexpected output schema for protocol version 2025-03-26: %+v", got)
+		}
+		got := send(mcp.PROTOCOL_VERSION_2025_03_26, toolsCall)
+		if _, ok := got["structuredContent"]; ok {
+			t.Fatalf("unexpected structured content for protocol version 2025-03-26: %+v", got)
+		}
+	})
+}
+
+func TestMcpAuth(t *testing.T) {
+	authTool := MockTool{
+		Name:         "auth_required",
+		AuthRequired: []string{"my-auth"},
+		Params: tools.Parameters{
+			tools.NewStringParameterWithAuth("user", "The user id.", []tools.ParamAuthService{{Name: "my-auth", Field: "sub"}}),
+		},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2, authTool})
+	s, shutdown := setUpTestServer(t, toolsMap, toolsets)
+	defer shutdown()
+	s.resourceMgr.current.authServices = map[string]auth.AuthService{"my-auth": MockAuthService{Name: "my-auth"}}
+	r, err := mcpRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	callBody, err := json.Marshal(mcp.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "auth-call",
+		Request: mcp.Request{Method: "tools/call"},
+		Params:  map[string]any{"name": "auth_required", "arguments": map[string]any{}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body: %s", err)
+	}
+	call := func(url string, header map[string]string) map[string]any {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(callBody))
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+		defer resp.Body.Close()
+		var got map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		return got
+	}
+
+	t.Run("missing auth header", func(t *testing.T) {
+		got := call(ts.URL+"/", nil)
+		if _, ok := got["error"]; !ok {
+			t.Fatalf("expected an error response, got %+v", got)
+		}
+	})
+
+	t.Run("auth header on request", func(t *testing.T) {
+		got := call(ts.URL+"/", map[string]string{"my-auth_token": "alice"})
+		if _, ok := got["result"]; !ok {
+			t.Fatalf("expected a result response, got %+v", got)
+		}
+	})
+
+	// sseEndpoint opens an SSE session authenticated as alice and returns the
+	// endpoint for its messages.
+	sseEndpoint := func(t *testing.T) (string, func()) {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/sse", nil)
+		if err != nil {
+			t.Fatalf("unable to create request: %s", err)
+		}
+		req.Header.Set("my-auth_token", "alice")
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %s", err)
+		}
+
+		buffer := make([]byte, 1024)
+		n, err := resp.Body.Read(buffer)
+		if err != nil {
+			t.Fatalf("unable to read response: %s", err)
+		}
+		endpoint := strings.TrimSpace(strings.TrimPrefix(string(buffer[:n]), "event: endpoint\ndata: "))
+		// the test router is not mounted under /mcp
+		return strings.Replace(endpoint, "/mcp?", "/?", 1), func() { resp.Body.Close() }
+	}
+
+	t.Run("auth header on sse session", func(t *testing.T) {
+		endpoint, closeSession := sseEndpoint(t)
+		defer closeSession()
+		got := call(endpoint, nil)
+		if _, ok := got["result"]; !ok {
+			t.Fatalf("expected a result response, got %+v", got)
+		}
+	})
+
+	t.Run("expired auth header on sse session", func(t *testing.T) {
+		s.resourceMgr.current.authServices = map[string]auth.AuthService{"my-auth": MockAuthService{Name: "my-auth", TokenLifetime: -time.Minute}}
+		endpoint, closeSession := sseEndpoint(t)
+		defer closeSession()
+		got := call(endpoint, nil)
+		if _, ok := got["error"]; !ok {
+			t.Fatalf("expected an error response, got %+v", got)
+		}
+	})
+}
+
 func runSseRequest(ts *httptest.Server, path string, proto string) (*http.Response, error) {
 	req, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
 	if err != nil {
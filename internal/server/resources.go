@@ -0,0 +1,457 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/server/mcp"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// resourceSet holds the resources initialized from a single configuration.
+// It is never modified once it is in use, so that requests see a consistent
+// configuration while it is reloaded.
+type resourceSet struct {
+	sources      map[string]sources.Source
+	authServices map[string]auth.AuthService
+	tools        map[string]tools.Tool
+	toolsets     map[string]tools.Toolset
+	prompts      map[string]prompts.Prompt
+
+	// configs the resources were initialized from
+	sourceConfigs      SourceConfigs
+	authServiceConfigs AuthServiceConfigs
+	toolConfigs        ToolConfigs
+	toolsetConfigs     ToolsetConfigs
+	promptConfigs      PromptConfigs
+
+	// shared holds the sources by name, counting the sets of resources using
+	// them.
+	shared map[string]*sharedSource
+
+	// inFlight counts the requests using the resources.
+	inFlight sync.WaitGroup
+	// retired is set, under the lock of the resourceManager, once the
+	// resources no longer take new requests. inFlight can only be waited on
+	// after that.
+	retired bool
+}
+
+// sharedSource is a source used by one or more sets of resources, since a
+// source whose configuration does not change is reused on reload. It is
+// closed once no set of resources uses it.
+type sharedSource struct {
+	source sources.Source
+	users  atomic.Int32
+}
+
+// releaseSources stops using the sources of r, closing the ones no other set
+// of resources uses. It must only be called once the requests using r are
+// done.
+func (r *resourceSet) releaseSources(ctx context.Context, l log.Logger) {
+	for _, ss := range r.shared {
+		if ss.users.Add(-1) == 0 {
+			closeSource(ctx, l, ss.source)
+		}
+	}
+}
+
+// toolsetSources returns the sources used by the tools of the toolset named
+// toolsetName, whose schemas are exposed to the clients of the toolset. The
+// sources of tools not implementing tools.ReferencingConfig are not known,
+// so they are not returned.
+func (r *resourceSet) toolsetSources(toolsetName string) map[string]sources.Source {
+	srcs := make(map[string]sources.Source)
+	toolset, ok := r.toolsets[toolsetName]
+	if !ok {
+		return srcs
+	}
+	for name := range toolset.Manifest.ToolsManifest {
+		rc, ok := r.toolConfigs[name].(tools.ReferencingConfig)
+		if !ok {
+			continue
+		}
+		source := rc.References().Source
+		if src, ok := r.sources[source]; ok {
+			srcs[source] = src
+		}
+	}
+	return srcs
+}
+
+// resourceManager holds the resources currently served.
+type resourceManager struct {
+	mu      sync.RWMutex
+	current *resourceSet
+}
+
+func newResourceManager(r *resourceSet) *resourceManager {
+	return &resourceManager{current: r}
+}
+
+// acquire returns the resources currently served. release must be called once
+// the caller is done with them.
+func (m *resourceManager) acquire() (r *resourceSet, release func()) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r = m.current
+	if r.retired {
+		// requests made during shutdown are not waited for
+		return r, func() {}
+	}
+	r.inFlight.Add(1)
+	return r, r.inFlight.Done
+}
+
+// swap replaces the resources served, returning the previous ones. The
+// previous resources are retired.
+func (m *resourceManager) swap(r *resourceSet) *resourceSet {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	old := m.current
+	old.retired = true
+	m.current = r
+	return old
+}
+
+// retire stops counting the requests using the resources currently served,
+// and returns them.
+func (m *resourceManager) retire() *resourceSet {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.current.retired = true
+	return m.current
+}
+
+// initializeResources initializes the resources of cfg. Sources and auth
+// services whose configuration is the same in previous are reused rather than
+// initialized again. previous must not have released its sources.
+func initializeResources(ctx context.Context, cfg ServerConfig, instrumentation *Instrumentation, l log.Logger, previous *resourceSet) (*resourceSet, error) {
+	var created []sources.Source
+	shared := make(map[string]*sharedSource)
+	r, err := func() (*resourceSet, error) {
+		// initialize and validate the sources from configs
+		sourcesMap := make(map[string]sources.Source)
+		for name, sc := range cfg.SourceConfigs {
+			if previous != nil {
+				if psc, ok := previous.sourceConfigs[name]; ok && reflect.DeepEqual(psc, sc) {
+					sourcesMap[name] = previous.sources[name]
+					shared[name] = previous.shared[name]
+					continue
+				}
+			}
+			s, err := func() (sources.Source, error) {
+				childCtx, span := instrumentation.Tracer.Start(
+					ctx,
+					"toolbox/server/source/init",
+					trace.WithAttributes(attribute.String("source_kind", sc.SourceConfigKind())),
+					trace.WithAttributes(attribute.String("source_name", name)),
+				)
+				defer span.End()
+				s, err := sc.Initialize(childCtx, instrumentation.Tracer)
+				if err != nil {
+					return nil, fmt.Errorf("unable to initialize source %q: %w", name, err)
+				}
+				return s, nil
+			}()
+			if err != nil {
+				return nil, err
+			}
+			created = append(created, s)
+			sourcesMap[name] = s
+			shared[name] = &sharedSource{source: s}
+		}
+		l.InfoContext(ctx, fmt.Sprintf("Initialized %d sources.", len(sourcesMap)))
+
+		// initialize and validate the auth services from configs
+		authServicesMap := make(map[string]auth.AuthService)
+		for name, sc := range cfg.AuthServiceConfigs {
+			if previous != nil {
+				if psc, ok := previous.authServiceConfigs[name]; ok && reflect.DeepEqual(psc, sc) {
+					authServicesMap[name] = previous.authServices[name]
+					continue
+				}
+			}
+			a, err := func() (auth.AuthService, error) {
+				_, span := instrumentation.Tracer.Start(
+					ctx,
+					"toolbox/server/auth/init",
+					trace.WithAttributes(attribute.String("auth_kind", sc.AuthServiceConfigKind())),
+					trace.WithAttributes(attribute.String("auth_name", name)),
+				)
+				defer span.End()
+				a, err := sc.Initialize()
+				if err != nil {
+					return nil, fmt.Errorf("unable to initialize auth service %q: %w", name, err)
+				}
+				return a, nil
+			}()
+			if err != nil {
+				return nil, err
+			}
+			authServicesMap[name] = a
+		}
+		l.InfoContext(ctx, fmt.Sprintf("Initialized %d authServices.", len(authServicesMap)))
+
+		// initialize and validate the tools from configs
+		toolsMap := make(map[string]tools.Tool)
+		for name, tc := range cfg.ToolConfigs {
+			t, err := func() (tools.Tool, error) {
+				_, span := instrumentation.Tracer.Start(
+					ctx,
+					"toolbox/server/tool/init",
+					trace.WithAttributes(attribute.String("tool_kind", tc.ToolConfigKind())),
+					trace.WithAttributes(attribute.String("tool_name", name)),
+				)
+				defer span.End()
+				t, err := tc.Initialize(sourcesMap)
+				if err != nil {
+					return nil, fmt.Errorf("unable to initialize tool %q: %w", name, err)
+				}
+				return t, nil
+			}()
+			if err != nil {
+				return nil, err
+			}
+			toolsMap[name] = t
+		}
+		l.InfoContext(ctx, fmt.Sprintf("Initialized %d tools.", len(toolsMap)))
+
+		// create a default toolset that contains all tools
+		allToolNames := make([]string, 0, len(toolsMap))
+		for name := range toolsMap {
+			allToolNames = append(allToolNames, name)
+		}
+		toolsetConfigs := maps.Clone(cfg.ToolsetConfigs)
+		if toolsetConfigs == nil {
+			toolsetConfigs = make(ToolsetConfigs)
+		}
+		toolsetConfigs[""] = tools.ToolsetConfig{Name: "", ToolNames: allToolNames}
+
+		// initialize and validate the toolsets from configs
+		toolsetsMap := make(map[string]tools.Toolset)
+		for name, tc := range toolsetConfigs {
+			t, err := func() (tools.Toolset, error) {
+				_, span := instrumentation.Tracer.Start(
+					ctx,
+					"toolbox/server/toolset/init",
+					trace.WithAttributes(attribute.String("toolset_name", name)),
+				)
+				defer span.End()
+				t, err := tc.Initialize(cfg.Version, toolsMap)
+				if err != nil {
+					return tools.Toolset{}, fmt.Errorf("unable to initialize toolset %q: %w", name, err)
+				}
+				return t, err
+			}()
+			if err != nil {
+				return nil, err
+			}
+			toolsetsMap[name] = t
+		}
+		l.InfoContext(ctx, fmt.Sprintf("Initialized %d toolsets.", len(toolsetsMap)))
+
+		// initialize and validate the prompts from configs
+		promptsMap := make(map[string]prompts.Prompt)
+		for name, pc := range cfg.PromptConfigs {
+			p, err := func() (prompts.Prompt, error) {
+				_, span := instrumentation.Tracer.Start(
+					ctx,
+					"toolbox/server/prompt/init",
+					trace.WithAttributes(attribute.String("prompt_name", name)),
+				)
+				defer span.End()
+				p, err := pc.Initialize()
+				if err != nil {
+					return prompts.Prompt{}, fmt.Errorf("unable to initialize prompt %q: %w", name, err)
+				}
+				return p, nil
+			}()
+			if err != nil {
+				return nil, err
+			}
+			promptsMap[name] = p
+		}
+		l.InfoContext(ctx, fmt.Sprintf("Initialized %d prompts.", len(promptsMap)))
+
+		return &resourceSet{
+			sources:            sourcesMap,
+			authServices:       authServicesMap,
+			tools:              toolsMap,
+			toolsets:           toolsetsMap,
+			prompts:            promptsMap,
+			sourceConfigs:      cfg.SourceConfigs,
+			authServiceConfigs: cfg.AuthServiceConfigs,
+			toolConfigs:        cfg.ToolConfigs,
+			toolsetConfigs:     cfg.ToolsetConfigs,
+			promptConfigs:      cfg.PromptConfigs,
+			shared:             shared,
+		}, nil
+	}()
+	if err != nil {
+		// release the sources initialized before the failure
+		for _, s := range created {
+			closeSource(ctx, l, s)
+		}
+		return nil, err
+	}
+	for _, ss := range shared {
+		ss.users.Add(1)
+	}
+	return r, nil
+}
+
+// closeSource closes a source, if it holds resources that need to be released.
+func closeSource(ctx context.Context, l log.Logger, s sources.Source) {
+	c, ok := s.(sources.Closer)
+	if !ok {
+		return
+	}
+	if err := c.Close(); err != nil {
+		l.WarnContext(ctx, fmt.Sprintf("unable to close %s source: %s", s.SourceKind(), err))
+	}
+}
+
+// closeSources closes the sources currently served once the requests using
+// them are done, or ctx is done. Sources still used by previous resources are
+// closed once their requests are done.
+func (s *Server) closeSources(ctx context.Context) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	r := s.resourceMgr.retire()
+	done := make(chan struct{})
+	go func() {
+		r.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.logger.WarnContext(ctx, "sources still in use were not closed")
+		return
+	}
+	r.releaseSources(ctx, s.logger)
+}
+
+// Reload replaces the sources, auth services, tools, toolsets and prompts
+// served with the ones configured in cfg. Only the resources whose
+// configuration changed are initialized again. If any of them fails to
+// initialize, an error is returned and the current resources keep being
+// served.
+//
+// Requests already in progress complete with the previous resources. Sources
+// that are no longer used are closed once they are done, and connected MCP
+// clients are notified of the lists that changed.
+func (s *Server) Reload(ctx context.Context, cfg ServerConfig) error {
+	ctx, span := s.instrumentation.Tracer.Start(ctx, "toolbox/server/reload")
+	defer span.End()
+
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	current, release := s.resourceMgr.acquire()
+	r, err := initializeResources(ctx, cfg, s.instrumentation, s.logger, current)
+	release()
+	if err != nil {
+		return err
+	}
+	old := s.resourceMgr.swap(r)
+
+	go func() {
+		// sources may still be used by requests made before the swap, and
+		// the ones reused by r are only closed once r is done with them
+		old.inFlight.Wait()
+		old.releaseSources(ctx, s.logger)
+	}()
+
+	if !reflect.DeepEqual(old.toolConfigs, r.toolConfigs) || !reflect.DeepEqual(old.toolsetConfigs, r.toolsetConfigs) {
+		s.listeners.broadcast(ctx, s.logger, listChangedNotification(mcp.TOOLS_LIST_CHANGED))
+	}
+	if !reflect.DeepEqual(old.sourceConfigs, r.sourceConfigs) {
+		s.listeners.broadcast(ctx, s.logger, listChangedNotification(mcp.RESOURCES_LIST_CHANGED))
+	}
+	if !reflect.DeepEqual(old.promptConfigs, r.promptConfigs) {
+		s.listeners.broadcast(ctx, s.logger, listChangedNotification(mcp.PROMPTS_LIST_CHANGED))
+	}
+	s.logger.InfoContext(ctx, "Reloaded configuration.")
+	return nil
+}
+
+// listChangedNotification returns the notification sent to clients when a
+// list changes.
+func listChangedNotification(method string) mcp.JSONRPCNotification {
+	return mcp.JSONRPCNotification{
+		Jsonrpc:      mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{Method: method},
+	}
+}
+
+// listenerRegistry holds the MCP connections served by this server that are
+// able to receive server-initiated messages, such as event streams.
+type listenerRegistry struct {
+	mu        sync.Mutex
+	listeners map[string]*listener
+}
+
+type listener struct {
+	notify func(msg any) error
+}
+
+func newListenerRegistry() *listenerRegistry {
+	return &listenerRegistry{listeners: make(map[string]*listener)}
+}
+
+// add registers a connection until remove is called. It replaces any
+// connection registered with the same id.
+func (r *listenerRegistry) add(id string, notify func(msg any) error) (remove func()) {
+	l := &listener{notify: notify}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners[id] = l
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.listeners[id] == l {
+			delete(r.listeners, id)
+		}
+	}
+}
+
+// broadcast sends msg to every registered connection.
+func (r *listenerRegistry) broadcast(ctx context.Context, l log.Logger, msg any) {
+	r.mu.Lock()
+	listeners := make([]*listener, 0, len(r.listeners))
+	for _, listener := range r.listeners {
+		listeners = append(listeners, listener)
+	}
+	r.mu.Unlock()
+	for _, listener := range listeners {
+		if err := listener.notify(msg); err != nil {
+			l.DebugContext(ctx, fmt.Sprintf("unable to send notification: %s", err))
+		}
+	}
+}
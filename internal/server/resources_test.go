@@ -0,0 +1,237 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server/mcp"
+	"github.com/googleapis/genai-toolbox/internal/sources/sqlite"
+	"github.com/googleapis/genai-toolbox/internal/tools/sqlitesql"
+)
+
+func sqliteSourceConfig(name, database string) sqlite.Config {
+	return sqlite.Config{Name: name, Kind: sqlite.SourceKind, Database: database}
+}
+
+func sqliteToolConfig(name, source string) sqlitesql.Config {
+	return sqlitesql.Config{
+		Name:        name,
+		Kind:        sqlitesql.ToolKind,
+		Source:      source,
+		Description: "a test tool",
+		Statement:   "SELECT 1;",
+	}
+}
+
+// recordNotifications registers a listener on s and returns a function
+// returning the methods of the notifications it received.
+func recordNotifications(s *Server) func() []string {
+	var mu sync.Mutex
+	var methods []string
+	s.listeners.add("test", func(msg any) error {
+		mu.Lock()
+		defer mu.Unlock()
+		methods = append(methods, msg.(mcp.JSONRPCNotification).Method)
+		return nil
+	})
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		got := methods
+		methods = nil
+		return got
+	}
+}
+
+// isClosed reports whether the database of a sqlite source is closed.
+func isClosed(s *sqlite.Source) bool {
+	return s.Db.PingContext(context.Background()) != nil
+}
+
+func TestReload(t *testing.T) {
+	ctx := context.Background()
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	dir := t.TempDir()
+
+	cfg := ServerConfig{
+		Version:       fakeVersionString,
+		SourceConfigs: SourceConfigs{"my-sqlite": sqliteSourceConfig("my-sqlite", filepath.Join(dir, "first.db"))},
+		ToolConfigs:   ToolConfigs{"tool1": sqliteToolConfig("tool1", "my-sqlite")},
+	}
+	s, err := NewServer(ctx, cfg, testLogger)
+	if err != nil {
+		t.Fatalf("unable to initialize server: %s", err)
+	}
+	defer s.Shutdown(ctx)
+	notifications := recordNotifications(s)
+
+	current := func() *resourceSet {
+		r, release := s.resourceMgr.acquire()
+		defer release()
+		return r
+	}
+	first := current()
+	firstSource := first.sources["my-sqlite"].(*sqlite.Source)
+
+	// adding a tool reuses the unchanged source
+	cfg.ToolConfigs = ToolConfigs{
+		"tool1": sqliteToolConfig("tool1", "my-sqlite"),
+		"tool2": sqliteToolConfig("tool2", "my-sqlite"),
+	}
+	if err := s.Reload(ctx, cfg); err != nil {
+		t.Fatalf("unable to reload: %s", err)
+	}
+	second := current()
+	if _, ok := second.tools["tool2"]; !ok {
+		t.Fatalf("added tool is not served")
+	}
+	if _, ok := second.toolsets[""].Manifest.ToolsManifest["tool2"]; !ok {
+		t.Fatalf("added tool is not part of the default toolset")
+	}
+	if second.sources["my-sqlite"] != firstSource {
+		t.Fatalf("unchanged source was initialized again")
+	}
+	if isClosed(firstSource) {
+		t.Fatalf("unchanged source was closed")
+	}
+	if got, want := notifications(), []string{mcp.TOOLS_LIST_CHANGED}; !slices.Equal(got, want) {
+		t.Fatalf("unexpected notifications: got %v, want %v", got, want)
+	}
+
+	// an invalid configuration keeps the current one
+	invalid := cfg
+	invalid.ToolConfigs = ToolConfigs{"tool3": sqliteToolConfig("tool3", "missing-source")}
+	if err := s.Reload(ctx, invalid); err == nil {
+		t.Fatalf("expected error reloading invalid configuration")
+	}
+	if current() != second {
+		t.Fatalf("invalid configuration replaced the current one")
+	}
+	if got := notifications(); len(got) != 0 {
+		t.Fatalf("unexpected notifications: %v", got)
+	}
+
+	// a replaced source is closed once the requests using it are done
+	_, release := s.resourceMgr.acquire()
+	cfg.SourceConfigs = SourceConfigs{"my-sqlite": sqliteSourceConfig("my-sqlite", filepath.Join(dir, "second.db"))}
+	if err := s.Reload(ctx, cfg); err != nil {
+		t.Fatalf("unable to reload: %s", err)
+	}
+	if current().sources["my-sqlite"] == firstSource {
+		t.Fatalf("changed source was not initialized again")
+	}
+	if got, want := notifications(), []string{mcp.RESOURCES_LIST_CHANGED}; !slices.Equal(got, want) {
+		t.Fatalf("unexpected notifications: got %v, want %v", got, want)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if isClosed(firstSource) {
+		t.Fatalf("source was closed while in use")
+	}
+	release()
+	deadline := time.Now().Add(5 * time.Second)
+	for !isClosed(firstSource) {
+		if time.Now().After(deadline) {
+			t.Fatalf("replaced source was not closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReloadSourceSharedAcrossGenerations(t *testing.T) {
+	ctx := context.Background()
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	dir := t.TempDir()
+
+	cfg := ServerConfig{
+		Version:       fakeVersionString,
+		SourceConfigs: SourceConfigs{"my-sqlite": sqliteSourceConfig("my-sqlite", filepath.Join(dir, "first.db"))},
+		ToolConfigs:   ToolConfigs{"tool1": sqliteToolConfig("tool1", "my-sqlite")},
+	}
+	s, err := NewServer(ctx, cfg, testLogger)
+	if err != nil {
+		t.Fatalf("unable to initialize server: %s", err)
+	}
+	defer s.Shutdown(ctx)
+
+	// a request on the first resources keeps using the source
+	first, release := s.resourceMgr.acquire()
+	firstSource := first.sources["my-sqlite"].(*sqlite.Source)
+
+	// the second resources reuse the source
+	cfg.ToolConfigs = ToolConfigs{"tool2": sqliteToolConfig("tool2", "my-sqlite")}
+	if err := s.Reload(ctx, cfg); err != nil {
+		t.Fatalf("unable to reload: %s", err)
+	}
+	// the third resources drop it, once the second ones have no requests
+	cfg.SourceConfigs = SourceConfigs{"my-sqlite": sqliteSourceConfig("my-sqlite", filepath.Join(dir, "second.db"))}
+	if err := s.Reload(ctx, cfg); err != nil {
+		t.Fatalf("unable to reload: %s", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if isClosed(firstSource) {
+		t.Fatalf("source was closed while used by a request on the first resources")
+	}
+
+	release()
+	deadline := time.Now().Add(5 * time.Second)
+	for !isClosed(firstSource) {
+		if time.Now().After(deadline) {
+			t.Fatalf("dropped source was not closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestResourceManagerRetire(t *testing.T) {
+	m := newResourceManager(&resourceSet{})
+	r, releaseBefore := m.acquire()
+	if got := m.retire(); got != r {
+		t.Fatalf("retire returned other resources than the current ones")
+	}
+	// requests made once the resources are retired are not waited for
+	_, releaseAfter := m.acquire()
+	defer releaseAfter()
+
+	done := make(chan struct{})
+	go func() {
+		r.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatalf("resources were released while in use")
+	case <-time.After(50 * time.Millisecond):
+	}
+	releaseBefore()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("resources were not released")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/jsonschema"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// prototypeDecoder decodes a config from an empty document, so that only the
+// defaults set by its factory are filled.
+type prototypeDecoder func(ctx context.Context, kind string, decoder *yaml.Decoder) (any, error)
+
+// JSONSchema describes the sources section of a tools file.
+func (SourceConfigs) JSONSchema(r *jsonschema.Reflector) map[string]any {
+	return kindsSchema(r, "source", sources.Kinds(), func(ctx context.Context, kind string, decoder *yaml.Decoder) (any, error) {
+		return sources.DecodeConfig(ctx, kind, "", decoder)
+	})
+}
+
+// JSONSchema describes the authServices section of a tools file.
+func (AuthServiceConfigs) JSONSchema(r *jsonschema.Reflector) map[string]any {
+	return kindsSchema(r, "authService", auth.Kinds(), func(ctx context.Context, kind string, decoder *yaml.Decoder) (any, error) {
+		return auth.DecodeConfig(ctx, kind, "", decoder)
+	})
+}
+
+// JSONSchema describes the tools section of a tools file.
+func (ToolConfigs) JSONSchema(r *jsonschema.Reflector) map[string]any {
+	return kindsSchema(r, "tool", tools.Kinds(), func(ctx context.Context, kind string, decoder *yaml.Decoder) (any, error) {
+		return tools.DecodeConfig(ctx, kind, "", decoder)
+	})
+}
+
+// JSONSchema describes the toolsets section of a tools file.
+func (ToolsetConfigs) JSONSchema(*jsonschema.Reflector) map[string]any {
+	return map[string]any{
+		"type": "object",
+		"additionalProperties": map[string]any{
+			"type":  "array",
+			"items": map[string]any{"type": "string"},
+		},
+	}
+}
+
+// JSONSchema describes the prompts section of a tools file.
+func (PromptConfigs) JSONSchema(r *jsonschema.Reflector) map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": withoutName(r.Reflect(prompts.PromptConfig{})),
+	}
+}
+
+// kindsSchema describes a section of a tools file mapping names to configs of
+// the given kinds. Each config must match the schema of its `kind` field.
+func kindsSchema(r *jsonschema.Reflector, section string, kinds []string, decode prototypeDecoder) map[string]any {
+	// factories may log warnings
+	logger, _ := log.NewStdLogger(io.Discard, io.Discard, "error")
+	ctx := util.WithLogger(context.Background(), logger)
+
+	oneOf := make([]any, 0, len(kinds))
+	for _, kind := range kinds {
+		oneOf = append(oneOf, r.Define(section+"-"+kind, func(r *jsonschema.Reflector) map[string]any {
+			return kindSchema(ctx, r, kind, decode)
+		}))
+	}
+	return map[string]any{
+		"type": "object",
+		"additionalProperties": map[string]any{
+			"type":       "object",
+			"required":   []string{"kind"},
+			"properties": map[string]any{"kind": map[string]any{"enum": kinds}},
+			"oneOf":      oneOf,
+		},
+	}
+}
+
+// kindSchema describes the config of a kind, derived from the struct decoded
+// by its factory.
+func kindSchema(ctx context.Context, r *jsonschema.Reflector, kind string, decode prototypeDecoder) map[string]any {
+	kindProperty := map[string]any{"const": kind}
+	cfg, err := decode(ctx, kind, yaml.NewDecoder(strings.NewReader("{}")))
+	if err != nil {
+		// the fields of the kind are unknown
+		return map[string]any{
+			"type":       "object",
+			"required":   []string{"kind"},
+			"properties": map[string]any{"kind": kindProperty},
+		}
+	}
+	s := withoutName(r.Reflect(cfg))
+	if properties, ok := s["properties"].(map[string]any); ok {
+		properties["kind"] = kindProperty
+	}
+	return s
+}
+
+// withoutName removes the `name` property of a config, which is set from its
+// key in the tools file.
+func withoutName(s map[string]any) map[string]any {
+	if properties, ok := s["properties"].(map[string]any); ok {
+		delete(properties, "name")
+	}
+	if required, ok := s["required"].([]string); ok {
+		s["required"] = slices.DeleteFunc(slices.Clone(required), func(n string) bool { return n == "name" })
+	}
+	return s
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/googleapis/genai-toolbox/internal/server/mcp"
+)
+
+// maxStdioMessageSize is the largest JSON-RPC message accepted over stdio.
+const maxStdioMessageSize = 10 * 1024 * 1024
+
+// stdioConnId identifies the single MCP connection served over stdio.
+const stdioConnId = "stdio"
+
+// ServeStdio serves MCP over the stdio transport. Newline-delimited JSON-RPC
+// messages are read from stdin and responses are written to stdout. Messages
+// are processed concurrently, so that long-running tool calls can be
+// cancelled, except initialize requests, which are processed before reading
+// the next message so that later messages use the negotiated protocol version.
+// It returns when stdin is closed or the context is canceled. stdin is closed on
+// return if it is an io.Closer, to stop reading from it.
+func (s *Server) ServeStdio(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	ctx, span := s.instrumentation.Tracer.Start(ctx, "toolbox/server/mcp/stdio")
+	defer span.End()
+	s.logger.DebugContext(ctx, "Starting a stdio server.")
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	out := &stdioWriter{w: stdout}
+	defer s.listeners.add(stdioConnId, out.write)()
+	// the protocol version is negotiated by the initialize request, which is
+	// processed by this goroutine before any later message
+	protocolVersion := mcp.LATEST_PROTOCOL_VERSION
+
+	// wait for in-flight messages before returning
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	lines := make(chan []byte)
+	scanErr := make(chan error, 1)
+	if c, ok := stdin.(io.Closer); ok {
+		// unblock the pending read of the scanner
+		defer c.Close()
+	}
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(stdin)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxStdioMessageSize)
+		for scanner.Scan() {
+			// the scanner reuses its buffer, so the line must be copied
+			line := bytes.Clone(scanner.Bytes())
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	// process handles a single line, sending its response to stdout
+	process := func(line []byte, conn *mcpConn) mcp.JSONRPCMessage {
+		// stdio has no headers to verify auth services against
+		res, _ := processMcpBody(withMcpConn(ctx, conn), s, line, "", map[string]map[string]any{})
+		// Notifications do not expect a response
+		if res == nil {
+			return nil
+		}
+		if err := out.write(res); err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			// stop serving if stdout is no longer writable
+			if out.failed(err) {
+				cancel(err)
+			}
+		}
+		return res
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := context.Cause(ctx); out.failed(err) {
+				return err
+			}
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				if err := <-scanErr; err != nil {
+					return fmt.Errorf("unable to read from stdin: %w", err)
+				}
+				s.logger.DebugContext(ctx, "stdin closed")
+				return nil
+			}
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			conn := &mcpConn{id: stdioConnId, notify: out.write, protocolVersion: protocolVersion}
+			if isInitializeRequest(line) {
+				if v, ok := initializedProtocolVersion(line, process(line, conn)); ok {
+					protocolVersion = v
+				}
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				process(line, conn)
+			}()
+		}
+	}
+}
+
+// isInitializeRequest reports whether line is a single initialize request.
+func isInitializeRequest(line []byte) bool {
+	var baseMessage struct {
+		Method string `json:"method"`
+	}
+	return json.Unmarshal(line, &baseMessage) == nil && baseMessage.Method == "initialize"
+}
+
+// stdioWriter serializes the messages written to stdout.
+type stdioWriter struct {
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+}
+
+// write writes a single JSON-RPC message followed by a newline.
+func (o *stdioWriter) write(msg any) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("unable to marshal response: %w", err)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.err != nil {
+		return o.err
+	}
+	if _, err := o.w.Write(append(b, '\n')); err != nil {
+		o.err = fmt.Errorf("unable to write to stdout: %w", err)
+		return o.err
+	}
+	return nil
+}
+
+// failed reports whether err is the error that caused writing to stdout to fail.
+func (o *stdioWriter) failed(err error) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.err != nil && err == o.err
+}
@@ -0,0 +1,219 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestServeStdio(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	s, shutdown := setUpTestServer(t, toolsMap, toolsets)
+	defer shutdown()
+
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		``,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"no_params","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"foo"}`,
+	}, "\n")
+	out := &bytes.Buffer{}
+	if err := s.ServeStdio(context.Background(), strings.NewReader(in), out); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("unexpected number of responses: got %d, want %d: %s", len(lines), 4, out.String())
+	}
+	// messages are processed concurrently, so responses can arrive in any order
+	wantKeys := map[float64]string{1: "result", 2: "result", 3: "result", 4: "error"}
+	for _, line := range lines {
+		var got map[string]any
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("unable to unmarshal response %q: %s", line, err)
+		}
+		id, _ := got["id"].(float64)
+		wantKey, ok := wantKeys[id]
+		if !ok {
+			t.Fatalf("unexpected id: got %v", got["id"])
+		}
+		delete(wantKeys, id)
+		if _, ok := got[wantKey]; !ok {
+			t.Fatalf("response %v is missing %q: %s", id, wantKey, line)
+		}
+	}
+}
+
+func TestServeStdioInitializeBeforeLaterMessages(t *testing.T) {
+	readOnly := true
+	annotatedTool := MockTool{
+		Name:        "annotated",
+		Params:      []tools.Parameter{},
+		Annotations: &tools.ToolAnnotations{ReadOnlyHint: &readOnly},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, annotatedTool})
+	s, shutdown := setUpTestServer(t, toolsMap, toolsets)
+	defer shutdown()
+
+	// annotations are omitted for 2024-11-05, which must be negotiated before
+	// the tools are listed
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+	}, "\n")
+	for i := 0; i < 20; i++ {
+		out := &bytes.Buffer{}
+		if err := s.ServeStdio(context.Background(), strings.NewReader(in), out); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("unexpected number of responses: got %d, want %d: %s", len(lines), 2, out.String())
+		}
+		// the initialize response is written before the tools are listed
+		var got struct {
+			Result struct {
+				Tools []map[string]any `json:"tools"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(lines[1]), &got); err != nil {
+			t.Fatalf("unable to unmarshal response %q: %s", lines[1], err)
+		}
+		for _, tool := range got.Result.Tools {
+			if _, ok := tool["annotations"]; ok {
+				t.Fatalf("unexpected annotations for protocol version 2024-11-05: %s", lines[1])
+			}
+		}
+	}
+}
+
+func TestServeStdioCancel(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	s, shutdown := setUpTestServer(t, toolsMap, toolsets)
+	defer shutdown()
+
+	inR, inW := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- s.ServeStdio(ctx, inR, io.Discard)
+	}()
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("ServeStdio did not return after the context was canceled")
+	}
+	// stdin is closed, so that the pending read of the scanner returns
+	if _, err := io.WriteString(inW, "{}\n"); !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatalf("unexpected error writing to stdin: got %v, want %v", err, io.ErrClosedPipe)
+	}
+}
+
+func TestServeStdioProgressAndCancellation(t *testing.T) {
+	invoked := make(chan struct{})
+	cancelled := make(chan struct{})
+	slowTool := MockTool{
+		Name:   "slow",
+		Params: []tools.Parameter{},
+		InvokeFunc: func(ctx context.Context, _ tools.ParamValues) ([]any, error) {
+			tools.ReportProgress(ctx, 1, 10, "started")
+			close(invoked)
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, slowTool})
+	s, shutdown := setUpTestServer(t, toolsMap, toolsets)
+	defer shutdown()
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- s.ServeStdio(context.Background(), inR, outW)
+	}()
+	out := bufio.NewScanner(outR)
+	readMessage := func() map[string]any {
+		if !out.Scan() {
+			t.Fatalf("unable to read message: %v", out.Err())
+		}
+		var got map[string]any
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatalf("unable to unmarshal message %q: %s", out.Text(), err)
+		}
+		return got
+	}
+	writeMessage := func(msg string) {
+		if _, err := io.WriteString(inW, msg+"\n"); err != nil {
+			t.Fatalf("unable to write message: %s", err)
+		}
+	}
+
+	writeMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow","arguments":{},"_meta":{"progressToken":"my-token"}}}`)
+	got := readMessage()
+	want := map[string]any{
+		"jsonrpc": "2.0",
+		"method":  "notifications/progress",
+		"params": map[string]any{
+			"progressToken": "my-token",
+			"progress":      1.0,
+			"total":         10.0,
+			"message":       "started",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected progress notification: got %+v, want %+v", got, want)
+	}
+	<-invoked
+
+	writeMessage(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1,"reason":"user cancelled"}}`)
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("tool invocation was not cancelled")
+	}
+
+	// the cancelled request does not get a response
+	writeMessage(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"no_params","arguments":{}}}`)
+	got = readMessage()
+	if got["id"] != 2.0 {
+		t.Fatalf("unexpected response: got %+v, want a response to request 2", got)
+	}
+
+	inW.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
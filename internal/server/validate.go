@@ -0,0 +1,173 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// Severities of a ConfigIssue.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Kinds of resources a ConfigIssue can be about.
+const (
+	ResourceSource      = "source"
+	ResourceAuthService = "authService"
+	ResourceTool        = "tool"
+	ResourceToolset     = "toolset"
+	ResourcePrompt      = "prompt"
+)
+
+// ConfigIssue is a problem found in a configuration.
+type ConfigIssue struct {
+	// Severity is SeverityError if the server would fail to start with the
+	// configuration, or SeverityWarning otherwise.
+	Severity string `json:"severity"`
+	// Resource is the kind of resource the issue is about, such as
+	// ResourceTool. It is empty for issues about the configuration as a whole.
+	Resource string `json:"resource,omitempty"`
+	// Name is the name of the resource the issue is about.
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message"`
+}
+
+func (i ConfigIssue) String() string {
+	if i.Resource == "" {
+		return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+	}
+	return fmt.Sprintf("%s: %s %q: %s", i.Severity, i.Resource, i.Name, i.Message)
+}
+
+// resourceOrder is the order issues are reported in.
+var resourceOrder = []string{"", ResourceSource, ResourceAuthService, ResourceTool, ResourceToolset, ResourcePrompt}
+
+// ValidateConfigs checks the resource configs of cfg without initializing any
+// of them, so that no connection is made to their sources. It returns every
+// issue found, ordered by resource kind and name.
+//
+// Tool configs are only checked for references to other resources if they
+// implement tools.ReferencingConfig. The checks of tool configs implementing
+// tools.ValidatingConfig are also run.
+func ValidateConfigs(cfg ServerConfig) []ConfigIssue {
+	var issues []ConfigIssue
+	add := func(severity, resource, name, format string, a ...any) {
+		issues = append(issues, ConfigIssue{
+			Severity: severity,
+			Resource: resource,
+			Name:     name,
+			Message:  fmt.Sprintf(format, a...),
+		})
+	}
+	checkName := func(resource, name string) {
+		if !tools.IsValidName(name) {
+			add(SeverityError, resource, name, "invalid name: must only contain letters, digits, '_' and '-'")
+		}
+	}
+
+	for name := range cfg.SourceConfigs {
+		checkName(ResourceSource, name)
+	}
+	for name := range cfg.AuthServiceConfigs {
+		checkName(ResourceAuthService, name)
+	}
+
+	usedSources := make(map[string]bool)
+	usedAuthServices := make(map[string]bool)
+	for name, tc := range cfg.ToolConfigs {
+		checkName(ResourceTool, name)
+		if vc, ok := tc.(tools.ValidatingConfig); ok {
+			if err := vc.Validate(); err != nil {
+				add(SeverityError, ResourceTool, name, "%s", err)
+			}
+		}
+		rc, ok := tc.(tools.ReferencingConfig)
+		if !ok {
+			continue
+		}
+		refs := rc.References()
+		if sc, ok := cfg.SourceConfigs[refs.Source]; !ok {
+			add(SeverityError, ResourceTool, name, "source %q does not exist", refs.Source)
+		} else {
+			usedSources[refs.Source] = true
+			if !slices.Contains(refs.CompatibleSourceKinds, sc.SourceConfigKind()) {
+				add(SeverityError, ResourceTool, name, "source %q is of kind %q, but %q tools require one of %q", refs.Source, sc.SourceConfigKind(), tc.ToolConfigKind(), refs.CompatibleSourceKinds)
+			}
+		}
+		for _, a := range refs.AuthRequired {
+			if _, ok := cfg.AuthServiceConfigs[a]; !ok {
+				add(SeverityError, ResourceTool, name, "authRequired refers to undefined auth service %q", a)
+				continue
+			}
+			usedAuthServices[a] = true
+		}
+		for _, p := range refs.Parameters {
+			for _, a := range p.GetAuthServices() {
+				if _, ok := cfg.AuthServiceConfigs[a.Name]; !ok {
+					add(SeverityError, ResourceTool, name, "parameter %q refers to undefined auth service %q", p.GetName(), a.Name)
+					continue
+				}
+				usedAuthServices[a.Name] = true
+			}
+		}
+	}
+
+	for name, tc := range cfg.ToolsetConfigs {
+		checkName(ResourceToolset, name)
+		for _, toolName := range tc.ToolNames {
+			if _, ok := cfg.ToolConfigs[toolName]; !ok {
+				add(SeverityError, ResourceToolset, name, "tool %q does not exist", toolName)
+			}
+		}
+	}
+
+	for name, pc := range cfg.PromptConfigs {
+		if !tools.IsValidName(name) {
+			checkName(ResourcePrompt, name)
+			continue
+		}
+		// initializing a prompt only parses its templates
+		if _, err := pc.Initialize(); err != nil {
+			add(SeverityError, ResourcePrompt, name, "%s", err)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(cfg.SourceConfigs)) {
+		if !usedSources[name] {
+			add(SeverityWarning, ResourceSource, name, "not used by any tool")
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.AuthServiceConfigs)) {
+		if !usedAuthServices[name] {
+			add(SeverityWarning, ResourceAuthService, name, "not used by any tool")
+		}
+	}
+
+	slices.SortStableFunc(issues, func(a, b ConfigIssue) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(resourceOrder, a.Resource), slices.Index(resourceOrder, b.Resource)),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+	return issues
+}
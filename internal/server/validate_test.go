@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth/google"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mysqlsql"
+)
+
+func TestValidateConfigs(t *testing.T) {
+	authService := google.Config{Name: "my-auth", Kind: google.AuthServiceKind, ClientID: "id"}
+	withAuth := sqliteToolConfig("with_auth", "my-sqlite")
+	withAuth.AuthRequired = []string{"my-auth", "missing-auth"}
+	withAuth.Parameters = tools.Parameters{
+		tools.NewStringParameterWithAuth("email", "the user email", []tools.ParamAuthService{{Name: "other-auth", Field: "email"}}),
+	}
+	incompatible := mysqlsql.Config{
+		Name:        "incompatible",
+		Kind:        mysqlsql.ToolKind,
+		Source:      "my-sqlite",
+		Description: "a test tool",
+		Statement:   "SELECT 1;",
+	}
+	badTemplate := sqliteToolConfig("bad_template", "my-sqlite")
+	badTemplate.Statement = "SELECT * FROM {{.table}"
+	badTemplate.Parameters = tools.Parameters{tools.NewIdentifierParameter("table", "the table", []string{"users"})}
+	badOutputSchema := sqliteToolConfig("bad_output_schema", "my-sqlite")
+	badOutputSchema.OutputSchema = map[string]any{"type": "row"}
+
+	tcs := []struct {
+		desc string
+		cfg  ServerConfig
+		want []ConfigIssue
+	}{
+		{
+			desc: "valid",
+			cfg: ServerConfig{
+				SourceConfigs:      SourceConfigs{"my-sqlite": sqliteSourceConfig("my-sqlite", "my.db")},
+				AuthServiceConfigs: AuthServiceConfigs{"my-auth": authService},
+				ToolConfigs: ToolConfigs{
+					"tool1": sqliteToolConfig("tool1", "my-sqlite"),
+					"tool2": func() tools.ToolConfig {
+						c := sqliteToolConfig("tool2", "my-sqlite")
+						c.AuthRequired = []string{"my-auth"}
+						return c
+					}(),
+				},
+				ToolsetConfigs: ToolsetConfigs{"my-toolset": tools.ToolsetConfig{Name: "my-toolset", ToolNames: []string{"tool1", "tool2"}}},
+			},
+		},
+		{
+			desc: "missing references",
+			cfg: ServerConfig{
+				SourceConfigs:      SourceConfigs{"my-sqlite": sqliteSourceConfig("my-sqlite", "my.db")},
+				AuthServiceConfigs: AuthServiceConfigs{"my-auth": authService},
+				ToolConfigs: ToolConfigs{
+					"with_auth":    withAuth,
+					"incompatible": incompatible,
+					"no_source":    sqliteToolConfig("no_source", "missing-source"),
+				},
+				ToolsetConfigs: ToolsetConfigs{"my-toolset": tools.ToolsetConfig{Name: "my-toolset", ToolNames: []string{"with_auth", "missing_tool"}}},
+			},
+			want: []ConfigIssue{
+				{Severity: SeverityError, Resource: ResourceTool, Name: "incompatible", Message: `source "my-sqlite" is of kind "sqlite", but "mysql-sql" tools require one of ["cloud-sql-mysql" "mysql"]`},
+				{Severity: SeverityError, Resource: ResourceTool, Name: "no_source", Message: `source "missing-source" does not exist`},
+				{Severity: SeverityError, Resource: ResourceTool, Name: "with_auth", Message: `authRequired refers to undefined auth service "missing-auth"`},
+				{Severity: SeverityError, Resource: ResourceTool, Name: "with_auth", Message: `parameter "email" refers to undefined auth service "other-auth"`},
+				{Severity: SeverityError, Resource: ResourceToolset, Name: "my-toolset", Message: `tool "missing_tool" does not exist`},
+			},
+		},
+		{
+			desc: "invalid tool config",
+			cfg: ServerConfig{
+				SourceConfigs: SourceConfigs{"my-sqlite": sqliteSourceConfig("my-sqlite", "my.db")},
+				ToolConfigs:   ToolConfigs{"bad_template": badTemplate, "bad_output_schema": badOutputSchema},
+			},
+			want: []ConfigIssue{
+				{Severity: SeverityError, Resource: ResourceTool, Name: "bad_output_schema", Message: `invalid outputSchema: /type: unknown type "row"`},
+				{Severity: SeverityError, Resource: ResourceTool, Name: "bad_template", Message: "unable to parse statement template: template: statement:1: bad character U+007D '}'"},
+			},
+		},
+		{
+			desc: "invalid names and unused resources",
+			cfg: ServerConfig{
+				SourceConfigs: SourceConfigs{
+					"my-sqlite":    sqliteSourceConfig("my-sqlite", "my.db"),
+					"other sqlite": sqliteSourceConfig("other sqlite", "other.db"),
+				},
+				AuthServiceConfigs: AuthServiceConfigs{"my-auth": authService},
+				ToolConfigs:        ToolConfigs{"my.tool": sqliteToolConfig("my.tool", "my-sqlite")},
+				PromptConfigs: PromptConfigs{
+					"my prompt":  prompts.PromptConfig{Name: "my prompt", Messages: []prompts.MessageConfig{{Content: "hello"}}},
+					"bad_prompt": prompts.PromptConfig{Name: "bad_prompt", Messages: []prompts.MessageConfig{{Content: "{{"}}},
+				},
+			},
+			want: []ConfigIssue{
+				{Severity: SeverityError, Resource: ResourceSource, Name: "other sqlite", Message: "invalid name: must only contain letters, digits, '_' and '-'"},
+				{Severity: SeverityWarning, Resource: ResourceSource, Name: "other sqlite", Message: "not used by any tool"},
+				{Severity: SeverityWarning, Resource: ResourceAuthService, Name: "my-auth", Message: "not used by any tool"},
+				{Severity: SeverityError, Resource: ResourceTool, Name: "my.tool", Message: "invalid name: must only contain letters, digits, '_' and '-'"},
+				{Severity: SeverityError, Resource: ResourcePrompt, Name: "bad_prompt", Message: "unable to parse template for message 0: template: bad_prompt[0]:1: unclosed action"},
+				{Severity: SeverityError, Resource: ResourcePrompt, Name: "my prompt", Message: "invalid name: must only contain letters, digits, '_' and '-'"},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := ValidateConfigs(tc.cfg)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect issues: diff %v", diff)
+			}
+		})
+	}
+}
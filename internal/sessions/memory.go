@@ -0,0 +1,260 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessions
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// validate interface
+var _ Store = &MemoryStore{}
+
+type memorySession struct {
+	Session
+	// events holds the most recent events of the session, in order.
+	events      []Event
+	lastEventId uint64
+	// newEvent is closed and replaced whenever an event is added.
+	newEvent chan struct{}
+	// stream is closed when the subscription to the session is replaced by
+	// another one. It is nil if there is no subscription.
+	stream chan struct{}
+	// done is closed when the session is deleted.
+	done chan struct{}
+	// lastActive is the last time the session was used or had a
+	// subscription.
+	lastActive time.Time
+}
+
+// MemoryStore keeps sessions in memory. They are only available to the
+// server that created them.
+type MemoryStore struct {
+	cfg Config
+
+	mu       sync.Mutex
+	sessions map[string]*memorySession
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewMemoryStore returns a MemoryStore. If cfg has an IdleTimeout, idle
+// sessions are removed in the background until the store is closed.
+func NewMemoryStore(cfg Config) *MemoryStore {
+	m := &MemoryStore{
+		cfg:      cfg.withDefaults(),
+		sessions: make(map[string]*memorySession),
+		closed:   make(chan struct{}),
+	}
+	if m.cfg.IdleTimeout > 0 {
+		go m.expireIdleSessions()
+	}
+	return m
+}
+
+func (m *MemoryStore) Create(ctx context.Context, s Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s.Claims = maps.Clone(s.Claims)
+	m.sessions[s.Id] = &memorySession{
+		Session:    s,
+		newEvent:   make(chan struct{}),
+		done:       make(chan struct{}),
+		lastActive: time.Now(),
+	}
+	return nil
+}
+
+func (m *MemoryStore) Get(ctx context.Context, id string) (Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return Session{}, ErrNotFound
+	}
+	return s.Session, nil
+}
+
+func (m *MemoryStore) SetProtocolVersion(ctx context.Context, id, version string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return ErrNotFound
+	}
+	s.ProtocolVersion = version
+	return nil
+}
+
+func (m *MemoryStore) Touch(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return ErrNotFound
+	}
+	s.lastActive = time.Now()
+	return nil
+}
+
+func (m *MemoryStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return ErrNotFound
+	}
+	close(s.done)
+	delete(m.sessions, id)
+	return nil
+}
+
+func (m *MemoryStore) Publish(ctx context.Context, id string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return ErrNotFound
+	}
+	s.lastEventId++
+	s.events = append(s.events, Event{Id: s.lastEventId, Data: data})
+	if len(s.events) > m.cfg.ReplayBufferSize {
+		s.events = slices.Delete(s.events, 0, len(s.events)-m.cfg.ReplayBufferSize)
+	}
+	close(s.newEvent)
+	s.newEvent = make(chan struct{})
+	return nil
+}
+
+func (m *MemoryStore) Subscribe(ctx context.Context, id string, lastEventId uint64) (*Subscription, error) {
+	m.mu.Lock()
+	s, ok := m.sessions[id]
+	if !ok {
+		m.mu.Unlock()
+		return nil, ErrNotFound
+	}
+	if s.stream != nil {
+		close(s.stream)
+	}
+	stream := make(chan struct{})
+	s.stream = stream
+	pending, missed := eventsAfter(s.events, lastEventId, s.lastEventId)
+	newEvent := s.newEvent
+	m.mu.Unlock()
+
+	events := make(chan Event)
+	sub := &Subscription{Events: events, Missed: missed}
+	go func() {
+		defer close(events)
+		defer m.detach(s, stream)
+		for {
+			for _, e := range pending {
+				select {
+				case events <- e:
+					lastEventId = e.Id
+				case <-stream:
+					sub.err = ErrStreamReplaced
+					return
+				case <-s.done:
+					sub.err = ErrSessionClosed
+					return
+				case <-ctx.Done():
+					sub.err = ctx.Err()
+					return
+				}
+			}
+			select {
+			case <-newEvent:
+				m.mu.Lock()
+				pending, _ = eventsAfter(s.events, lastEventId, s.lastEventId)
+				newEvent = s.newEvent
+				m.mu.Unlock()
+			case <-stream:
+				sub.err = ErrStreamReplaced
+				return
+			case <-s.done:
+				sub.err = ErrSessionClosed
+				return
+			case <-ctx.Done():
+				sub.err = ctx.Err()
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// detach removes a subscription from a session, if it has not been replaced.
+func (m *MemoryStore) detach(s *memorySession, stream chan struct{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s.stream == stream {
+		s.stream = nil
+	}
+	s.lastActive = time.Now()
+}
+
+// RemoveIdle deletes the sessions that have no subscription and have not
+// been used for IdleTimeout at now. It returns the ids of the deleted
+// sessions.
+func (m *MemoryStore) RemoveIdle(now time.Time) []string {
+	if m.cfg.IdleTimeout <= 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var removed []string
+	for id, s := range m.sessions {
+		if s.stream == nil && now.Sub(s.lastActive) >= m.cfg.IdleTimeout {
+			close(s.done)
+			delete(m.sessions, id)
+			removed = append(removed, id)
+		}
+	}
+	return removed
+}
+
+// expireIdleSessions periodically removes idle sessions until the store is
+// closed.
+func (m *MemoryStore) expireIdleSessions() {
+	ticker := time.NewTicker(m.cfg.IdleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			m.RemoveIdle(now)
+		case <-m.closed:
+			return
+		}
+	}
+}
+
+// Close deletes every session, ending their subscriptions.
+func (m *MemoryStore) Close() error {
+	m.closeOnce.Do(func() {
+		close(m.closed)
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for id, s := range m.sessions {
+			close(s.done)
+			delete(m.sessions, id)
+		}
+	})
+	return nil
+}
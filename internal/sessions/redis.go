@@ -0,0 +1,343 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// validate interface
+var _ Store = &RedisStore{}
+
+// redisKeyPrefix prefixes the keys and channels used by the RedisStore.
+const redisKeyPrefix = "toolbox:sessions:"
+
+// Messages published on the channel of a session, in addition to events.
+const (
+	// closedMessage is published when the session is deleted.
+	closedMessage = "closed"
+	// attachPrefix is followed by the id of a new subscription, ending the
+	// previous one.
+	attachPrefix = "attach "
+)
+
+// publishScript adds an event to the buffer of an existing session and
+// publishes it to the subscriber of the session. Events are encoded as their
+// id followed by a space and their data.
+var publishScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return -1
+end
+local id = redis.call('HINCRBY', KEYS[1], 'lastEventId', 1)
+local event = id .. ' ' .. ARGV[1]
+redis.call('RPUSH', KEYS[2], event)
+redis.call('LTRIM', KEYS[2], -tonumber(ARGV[2]), -1)
+redis.call('PUBLISH', KEYS[3], event)
+if tonumber(ARGV[3]) > 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+	redis.call('PEXPIRE', KEYS[2], ARGV[3])
+end
+return id
+`)
+
+// setFieldScript sets a field of an existing session.
+var setFieldScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+return 1
+`)
+
+// RedisStore keeps sessions in Redis, so that they are shared by every server
+// using the same Redis instance. Events are delivered to the server holding
+// the stream of a session through Redis pub/sub.
+//
+// A session is stored as a hash, and its replay buffer as a list. Both expire
+// after IdleTimeout unless the session is used or has a subscription.
+type RedisStore struct {
+	client redis.UniversalClient
+	cfg    Config
+
+	// ctx is cancelled when the store is closed, ending its subscriptions.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewRedisStore returns a RedisStore using client. The client is closed with
+// the store.
+func NewRedisStore(client redis.UniversalClient, cfg Config) *RedisStore {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &RedisStore{client: client, cfg: cfg.withDefaults(), ctx: ctx, cancel: cancel}
+}
+
+func sessionKey(id string) string {
+	return redisKeyPrefix + id
+}
+
+func eventsKey(id string) string {
+	return redisKeyPrefix + id + ":events"
+}
+
+func channelName(id string) string {
+	return redisKeyPrefix + id + ":channel"
+}
+
+func (r *RedisStore) Create(ctx context.Context, s Session) error {
+	claims, err := json.Marshal(s.Claims)
+	if err != nil {
+		return fmt.Errorf("unable to marshal claims: %w", err)
+	}
+	_, err = r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.HSet(ctx, sessionKey(s.Id),
+			"toolsetName", s.ToolsetName,
+			"claims", claims,
+			"protocolVersion", s.ProtocolVersion,
+			"lastEventId", 0,
+		)
+		if r.cfg.IdleTimeout > 0 {
+			p.PExpire(ctx, sessionKey(s.Id), r.cfg.IdleTimeout)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to store session: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisStore) Get(ctx context.Context, id string) (Session, error) {
+	fields, err := r.client.HGetAll(ctx, sessionKey(id)).Result()
+	if err != nil {
+		return Session{}, fmt.Errorf("unable to retrieve session: %w", err)
+	}
+	if len(fields) == 0 {
+		return Session{}, ErrNotFound
+	}
+	s := Session{
+		Id:              id,
+		ToolsetName:     fields["toolsetName"],
+		ProtocolVersion: fields["protocolVersion"],
+	}
+	if err := json.Unmarshal([]byte(fields["claims"]), &s.Claims); err != nil {
+		return Session{}, fmt.Errorf("unable to unmarshal claims: %w", err)
+	}
+	return s, nil
+}
+
+func (r *RedisStore) SetProtocolVersion(ctx context.Context, id, version string) error {
+	ok, err := setFieldScript.Run(ctx, r.client, []string{sessionKey(id)}, "protocolVersion", version).Int()
+	if err != nil {
+		return fmt.Errorf("unable to update session: %w", err)
+	}
+	if ok == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *RedisStore) Touch(ctx context.Context, id string) error {
+	if r.cfg.IdleTimeout <= 0 {
+		return nil
+	}
+	var exists *redis.BoolCmd
+	_, err := r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		exists = p.PExpire(ctx, sessionKey(id), r.cfg.IdleTimeout)
+		p.PExpire(ctx, eventsKey(id), r.cfg.IdleTimeout)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to update session: %w", err)
+	}
+	if !exists.Val() {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *RedisStore) Delete(ctx context.Context, id string) error {
+	n, err := r.client.Del(ctx, sessionKey(id), eventsKey(id)).Result()
+	if err != nil {
+		return fmt.Errorf("unable to delete session: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	if err := r.client.Publish(ctx, channelName(id), closedMessage).Err(); err != nil {
+		return fmt.Errorf("unable to end subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisStore) Publish(ctx context.Context, id string, data []byte) error {
+	keys := []string{sessionKey(id), eventsKey(id), channelName(id)}
+	eventId, err := publishScript.Run(ctx, r.client, keys, data, r.cfg.ReplayBufferSize, r.cfg.IdleTimeout.Milliseconds()).Int64()
+	if err != nil {
+		return fmt.Errorf("unable to publish event: %w", err)
+	}
+	if eventId < 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// parseEvent decodes an event stored or published by publishScript.
+func parseEvent(s string) (Event, error) {
+	idStr, data, ok := strings.Cut(s, " ")
+	if !ok {
+		return Event{}, fmt.Errorf("invalid event: %q", s)
+	}
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		return Event{}, fmt.Errorf("invalid event id: %q", idStr)
+	}
+	return Event{Id: id, Data: []byte(data)}, nil
+}
+
+func (r *RedisStore) Subscribe(ctx context.Context, id string, lastEventId uint64) (*Subscription, error) {
+	// Subscribe to the channel before reading the buffer, so that no event
+	// is missed in between.
+	pubsub := r.client.Subscribe(ctx, channelName(id))
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, fmt.Errorf("unable to subscribe to session: %w", err)
+	}
+
+	var newestId *redis.StringCmd
+	var buffered *redis.StringSliceCmd
+	_, err := r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		newestId = p.HGet(ctx, sessionKey(id), "lastEventId")
+		buffered = p.LRange(ctx, eventsKey(id), 0, -1)
+		return nil
+	})
+	if errors.Is(err, redis.Nil) {
+		_ = pubsub.Close()
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		_ = pubsub.Close()
+		return nil, fmt.Errorf("unable to retrieve session: %w", err)
+	}
+	newest, err := strconv.ParseUint(newestId.Val(), 10, 64)
+	if err != nil {
+		_ = pubsub.Close()
+		return nil, fmt.Errorf("invalid last event id: %q", newestId.Val())
+	}
+	buffer := make([]Event, 0, len(buffered.Val()))
+	for _, s := range buffered.Val() {
+		e, err := parseEvent(s)
+		if err != nil {
+			_ = pubsub.Close()
+			return nil, err
+		}
+		buffer = append(buffer, e)
+	}
+	pending, missed := eventsAfter(buffer, lastEventId, newest)
+
+	// end the previous subscription to the session
+	subscriptionId := uuid.New().String()
+	if err := r.client.Publish(ctx, channelName(id), attachPrefix+subscriptionId).Err(); err != nil {
+		_ = pubsub.Close()
+		return nil, fmt.Errorf("unable to subscribe to session: %w", err)
+	}
+
+	events := make(chan Event)
+	sub := &Subscription{Events: events, Missed: missed}
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		// Sessions do not expire while they have a subscription.
+		var touch <-chan time.Time
+		if r.cfg.IdleTimeout > 0 {
+			ticker := time.NewTicker(r.cfg.IdleTimeout / 2)
+			defer ticker.Stop()
+			touch = ticker.C
+		}
+
+		messages := pubsub.Channel()
+		for {
+			var out chan<- Event
+			var next Event
+			if len(pending) > 0 {
+				out, next = events, pending[0]
+			}
+			select {
+			case out <- next:
+				lastEventId = next.Id
+				pending = pending[1:]
+			case msg, ok := <-messages:
+				if !ok {
+					sub.err = ErrSessionClosed
+					return
+				}
+				switch {
+				case msg.Payload == closedMessage:
+					sub.err = ErrSessionClosed
+					return
+				case strings.HasPrefix(msg.Payload, attachPrefix):
+					if strings.TrimPrefix(msg.Payload, attachPrefix) != subscriptionId {
+						sub.err = ErrStreamReplaced
+						return
+					}
+				default:
+					e, err := parseEvent(msg.Payload)
+					if err != nil {
+						continue
+					}
+					// skip events already read from the buffer
+					if len(pending) > 0 && e.Id <= pending[len(pending)-1].Id || e.Id <= lastEventId {
+						continue
+					}
+					pending = append(pending, e)
+					// like the replay buffer, only the most recent events
+					// are kept for a client that does not read them
+					if len(pending) > r.cfg.ReplayBufferSize {
+						pending = slices.Delete(pending, 0, len(pending)-r.cfg.ReplayBufferSize)
+					}
+				}
+			case <-touch:
+				if err := r.Touch(ctx, id); errors.Is(err, ErrNotFound) {
+					sub.err = ErrSessionClosed
+					return
+				}
+			case <-ctx.Done():
+				sub.err = ctx.Err()
+				return
+			case <-r.ctx.Done():
+				sub.err = ErrSessionClosed
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// Close ends the subscriptions made through the store and closes its client.
+// Sessions are kept in Redis.
+func (r *RedisStore) Close() error {
+	r.cancel()
+	return r.client.Close()
+}
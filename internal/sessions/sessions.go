@@ -0,0 +1,199 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sessions stores MCP sessions and the events sent on their streams.
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrNotFound is returned when a session does not exist or has expired.
+	ErrNotFound = errors.New("session does not exist")
+	// ErrSessionClosed ends a subscription when its session is deleted or
+	// expires.
+	ErrSessionClosed = errors.New("session terminated")
+	// ErrStreamReplaced ends a subscription when another subscription to the
+	// same session is made.
+	ErrStreamReplaced = errors.New("stream replaced by a new connection")
+)
+
+// DefaultReplayBufferSize is the number of events kept by each session if
+// none is configured.
+const DefaultReplayBufferSize = 100
+
+// Session is an MCP session.
+type Session struct {
+	Id string
+	// ToolsetName is the toolset the session is bound to.
+	ToolsetName string
+	// Claims maps the name of each auth service verified when the session
+	// was established to the claims retrieved from it. Use ValidClaims to
+	// read the claims whose token has not expired.
+	Claims map[string]map[string]any
+	// ProtocolVersion is the protocol version negotiated during
+	// initialization.
+	ProtocolVersion string
+}
+
+// ValidClaims returns the claims of the session whose token has not expired
+// at now, according to their "exp" claim. Claims without an expiry are not
+// returned, since their token cannot be trusted after it was verified.
+func (s Session) ValidClaims(now time.Time) map[string]map[string]any {
+	valid := make(map[string]map[string]any)
+	for name, claims := range s.Claims {
+		exp, ok := claimExpiry(claims)
+		if ok && now.Before(exp) {
+			valid[name] = claims
+		}
+	}
+	return valid
+}
+
+// claimExpiry returns the time of the "exp" claim, in seconds since the epoch.
+func claimExpiry(claims map[string]any) (time.Time, bool) {
+	var secs float64
+	switch v := claims["exp"].(type) {
+	case float64:
+		secs = v
+	case int64:
+		secs = float64(v)
+	case int:
+		secs = float64(v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		secs = f
+	default:
+		return time.Time{}, false
+	}
+	return time.Unix(int64(secs), 0), true
+}
+
+// Event is a message sent on the stream of a session. Ids are assigned in
+// increasing order, starting at 1.
+type Event struct {
+	Id   uint64
+	Data []byte
+}
+
+// Config configures the limits of a Store.
+type Config struct {
+	// ReplayBufferSize is the number of events each session keeps for
+	// clients resuming its stream.
+	ReplayBufferSize int
+	// IdleTimeout is the duration after which sessions without a stream
+	// expire. Sessions never expire if it is 0.
+	IdleTimeout time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.ReplayBufferSize <= 0 {
+		c.ReplayBufferSize = DefaultReplayBufferSize
+	}
+	return c
+}
+
+// Store stores sessions and delivers the events published to them. Stores
+// shared between several servers let any of them publish events to a stream
+// held by another one.
+type Store interface {
+	// Create stores a new session.
+	Create(ctx context.Context, s Session) error
+	// Get returns the session with the given id, or ErrNotFound.
+	Get(ctx context.Context, id string) (Session, error)
+	// SetProtocolVersion sets the protocol version of a session.
+	SetProtocolVersion(ctx context.Context, id, version string) error
+	// Touch records that a session is in use, postponing its expiry.
+	Touch(ctx context.Context, id string) error
+	// Delete deletes a session and ends its subscription.
+	Delete(ctx context.Context, id string) error
+	// Publish adds an event to the stream of a session. Once the replay
+	// buffer of the session is full, its oldest event is discarded.
+	Publish(ctx context.Context, id string, data []byte) error
+	// Subscribe attaches a stream to a session, ending any other
+	// subscription to it. The subscription receives the buffered events
+	// following lastEventId, then new events as they are published, until
+	// ctx is done.
+	Subscribe(ctx context.Context, id string, lastEventId uint64) (*Subscription, error)
+	// Close ends the subscriptions made through the store and releases its
+	// resources.
+	Close() error
+}
+
+// Subscription is a stream attached to a session.
+type Subscription struct {
+	// Events receives the events of the session, in order. It is closed
+	// when the subscription ends.
+	Events <-chan Event
+	// Missed is true if some of the events following the requested
+	// lastEventId were no longer buffered.
+	Missed bool
+
+	err error
+}
+
+// Err returns the reason the subscription ended. It must only be called once
+// Events is closed.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// NewStore returns the store identified by storeURL. It is either "memory",
+// for sessions local to this server, or the URL of a Redis server such as
+// "redis://localhost:6379/0".
+func NewStore(ctx context.Context, storeURL string, cfg Config) (Store, error) {
+	switch {
+	case storeURL == "" || storeURL == "memory":
+		return NewMemoryStore(cfg), nil
+	case strings.HasPrefix(storeURL, "redis://") || strings.HasPrefix(storeURL, "rediss://"):
+		opts, err := redis.ParseURL(storeURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis url: %w", err)
+		}
+		client := redis.NewClient(opts)
+		if err := client.Ping(ctx).Err(); err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("unable to connect to redis: %w", err)
+		}
+		return NewRedisStore(client, cfg), nil
+	default:
+		return nil, fmt.Errorf("unsupported session store %q: must be \"memory\" or a redis:// url", storeURL)
+	}
+}
+
+// eventsAfter returns the events of a buffer following lastEventId. missed is
+// true if some of them are no longer buffered.
+func eventsAfter(buffer []Event, lastEventId, newestId uint64) (events []Event, missed bool) {
+	for i, e := range buffer {
+		if e.Id > lastEventId {
+			events = append(events, buffer[i:]...)
+			break
+		}
+	}
+	if newestId > lastEventId {
+		missed = len(events) == 0 || events[0].Id > lastEventId+1
+	}
+	return events, missed
+}
@@ -0,0 +1,290 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessions_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/sessions"
+	"github.com/redis/go-redis/v9"
+)
+
+func newRedisStore(t *testing.T, mr *miniredis.Miniredis, cfg sessions.Config) *sessions.RedisStore {
+	store := sessions.NewRedisStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}), cfg)
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+// receive returns the next event of a subscription.
+func receive(t *testing.T, sub *sessions.Subscription) sessions.Event {
+	t.Helper()
+	select {
+	case e, ok := <-sub.Events:
+		if !ok {
+			t.Fatalf("subscription ended: %s", sub.Err())
+		}
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for event")
+	}
+	return sessions.Event{}
+}
+
+// ended returns the reason a subscription ended.
+func ended(t *testing.T, sub *sessions.Subscription) error {
+	t.Helper()
+	select {
+	case e, ok := <-sub.Events:
+		if ok {
+			t.Fatalf("unexpected event %d: %s", e.Id, e.Data)
+		}
+		return sub.Err()
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for the subscription to end")
+	}
+	return nil
+}
+
+func TestStores(t *testing.T) {
+	cfg := sessions.Config{ReplayBufferSize: 3, IdleTimeout: time.Minute}
+	tcs := []struct {
+		name  string
+		store func(t *testing.T) sessions.Store
+	}{
+		{
+			name:  "memory",
+			store: func(t *testing.T) sessions.Store { return sessions.NewMemoryStore(cfg) },
+		},
+		{
+			name:  "redis",
+			store: func(t *testing.T) sessions.Store { return newRedisStore(t, miniredis.RunT(t), cfg) },
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := tc.store(t)
+
+			want := sessions.Session{
+				Id:              "session",
+				ToolsetName:     "my-toolset",
+				Claims:          map[string]map[string]any{"my-google-auth": {"email": "foo@bar.com"}},
+				ProtocolVersion: "2025-03-26",
+			}
+			if err := store.Create(ctx, want); err != nil {
+				t.Fatalf("unable to create session: %s", err)
+			}
+			if err := store.SetProtocolVersion(ctx, want.Id, "2025-06-18"); err != nil {
+				t.Fatalf("unable to set protocol version: %s", err)
+			}
+			want.ProtocolVersion = "2025-06-18"
+			got, err := store.Get(ctx, want.Id)
+			if err != nil {
+				t.Fatalf("unable to get session: %s", err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("incorrect session: diff %v", diff)
+			}
+			if _, err := store.Get(ctx, "unknown"); !errors.Is(err, sessions.ErrNotFound) {
+				t.Fatalf("unexpected error for unknown session: %v", err)
+			}
+
+			// only the last 3 events are buffered
+			for i := 1; i <= 5; i++ {
+				if err := store.Publish(ctx, want.Id, []byte(fmt.Sprint(i))); err != nil {
+					t.Fatalf("unable to publish event: %s", err)
+				}
+			}
+			first, err := store.Subscribe(ctx, want.Id, 1)
+			if err != nil {
+				t.Fatalf("unable to subscribe: %s", err)
+			}
+			if !first.Missed {
+				t.Fatalf("expected evicted events to be missed")
+			}
+			for _, wantId := range []uint64{3, 4, 5} {
+				if e := receive(t, first); e.Id != wantId || string(e.Data) != fmt.Sprint(wantId) {
+					t.Fatalf("unexpected event: got %d (%s), want %d", e.Id, e.Data, wantId)
+				}
+			}
+			if err := store.Publish(ctx, want.Id, []byte("6")); err != nil {
+				t.Fatalf("unable to publish event: %s", err)
+			}
+			if e := receive(t, first); e.Id != 6 {
+				t.Fatalf("unexpected event: got %d, want 6", e.Id)
+			}
+
+			// a new subscription replaces the previous one
+			second, err := store.Subscribe(ctx, want.Id, 4)
+			if err != nil {
+				t.Fatalf("unable to subscribe: %s", err)
+			}
+			if second.Missed {
+				t.Fatalf("unexpected missed events")
+			}
+			if err := ended(t, first); !errors.Is(err, sessions.ErrStreamReplaced) {
+				t.Fatalf("unexpected end of replaced subscription: %v", err)
+			}
+			for _, wantId := range []uint64{5, 6} {
+				if e := receive(t, second); e.Id != wantId {
+					t.Fatalf("unexpected event: got %d, want %d", e.Id, wantId)
+				}
+			}
+
+			if err := store.Delete(ctx, want.Id); err != nil {
+				t.Fatalf("unable to delete session: %s", err)
+			}
+			if err := ended(t, second); !errors.Is(err, sessions.ErrSessionClosed) {
+				t.Fatalf("unexpected end of subscription to deleted session: %v", err)
+			}
+			if err := store.Publish(ctx, want.Id, []byte("7")); !errors.Is(err, sessions.ErrNotFound) {
+				t.Fatalf("unexpected error publishing to deleted session: %v", err)
+			}
+			if _, err := store.Subscribe(ctx, want.Id, 0); !errors.Is(err, sessions.ErrNotFound) {
+				t.Fatalf("unexpected error subscribing to deleted session: %v", err)
+			}
+		})
+	}
+}
+
+func TestRedisStoreSharedSessions(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	replica1 := newRedisStore(t, mr, sessions.Config{})
+	replica2 := newRedisStore(t, mr, sessions.Config{})
+
+	if err := replica1.Create(ctx, sessions.Session{Id: "session"}); err != nil {
+		t.Fatalf("unable to create session: %s", err)
+	}
+	sub, err := replica1.Subscribe(ctx, "session", 0)
+	if err != nil {
+		t.Fatalf("unable to subscribe: %s", err)
+	}
+	if _, err := replica2.Get(ctx, "session"); err != nil {
+		t.Fatalf("session is not shared: %s", err)
+	}
+	if err := replica2.Publish(ctx, "session", []byte(`{"jsonrpc":"2.0"}`)); err != nil {
+		t.Fatalf("unable to publish event: %s", err)
+	}
+	if e := receive(t, sub); e.Id != 1 || string(e.Data) != `{"jsonrpc":"2.0"}` {
+		t.Fatalf("unexpected event: %d (%s)", e.Id, e.Data)
+	}
+	if err := replica2.Delete(ctx, "session"); err != nil {
+		t.Fatalf("unable to delete session: %s", err)
+	}
+	if err := ended(t, sub); !errors.Is(err, sessions.ErrSessionClosed) {
+		t.Fatalf("unexpected end of subscription to deleted session: %v", err)
+	}
+}
+
+func TestRedisStoreSlowSubscriber(t *testing.T) {
+	ctx := context.Background()
+	store := newRedisStore(t, miniredis.RunT(t), sessions.Config{ReplayBufferSize: 3})
+	if err := store.Create(ctx, sessions.Session{Id: "session"}); err != nil {
+		t.Fatalf("unable to create session: %s", err)
+	}
+	sub, err := store.Subscribe(ctx, "session", 0)
+	if err != nil {
+		t.Fatalf("unable to subscribe: %s", err)
+	}
+	for i := 1; i <= 10; i++ {
+		if err := store.Publish(ctx, "session", []byte(fmt.Sprint(i))); err != nil {
+			t.Fatalf("unable to publish event: %s", err)
+		}
+	}
+	// let the subscription receive the events before reading them
+	time.Sleep(100 * time.Millisecond)
+
+	// only the last 3 events are kept for the subscriber
+	for _, wantId := range []uint64{8, 9, 10} {
+		if e := receive(t, sub); e.Id != wantId {
+			t.Fatalf("unexpected event: got %d, want %d", e.Id, wantId)
+		}
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	ctx := context.Background()
+	cfg := sessions.Config{IdleTimeout: time.Minute}
+
+	t.Run("memory", func(t *testing.T) {
+		store := sessions.NewMemoryStore(cfg)
+		defer store.Close()
+		for _, id := range []string{"streaming", "idle"} {
+			if err := store.Create(ctx, sessions.Session{Id: id}); err != nil {
+				t.Fatalf("unable to create session: %s", err)
+			}
+		}
+		subCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		if _, err := store.Subscribe(subCtx, "streaming", 0); err != nil {
+			t.Fatalf("unable to subscribe: %s", err)
+		}
+
+		if removed := store.RemoveIdle(time.Now()); len(removed) != 0 {
+			t.Fatalf("unexpected expired sessions: %v", removed)
+		}
+		removed := store.RemoveIdle(time.Now().Add(2 * time.Minute))
+		if diff := cmp.Diff([]string{"idle"}, removed); diff != "" {
+			t.Fatalf("incorrect expired sessions: diff %v", diff)
+		}
+		if _, err := store.Get(ctx, "idle"); !errors.Is(err, sessions.ErrNotFound) {
+			t.Fatalf("expired session was not removed: %v", err)
+		}
+	})
+	t.Run("redis", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		store := newRedisStore(t, mr, cfg)
+		if err := store.Create(ctx, sessions.Session{Id: "idle"}); err != nil {
+			t.Fatalf("unable to create session: %s", err)
+		}
+		mr.FastForward(30 * time.Second)
+		if err := store.Touch(ctx, "idle"); err != nil {
+			t.Fatalf("unable to touch session: %s", err)
+		}
+		mr.FastForward(45 * time.Second)
+		if _, err := store.Get(ctx, "idle"); err != nil {
+			t.Fatalf("session used recently has expired: %s", err)
+		}
+		mr.FastForward(time.Minute)
+		if _, err := store.Get(ctx, "idle"); !errors.Is(err, sessions.ErrNotFound) {
+			t.Fatalf("idle session has not expired: %v", err)
+		}
+	})
+}
+
+func TestValidClaims(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := sessions.Session{Claims: map[string]map[string]any{
+		"valid":      {"sub": "alice", "exp": float64(2000)},
+		"expired":    {"sub": "bob", "exp": float64(500)},
+		"no-expiry":  {"sub": "carol"},
+		"json-valid": {"sub": "dave", "exp": json.Number("2000")},
+	}}
+	want := map[string]map[string]any{
+		"valid":      {"sub": "alice", "exp": float64(2000)},
+		"json-valid": {"sub": "dave", "exp": json.Number("2000")},
+	}
+	if diff := cmp.Diff(want, s.ValidClaims(now)); diff != "" {
+		t.Fatalf("incorrect claims: diff %v", diff)
+	}
+}
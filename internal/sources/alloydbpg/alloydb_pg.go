@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"cloud.google.com/go/alloydbconn"
+	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -29,6 +30,18 @@ import (
 
 const SourceKind string = "alloydb-postgres"
 
+func init() {
+	sources.MustRegister(SourceKind, newConfig)
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name, IPType: "public"}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
 // validate interface
 var _ sources.SourceConfig = Config{}
 
@@ -69,6 +82,8 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.Closer = &Source{}
+var _ sources.SchemaSource = &Source{}
 
 type Source struct {
 	Name string `yaml:"name"`
@@ -80,10 +95,21 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close releases the connections of the source.
+func (s *Source) Close() error {
+	s.Pool.Close()
+	return nil
+}
+
 func (s *Source) PostgresPool() *pgxpool.Pool {
 	return s.Pool
 }
 
+// Schema returns the tables and columns of the database.
+func (s *Source) Schema(ctx context.Context) (any, error) {
+	return sources.PostgresSchema(ctx, s.Pool)
+}
+
 func getOpts(ipType, userAgent string, useIAM bool) ([]alloydbconn.Option, error) {
 	opts := []alloydbconn.Option{alloydbconn.WithUserAgent(userAgent)}
 	switch strings.ToLower(ipType) {
@@ -21,6 +21,7 @@ import (
 	"slices"
 
 	"cloud.google.com/go/cloudsqlconn/sqlserver/mssql"
+	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/trace"
@@ -28,6 +29,18 @@ import (
 
 const SourceKind string = "cloud-sql-mssql"
 
+func init() {
+	sources.MustRegister(SourceKind, newConfig)
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name, IPType: "public"}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
 // validate interface
 var _ sources.SourceConfig = Config{}
 
@@ -72,6 +85,8 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.Closer = &Source{}
+var _ sources.SchemaSource = &Source{}
 
 type Source struct {
 	// Cloud SQL MSSQL struct with connection pool
@@ -85,11 +100,21 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close releases the connections of the source.
+func (s *Source) Close() error {
+	return s.Db.Close()
+}
+
 func (s *Source) MSSQLDB() *sql.DB {
 	// Returns a Cloud SQL MSSQL database connection pool
 	return s.Db
 }
 
+// Schema returns the tables and columns of the database.
+func (s *Source) Schema(ctx context.Context) (any, error) {
+	return sources.SQLSchema(ctx, s.Db, sources.MSSQLSchemaQuery)
+}
+
 func initCloudSQLMssqlConnection(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipAddress, ipType, user, pass, dbname string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -21,6 +21,7 @@ import (
 	"slices"
 
 	"cloud.google.com/go/cloudsqlconn/mysql/mysql"
+	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/trace"
@@ -28,6 +29,18 @@ import (
 
 const SourceKind string = "cloud-sql-mysql"
 
+func init() {
+	sources.MustRegister(SourceKind, newConfig)
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name, IPType: "public"}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
 // validate interface
 var _ sources.SourceConfig = Config{}
 
@@ -67,6 +80,8 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.Closer = &Source{}
+var _ sources.SchemaSource = &Source{}
 
 type Source struct {
 	Name string `yaml:"name"`
@@ -78,10 +93,20 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close releases the connections of the source.
+func (s *Source) Close() error {
+	return s.Pool.Close()
+}
+
 func (s *Source) MySQLPool() *sql.DB {
 	return s.Pool
 }
 
+// Schema returns the tables and columns of the database.
+func (s *Source) Schema(ctx context.Context) (any, error) {
+	return sources.SQLSchema(ctx, s.Pool, sources.MySQLSchemaQuery)
+}
+
 func initCloudSQLMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipType, user, pass, dbname string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
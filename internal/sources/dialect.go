@@ -18,6 +18,8 @@ import (
 	"context"
 	"fmt"
 	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/jsonschema"
 )
 
 // Dialect represents the dialect type of a database.
@@ -43,3 +45,8 @@ func (i *Dialect) UnmarshalYAML(ctx context.Context, unmarshal func(interface{})
 		return fmt.Errorf(`dialect invalid: must be one of "googlesql", or "postgresql"`)
 	}
 }
+
+// JSONSchema describes the dialects accepted in tools files.
+func (Dialect) JSONSchema(*jsonschema.Reflector) map[string]any {
+	return map[string]any{"type": "string", "enum": []string{"googlesql", "postgresql"}}
+}
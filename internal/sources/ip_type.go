@@ -18,6 +18,8 @@ import (
 	"context"
 	"fmt"
 	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/jsonschema"
 )
 
 type IPType string
@@ -42,3 +44,8 @@ func (i *IPType) UnmarshalYAML(ctx context.Context, unmarshal func(interface{})
 		return fmt.Errorf(`ipType invalid: must be one of "public", or "private"`)
 	}
 }
+
+// JSONSchema describes the IP types accepted in tools files.
+func (IPType) JSONSchema(*jsonschema.Reflector) map[string]any {
+	return map[string]any{"type": "string", "enum": []string{"public", "private"}}
+}
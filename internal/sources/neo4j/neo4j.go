@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 
+	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.opentelemetry.io/otel/trace"
@@ -25,6 +26,18 @@ import (
 
 const SourceKind string = "neo4j"
 
+func init() {
+	sources.MustRegister(SourceKind, newConfig)
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
 // validate interface
 var _ sources.SourceConfig = Config{}
 
@@ -65,6 +78,8 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.Closer = &Source{}
+var _ sources.SchemaSource = &Source{}
 
 type Source struct {
 	Name     string `yaml:"name"`
@@ -77,6 +92,11 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close releases the connections of the source.
+func (s *Source) Close() error {
+	return s.Driver.Close(context.Background())
+}
+
 func (s *Source) Neo4jDriver() neo4j.DriverWithContext {
 	return s.Driver
 }
@@ -85,6 +105,30 @@ func (s *Source) Neo4jDatabase() string {
 	return s.Database
 }
 
+// Schema returns the node labels, relationship types and property keys of the
+// database.
+func (s *Source) Schema(ctx context.Context) (any, error) {
+	schema := make(map[string][]string)
+	for key, query := range map[string]string{
+		"labels":            "CALL db.labels()",
+		"relationshipTypes": "CALL db.relationshipTypes()",
+		"propertyKeys":      "CALL db.propertyKeys()",
+	} {
+		result, err := neo4j.ExecuteQuery(ctx, s.Driver, query, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(s.Database), neo4j.ExecuteQueryWithReadersRouting())
+		if err != nil {
+			return nil, fmt.Errorf("unable to execute query %q: %w", query, err)
+		}
+		values := make([]string, 0, len(result.Records))
+		for _, record := range result.Records {
+			if len(record.Values) > 0 {
+				values = append(values, fmt.Sprint(record.Values[0]))
+			}
+		}
+		schema[key] = values
+	}
+	return schema, nil
+}
+
 func initNeo4jDriver(ctx context.Context, tracer trace.Tracer, uri, user, password, name string) (neo4j.DriverWithContext, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SchemaSource is implemented by sources that are able to describe the shape
+// of their data. The schema is published to MCP clients as a read-only resource.
+type SchemaSource interface {
+	Source
+	// Schema returns a JSON serializable description of the source's schema.
+	Schema(context.Context) (any, error)
+}
+
+// Table describes a single table and its columns.
+type Table struct {
+	Schema  string   `json:"schema,omitempty"`
+	Name    string   `json:"name"`
+	Columns []Column `json:"columns"`
+}
+
+// Column describes a single column of a table.
+type Column struct {
+	Name     string `json:"name"`
+	DataType string `json:"dataType"`
+	Nullable bool   `json:"nullable"`
+}
+
+// PostgresSchemaQuery lists the columns of every user table in a Postgres database.
+const PostgresSchemaQuery = `SELECT table_schema, table_name, column_name, data_type, is_nullable
+FROM information_schema.columns
+WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
+ORDER BY table_schema, table_name, ordinal_position`
+
+// MySQLSchemaQuery lists the columns of every table in the current MySQL database.
+const MySQLSchemaQuery = `SELECT table_schema, table_name, column_name, data_type, is_nullable
+FROM information_schema.columns
+WHERE table_schema = DATABASE()
+ORDER BY table_name, ordinal_position`
+
+// MSSQLSchemaQuery lists the columns of every table in the current SQL Server database.
+const MSSQLSchemaQuery = `SELECT TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME, DATA_TYPE, IS_NULLABLE
+FROM INFORMATION_SCHEMA.COLUMNS
+ORDER BY TABLE_SCHEMA, TABLE_NAME, ORDINAL_POSITION`
+
+// SQLiteSchemaQuery lists the columns of every user table in a SQLite database.
+const SQLiteSchemaQuery = `SELECT '', m.name, p.name, p.type, CASE WHEN p."notnull" = 0 THEN 'YES' ELSE 'NO' END
+FROM sqlite_master AS m JOIN pragma_table_info(m.name) AS p
+WHERE m.type = 'table' AND m.name NOT LIKE 'sqlite_%'
+ORDER BY m.name, p.cid`
+
+// PostgresSchema returns the tables of the database the pool is connected to.
+func PostgresSchema(ctx context.Context, pool *pgxpool.Pool) ([]Table, error) {
+	rows, err := pool.Query(ctx, PostgresSchemaQuery)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query schema: %w", err)
+	}
+	defer rows.Close()
+
+	tables := []Table{}
+	for rows.Next() {
+		var schema, table, column, dataType, nullable string
+		if err := rows.Scan(&schema, &table, &column, &dataType, &nullable); err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		tables = appendColumn(tables, schema, table, Column{Name: column, DataType: dataType, Nullable: nullable == "YES"})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("unable to query schema: %w", err)
+	}
+	return tables, nil
+}
+
+// SQLSchema returns the tables of the database the pool is connected to. The
+// query must return the schema, table, column, data type and nullability
+// ("YES" or "NO") of each column, ordered by table.
+func SQLSchema(ctx context.Context, pool *sql.DB, query string) ([]Table, error) {
+	rows, err := pool.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query schema: %w", err)
+	}
+	defer rows.Close()
+
+	tables := []Table{}
+	for rows.Next() {
+		var schema, table, column, dataType, nullable string
+		if err := rows.Scan(&schema, &table, &column, &dataType, &nullable); err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		tables = appendColumn(tables, schema, table, Column{Name: column, DataType: dataType, Nullable: nullable == "YES"})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("unable to query schema: %w", err)
+	}
+	return tables, nil
+}
+
+// appendColumn adds a column to the last table if it matches, or starts a new
+// table otherwise.
+func appendColumn(tables []Table, schema, table string, column Column) []Table {
+	if n := len(tables); n > 0 && tables[n-1].Schema == schema && tables[n-1].Name == table {
+		tables[n-1].Columns = append(tables[n-1].Columns, column)
+		return tables
+	}
+	return append(tables, Table{Schema: schema, Name: table, Columns: []Column{column}})
+}
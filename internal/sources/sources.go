@@ -17,6 +17,8 @@ package sources
 import (
 	"context"
 
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/registry"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -32,6 +34,43 @@ type Source interface {
 	SourceKind() string
 }
 
+// Closer is implemented by sources holding connections that must be released
+// once the source is no longer used, such as when it is removed from the
+// configuration.
+type Closer interface {
+	Source
+	Close() error
+}
+
+// SourceConfigFactory decodes the config of the source named name from
+// decoder. It is registered for a kind of source with Register.
+type SourceConfigFactory = registry.Factory[SourceConfig]
+
+var kinds = registry.New[SourceConfig]("data source")
+
+// Register makes a kind of source available in tools files. It is usually
+// called from the init function of the package implementing the source. It
+// returns false if the kind is already registered.
+func Register(kind string, factory SourceConfigFactory) bool {
+	return kinds.Register(kind, factory)
+}
+
+// MustRegister is like Register, but panics if the kind is already registered.
+func MustRegister(kind string, factory SourceConfigFactory) {
+	kinds.MustRegister(kind, factory)
+}
+
+// DecodeConfig decodes the config of the source named name using the factory
+// registered for kind.
+func DecodeConfig(ctx context.Context, kind, name string, decoder *yaml.Decoder) (SourceConfig, error) {
+	return kinds.DecodeConfig(ctx, kind, name, decoder)
+}
+
+// Kinds returns the registered kinds of sources, sorted.
+func Kinds() []string {
+	return kinds.Kinds()
+}
+
 // InitConnectionSpan adds a span for database pool connection initialization
 func InitConnectionSpan(ctx context.Context, tracer trace.Tracer, sourceKind, sourceName string) (context.Context, trace.Span) {
 	ctx, span := tracer.Start(
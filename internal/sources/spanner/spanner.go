@@ -19,13 +19,29 @@ import (
 	"fmt"
 
 	"cloud.google.com/go/spanner"
+	database "cloud.google.com/go/spanner/admin/database/apiv1"
+	"cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
+	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/option"
 )
 
 const SourceKind string = "spanner"
 
+func init() {
+	sources.MustRegister(SourceKind, newConfig)
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name, Dialect: "googlesql"}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
 // validate interface
 var _ sources.SourceConfig = Config{}
 
@@ -47,29 +63,45 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	if err != nil {
 		return nil, fmt.Errorf("unable to create client: %w", err)
 	}
+	adminClient, err := initAdminClient(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("unable to create database admin client: %w", err)
+	}
 
 	s := &Source{
-		Name:    r.Name,
-		Kind:    SourceKind,
-		Client:  client,
-		Dialect: r.Dialect.String(),
+		Name:        r.Name,
+		Kind:        SourceKind,
+		Client:      client,
+		AdminClient: adminClient,
+		Dialect:     r.Dialect.String(),
 	}
 	return s, nil
 }
 
 var _ sources.Source = &Source{}
+var _ sources.Closer = &Source{}
+var _ sources.SchemaSource = &Source{}
 
 type Source struct {
-	Name    string `yaml:"name"`
-	Kind    string `yaml:"kind"`
-	Client  *spanner.Client
-	Dialect string
+	Name   string `yaml:"name"`
+	Kind   string `yaml:"kind"`
+	Client *spanner.Client
+	// AdminClient reads the schema of the database.
+	AdminClient *database.DatabaseAdminClient
+	Dialect     string
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close releases the connections of the source.
+func (s *Source) Close() error {
+	s.Client.Close()
+	return s.AdminClient.Close()
+}
+
 func (s *Source) SpannerClient() *spanner.Client {
 	return s.Client
 }
@@ -78,6 +110,26 @@ func (s *Source) DatabaseDialect() string {
 	return s.Dialect
 }
 
+// Schema returns the DDL statements of the database.
+func (s *Source) Schema(ctx context.Context) (any, error) {
+	resp, err := s.AdminClient.GetDatabaseDdl(ctx, &databasepb.GetDatabaseDdlRequest{Database: s.Client.DatabaseName()})
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve database ddl: %w", err)
+	}
+	return map[string]any{
+		"dialect": s.Dialect,
+		"ddl":     resp.GetStatements(),
+	}, nil
+}
+
+func initAdminClient(ctx context.Context) (*database.DatabaseAdminClient, error) {
+	userAgent, err := util.UserAgentFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return database.NewDatabaseAdminClient(ctx, option.WithUserAgent(userAgent))
+}
+
 func initSpannerClient(ctx context.Context, tracer trace.Tracer, name, project, instance, dbname string) (*spanner.Client, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -18,7 +18,9 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
 	"github.com/googleapis/genai-toolbox/internal/tools"
@@ -27,6 +29,18 @@ import (
 
 const ToolKind string = "alloydb-ai-nl"
 
+func init() {
+	tools.MustRegister(ToolKind, newConfig)
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
 type compatibleSource interface {
 	PostgresPool() *pgxpool.Pool
 }
@@ -37,22 +51,45 @@ var _ compatibleSource = &alloydbpg.Source{}
 var compatibleSources = [...]string{alloydbpg.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	NLConfig           string           `yaml:"nlConfig" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	NLConfigParameters tools.Parameters `yaml:"nlConfigParameters"`
+	Name               string                 `yaml:"name" validate:"required"`
+	Kind               string                 `yaml:"kind" validate:"required"`
+	Source             string                 `yaml:"source" validate:"required"`
+	Description        string                 `yaml:"description" validate:"required"`
+	NLConfig           string                 `yaml:"nlConfig" validate:"required"`
+	AuthRequired       []string               `yaml:"authRequired"`
+	NLConfigParameters tools.Parameters       `yaml:"nlConfigParameters"`
+	Annotations        *tools.ToolAnnotations `yaml:"annotations"`
+	OutputSchema       map[string]any         `yaml:"outputSchema"`
 }
 
 // validate interface
 var _ tools.ToolConfig = Config{}
+var _ tools.ReferencingConfig = Config{}
+var _ tools.ValidatingConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return ToolKind
 }
 
+func (cfg Config) References() tools.ConfigReferences {
+	return tools.ConfigReferences{
+		Source:                cfg.Source,
+		CompatibleSourceKinds: compatibleSources[:],
+		AuthRequired:          cfg.AuthRequired,
+		Parameters:            cfg.NLConfigParameters,
+	}
+}
+
+// Validate verifies that the tool has no identifier parameters, which its
+// statement cannot substitute,
+// and that its output schema is valid.
+func (cfg Config) Validate() error {
+	if err := tools.CheckOutputSchema(cfg.OutputSchema); err != nil {
+		return err
+	}
+	return tools.CheckNoIdentifiers(cfg.NLConfigParameters)
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -66,6 +103,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", ToolKind, compatibleSources)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	numParams := len(cfg.NLConfigParameters)
 	quotedNameParts := make([]string, 0, numParams)
 	placeholderParts := make([]string, 0, numParams)
@@ -104,10 +145,17 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	cfg.NLConfigParameters = append([]tools.Parameter{newQuestionParam}, cfg.NLConfigParameters...)
 
+	outputSchema, err := tools.NewRowsOutputSchema(cfg.OutputSchema)
+	if err != nil {
+		return nil, err
+	}
+
 	mcpManifest := tools.McpManifest{
-		Name:        cfg.Name,
-		Description: cfg.Description,
-		InputSchema: cfg.NLConfigParameters.McpManifest(),
+		Name:         cfg.Name,
+		Description:  cfg.Description,
+		InputSchema:  cfg.NLConfigParameters.McpManifest(),
+		Annotations:  tools.MergeAnnotations(tools.DatabaseAnnotations(), cfg.Annotations),
+		OutputSchema: outputSchema,
 	}
 
 	t := Tool{
@@ -142,11 +190,21 @@ type Tool struct {
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	// parameters are passed as strings to the natural language query
+	params = params.FormatTimes(t.Parameters, map[string]string{
+		"date":      time.DateOnly,
+		"time":      tools.TimeOfDayLayout,
+		"timestamp": time.RFC3339Nano,
+	})
 	sliceParams := params.AsSlice()
 	allParamValues := make([]any, len(sliceParams)+1)
 	allParamValues[0] = fmt.Sprintf("%s", sliceParams[0]) // nl_question
 	allParamValues[1] = t.NLConfig                        // nl_config
 	for i, param := range sliceParams[1:] {
+		if param == nil {
+			// omitted optional parameters are bound as NULL
+			continue
+		}
 		allParamValues[i+2] = fmt.Sprintf("%s", param)
 	}
 
@@ -168,6 +226,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, erro
 			vMap[f.Name] = v[i]
 		}
 		out = append(out, vMap)
+		tools.ReportRowProgress(ctx, len(out), 0)
+	}
+	// the iteration stops early if the query fails or the context is canceled
+	if err := results.Err(); err != nil {
+		return nil, fmt.Errorf("unable to iterate through query results: %w", err)
 	}
 
 	return out, nil
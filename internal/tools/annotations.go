@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+// ToolAnnotations are additional properties describing a Tool to clients.
+//
+// All properties are hints. They are not guaranteed to provide a faithful
+// description of tool behavior, and clients should never make decisions based
+// on them when the server is untrusted.
+type ToolAnnotations struct {
+	// A human-readable title for the tool.
+	Title string `json:"title,omitempty" yaml:"title"`
+	// If true, the tool does not modify its environment.
+	ReadOnlyHint *bool `json:"readOnlyHint,omitempty" yaml:"readOnlyHint"`
+	// If true, the tool may perform destructive updates to its environment.
+	// If false, the tool performs only additive updates. Only meaningful when
+	// readOnlyHint is false.
+	DestructiveHint *bool `json:"destructiveHint,omitempty" yaml:"destructiveHint"`
+	// If true, calling the tool repeatedly with the same arguments will have
+	// no additional effect on its environment. Only meaningful when
+	// readOnlyHint is false.
+	IdempotentHint *bool `json:"idempotentHint,omitempty" yaml:"idempotentHint"`
+	// If true, the tool may interact with an "open world" of external
+	// entities. If false, the tool's domain of interaction is closed.
+	OpenWorldHint *bool `json:"openWorldHint,omitempty" yaml:"openWorldHint"`
+}
+
+// DatabaseAnnotations are the default annotations of tools that run a
+// statement against a single database. Whether the statement modifies the
+// database cannot be inferred from the kind of the tool.
+func DatabaseAnnotations() ToolAnnotations {
+	return ToolAnnotations{OpenWorldHint: boolPtr(false)}
+}
+
+// ReadOnlyDatabaseAnnotations are the default annotations of tools that only
+// read from a single database.
+func ReadOnlyDatabaseAnnotations() ToolAnnotations {
+	return ToolAnnotations{
+		ReadOnlyHint:  boolPtr(true),
+		OpenWorldHint: boolPtr(false),
+	}
+}
+
+// DestructiveDatabaseAnnotations are the default annotations of tools that
+// may modify or delete data of a single database.
+func DestructiveDatabaseAnnotations() ToolAnnotations {
+	return ToolAnnotations{
+		ReadOnlyHint:    boolPtr(false),
+		DestructiveHint: boolPtr(true),
+		IdempotentHint:  boolPtr(false),
+		OpenWorldHint:   boolPtr(false),
+	}
+}
+
+// MergeAnnotations returns the annotations of a tool, using the values set in
+// overrides and falling back to defaults for the others. It returns nil if no
+// annotation is set.
+func MergeAnnotations(defaults ToolAnnotations, overrides *ToolAnnotations) *ToolAnnotations {
+	a := defaults
+	if overrides != nil {
+		if overrides.Title != "" {
+			a.Title = overrides.Title
+		}
+		if overrides.ReadOnlyHint != nil {
+			a.ReadOnlyHint = overrides.ReadOnlyHint
+		}
+		if overrides.DestructiveHint != nil {
+			a.DestructiveHint = overrides.DestructiveHint
+		}
+		if overrides.IdempotentHint != nil {
+			a.IdempotentHint = overrides.IdempotentHint
+		}
+		if overrides.OpenWorldHint != nil {
+			a.OpenWorldHint = overrides.OpenWorldHint
+		}
+	}
+	if a == (ToolAnnotations{}) {
+		return nil
+	}
+	return &a
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
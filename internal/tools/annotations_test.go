@@ -0,0 +1,81 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestMergeAnnotations(t *testing.T) {
+	trueValue, falseValue := true, false
+	tcs := []struct {
+		name      string
+		defaults  tools.ToolAnnotations
+		overrides *tools.ToolAnnotations
+		want      string
+	}{
+		{
+			name:     "no annotations",
+			defaults: tools.ToolAnnotations{},
+			want:     "null",
+		},
+		{
+			name:     "defaults only",
+			defaults: tools.DestructiveDatabaseAnnotations(),
+			want:     `{"readOnlyHint":false,"destructiveHint":true,"idempotentHint":false,"openWorldHint":false}`,
+		},
+		{
+			name:      "overrides",
+			defaults:  tools.DestructiveDatabaseAnnotations(),
+			overrides: &tools.ToolAnnotations{Title: "Run SQL", ReadOnlyHint: &trueValue},
+			want:      `{"title":"Run SQL","readOnlyHint":true,"destructiveHint":true,"idempotentHint":false,"openWorldHint":false}`,
+		},
+		{
+			name:      "overrides without defaults",
+			defaults:  tools.ToolAnnotations{},
+			overrides: &tools.ToolAnnotations{OpenWorldHint: &falseValue},
+			want:      `{"openWorldHint":false}`,
+		},
+		{
+			name:     "http get",
+			defaults: tools.HTTPMethod("GET").Annotations(),
+			want:     `{"readOnlyHint":true,"openWorldHint":true}`,
+		},
+		{
+			name:     "http delete",
+			defaults: tools.HTTPMethod("DELETE").Annotations(),
+			want:     `{"readOnlyHint":false,"destructiveHint":true,"idempotentHint":true,"openWorldHint":true}`,
+		},
+		{
+			name:     "http post",
+			defaults: tools.HTTPMethod("POST").Annotations(),
+			want:     `{"readOnlyHint":false,"openWorldHint":true}`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := json.Marshal(tools.MergeAnnotations(tc.defaults, tc.overrides))
+			if err != nil {
+				t.Fatalf("unable to marshal annotations: %s", err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("unexpected annotations: got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
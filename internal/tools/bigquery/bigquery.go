@@ -18,16 +18,32 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	bigqueryapi "cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
 	"google.golang.org/api/iterator"
 )
 
 const ToolKind string = "bigquery-sql"
 
+func init() {
+	tools.MustRegister(ToolKind, newConfig)
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
 type compatibleSource interface {
 	BigQueryClient() *bigqueryapi.Client
 }
@@ -38,22 +54,44 @@ var _ compatibleSource = &bigqueryds.Source{}
 var compatibleSources = [...]string{bigqueryds.SourceKind}
 
 type Config struct {
-	Name         string           `yaml:"name" validate:"required"`
-	Kind         string           `yaml:"kind" validate:"required"`
-	Source       string           `yaml:"source" validate:"required"`
-	Description  string           `yaml:"description" validate:"required"`
-	Statement    string           `yaml:"statement" validate:"required"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
+	Name         string                 `yaml:"name" validate:"required"`
+	Kind         string                 `yaml:"kind" validate:"required"`
+	Source       string                 `yaml:"source" validate:"required"`
+	Description  string                 `yaml:"description" validate:"required"`
+	Statement    string                 `yaml:"statement" validate:"required"`
+	AuthRequired []string               `yaml:"authRequired"`
+	Parameters   tools.Parameters       `yaml:"parameters"`
+	Annotations  *tools.ToolAnnotations `yaml:"annotations"`
+	OutputSchema map[string]any         `yaml:"outputSchema"`
 }
 
 // validate interface
 var _ tools.ToolConfig = Config{}
+var _ tools.ReferencingConfig = Config{}
+var _ tools.ValidatingConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return ToolKind
 }
 
+func (cfg Config) References() tools.ConfigReferences {
+	return tools.ConfigReferences{
+		Source:                cfg.Source,
+		CompatibleSourceKinds: compatibleSources[:],
+		AuthRequired:          cfg.AuthRequired,
+		Parameters:            cfg.Parameters,
+	}
+}
+
+// Validate verifies that the identifiers of the statement can be substituted,
+// and that its output schema is valid.
+func (cfg Config) Validate() error {
+	if err := tools.CheckOutputSchema(cfg.OutputSchema); err != nil {
+		return err
+	}
+	return tools.CheckStatementTemplate(cfg.Statement, cfg.Parameters)
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -67,10 +105,21 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", ToolKind, compatibleSources)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	outputSchema, err := tools.NewRowsOutputSchema(cfg.OutputSchema)
+	if err != nil {
+		return nil, err
+	}
+
 	mcpManifest := tools.McpManifest{
-		Name:        cfg.Name,
-		Description: cfg.Description,
-		InputSchema: cfg.Parameters.McpManifest(),
+		Name:         cfg.Name,
+		Description:  cfg.Description,
+		InputSchema:  cfg.Parameters.McpManifest(),
+		Annotations:  tools.MergeAnnotations(tools.DatabaseAnnotations(), cfg.Annotations),
+		OutputSchema: outputSchema,
 	}
 
 	// finish tool setup
@@ -102,27 +151,227 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
+// nullValue returns the NULL value of parameter p. BigQuery does not accept
+// untyped NULL parameters.
+func nullValue(p tools.Parameter) (any, error) {
+	switch p.GetType() {
+	case "boolean":
+		return bigqueryapi.NullBool{}, nil
+	case "string":
+		return bigqueryapi.NullString{}, nil
+	case "integer":
+		return bigqueryapi.NullInt64{}, nil
+	case "float":
+		return bigqueryapi.NullFloat64{}, nil
+	case "date":
+		return bigqueryapi.NullDate{}, nil
+	case "time":
+		return bigqueryapi.NullTime{}, nil
+	case "timestamp":
+		return bigqueryapi.NullTimestamp{}, nil
+	case "object", "duration":
+		t, err := dataType(p)
+		if err != nil {
+			return nil, err
+		}
+		return &bigqueryapi.QueryParameterValue{Type: t, Value: bigqueryapi.NullString{}}, nil
+	}
+	return nil, fmt.Errorf("parameter %q of type %q cannot be NULL in BigQuery, set a default value", p.GetName(), p.GetType())
+}
+
+// dataType returns the BigQuery type of the values of parameter p.
+func dataType(p tools.Parameter) (bigqueryapi.StandardSQLDataType, error) {
+	switch p := p.(type) {
+	case *tools.ArrayParameter:
+		elem, err := dataType(p.Items)
+		if err != nil {
+			return bigqueryapi.StandardSQLDataType{}, err
+		}
+		return bigqueryapi.StandardSQLDataType{TypeKind: "ARRAY", ArrayElementType: &elem}, nil
+	case *tools.ObjectParameter:
+		fields := make([]*bigqueryapi.StandardSQLField, 0, len(p.Properties))
+		for _, prop := range p.Properties {
+			t, err := dataType(prop)
+			if err != nil {
+				return bigqueryapi.StandardSQLDataType{}, err
+			}
+			fields = append(fields, &bigqueryapi.StandardSQLField{Name: prop.GetName(), Type: &t})
+		}
+		return bigqueryapi.StandardSQLDataType{
+			TypeKind:   "STRUCT",
+			StructType: &bigqueryapi.StandardSQLStructType{Fields: fields},
+		}, nil
+	}
+	switch p.GetType() {
+	case "boolean":
+		return bigqueryapi.StandardSQLDataType{TypeKind: "BOOL"}, nil
+	case "string":
+		return bigqueryapi.StandardSQLDataType{TypeKind: "STRING"}, nil
+	case "integer":
+		return bigqueryapi.StandardSQLDataType{TypeKind: "INT64"}, nil
+	case "float":
+		return bigqueryapi.StandardSQLDataType{TypeKind: "FLOAT64"}, nil
+	case "date":
+		return bigqueryapi.StandardSQLDataType{TypeKind: "DATE"}, nil
+	case "time":
+		return bigqueryapi.StandardSQLDataType{TypeKind: "TIME"}, nil
+	case "timestamp":
+		return bigqueryapi.StandardSQLDataType{TypeKind: "TIMESTAMP"}, nil
+	case "duration":
+		return bigqueryapi.StandardSQLDataType{TypeKind: "INTERVAL"}, nil
+	}
+	return bigqueryapi.StandardSQLDataType{}, fmt.Errorf("parameter %q of type %q is not supported by BigQuery", p.GetName(), p.GetType())
+}
+
+// structValue returns the STRUCT value of object parameter p. The types of
+// its fields are set on the value of the query parameter.
+func structValue(p *tools.ObjectParameter, m map[string]any) (bigqueryapi.QueryParameterValue, error) {
+	fields := make(map[string]bigqueryapi.QueryParameterValue, len(p.Properties))
+	for _, prop := range p.Properties {
+		v, err := fieldValue(prop, m[prop.GetName()])
+		if err != nil {
+			return bigqueryapi.QueryParameterValue{}, err
+		}
+		fields[prop.GetName()] = v
+	}
+	return bigqueryapi.QueryParameterValue{StructValue: fields}, nil
+}
+
+// fieldValue returns the value v of parameter p, as a field of a STRUCT value.
+func fieldValue(p tools.Parameter, v any) (bigqueryapi.QueryParameterValue, error) {
+	if v == nil {
+		// any NULL value, typed by the field
+		return bigqueryapi.QueryParameterValue{Value: bigqueryapi.NullString{}}, nil
+	}
+	switch p := p.(type) {
+	case *tools.ObjectParameter:
+		return structValue(p, v.(map[string]any))
+	case *tools.ArrayParameter:
+		elems := v.([]any)
+		if len(elems) == 0 {
+			return bigqueryapi.QueryParameterValue{Value: elems}, nil
+		}
+		values := make([]bigqueryapi.QueryParameterValue, 0, len(elems))
+		for _, e := range elems {
+			ev, err := fieldValue(p.Items, e)
+			if err != nil {
+				return bigqueryapi.QueryParameterValue{}, err
+			}
+			values = append(values, ev)
+		}
+		return bigqueryapi.QueryParameterValue{ArrayValue: values}, nil
+	}
+	switch v := temporalValue(p, v).(type) {
+	case civil.Date:
+		return bigqueryapi.QueryParameterValue{Value: v.String()}, nil
+	case civil.Time:
+		return bigqueryapi.QueryParameterValue{Value: bigqueryapi.CivilTimeString(v)}, nil
+	case time.Time:
+		return bigqueryapi.QueryParameterValue{Value: v.Format(time.RFC3339Nano)}, nil
+	case *bigqueryapi.IntervalValue:
+		return bigqueryapi.QueryParameterValue{Value: v.String()}, nil
+	}
+	return bigqueryapi.QueryParameterValue{Value: v}, nil
+}
+
+// temporalValue returns the value v of parameter p as a civil date or time,
+// or as an interval, for the parameters of these types. Timestamps are bound
+// as time.Time values.
+func temporalValue(p tools.Parameter, v any) any {
+	switch v := v.(type) {
+	case time.Time:
+		switch p.GetType() {
+		case "date":
+			return civil.DateOf(v)
+		case "time":
+			return civil.TimeOf(v)
+		}
+	case time.Duration:
+		return bigqueryapi.IntervalValueFromDuration(v)
+	}
+	return v
+}
+
+// queryValue returns the value v of parameter p, as bound to the query.
+func queryValue(p tools.Parameter, v any) (any, error) {
+	if v == nil {
+		return nullValue(p)
+	}
+	o, ok := p.(*tools.ObjectParameter)
+	if !ok {
+		return temporalValue(p, v), nil
+	}
+	// maps are not bound as STRUCT values without explicit types
+	t, err := dataType(o)
+	if err != nil {
+		return nil, err
+	}
+	qv, err := structValue(o, v.(map[string]any))
+	if err != nil {
+		return nil, err
+	}
+	qv.Type = t
+	return &qv, nil
+}
+
+// queryJob is the part of a *bigquery.Job read by tools.
+type queryJob interface {
+	Read(ctx context.Context) (*bigqueryapi.RowIterator, error)
+	Cancel(ctx context.Context) error
+}
+
+// readJob waits for the results of job. If ctx is done first, such as when
+// the client cancels the tool call, the job is cancelled, since it would
+// otherwise keep running on the server.
+func readJob(ctx context.Context, job queryJob) (*bigqueryapi.RowIterator, error) {
+	stop := context.AfterFunc(ctx, func() {
+		if err := job.Cancel(context.WithoutCancel(ctx)); err != nil {
+			if logger, lErr := util.LoggerFromContext(ctx); lErr == nil {
+				logger.WarnContext(ctx, fmt.Sprintf("unable to cancel query job: %s", err))
+			}
+		}
+	})
+	defer stop()
+	return job.Read(ctx)
+}
+
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	namedArgs := make([]bigqueryapi.QueryParameter, 0, len(params))
-	paramsMap := params.AsReversedMap()
-	for _, v := range params.AsSlice() {
-		paramName := paramsMap[v]
-		if strings.Contains(t.Statement, "@"+paramName) {
+	values := make(tools.ParamValues, 0, len(params))
+	// params holds the value of each parameter of t, in order
+	for i, p := range params {
+		v, err := queryValue(t.Parameters[i], p.Value)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, tools.ParamValue{Name: p.Name, Value: v})
+	}
+	statement, values, err := tools.ResolveIdentifiers(t.Statement, t.Parameters, values, tools.QuoteGoogleSQL)
+	if err != nil {
+		return nil, err
+	}
+
+	namedArgs := make([]bigqueryapi.QueryParameter, 0, len(values))
+	for _, p := range values {
+		if strings.Contains(statement, "@"+p.Name) {
 			namedArgs = append(namedArgs, bigqueryapi.QueryParameter{
-				Name:  paramName,
-				Value: v,
+				Name:  p.Name,
+				Value: p.Value,
 			})
 		} else {
 			namedArgs = append(namedArgs, bigqueryapi.QueryParameter{
-				Value: v,
+				Value: p.Value,
 			})
 		}
 	}
 
-	query := t.Client.Query(t.Statement)
+	query := t.Client.Query(statement)
 	query.Parameters = namedArgs
 
-	it, err := query.Read(ctx)
+	job, err := query.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	it, err := readJob(ctx, job)
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
@@ -142,6 +391,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, erro
 			vMap[key] = value
 		}
 		out = append(out, vMap)
+		tools.ReportRowProgress(ctx, len(out), int(it.TotalRows))
 	}
 
 	return out, nil
@@ -0,0 +1,60 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+)
+
+// fakeJob is a query job running until its context is done.
+type fakeJob struct {
+	cancelled chan struct{}
+}
+
+func (j *fakeJob) Read(ctx context.Context) (*bigqueryapi.RowIterator, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (j *fakeJob) Cancel(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	close(j.cancelled)
+	return nil
+}
+
+func TestReadJobCancel(t *testing.T) {
+	job := &fakeJob{cancelled: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	if _, err := readJob(ctx, job); !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-job.cancelled:
+	case <-time.After(time.Second):
+		t.Fatalf("job was not cancelled")
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	"cloud.google.com/go/bigtable"
+	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	bigtabledb "github.com/googleapis/genai-toolbox/internal/sources/bigtable"
 	"github.com/googleapis/genai-toolbox/internal/tools"
@@ -26,6 +27,18 @@ import (
 
 const ToolKind string = "bigtable-sql"
 
+func init() {
+	tools.MustRegister(ToolKind, newConfig)
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
 type compatibleSource interface {
 	BigtableClient() *bigtable.Client
 }
@@ -36,22 +49,45 @@ var _ compatibleSource = &bigtabledb.Source{}
 var compatibleSources = [...]string{bigtabledb.SourceKind}
 
 type Config struct {
-	Name         string           `yaml:"name" validate:"required"`
-	Kind         string           `yaml:"kind" validate:"required"`
-	Source       string           `yaml:"source" validate:"required"`
-	Description  string           `yaml:"description" validate:"required"`
-	Statement    string           `yaml:"statement" validate:"required"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
+	Name         string                 `yaml:"name" validate:"required"`
+	Kind         string                 `yaml:"kind" validate:"required"`
+	Source       string                 `yaml:"source" validate:"required"`
+	Description  string                 `yaml:"description" validate:"required"`
+	Statement    string                 `yaml:"statement" validate:"required"`
+	AuthRequired []string               `yaml:"authRequired"`
+	Parameters   tools.Parameters       `yaml:"parameters"`
+	Annotations  *tools.ToolAnnotations `yaml:"annotations"`
+	OutputSchema map[string]any         `yaml:"outputSchema"`
 }
 
 // validate interface
 var _ tools.ToolConfig = Config{}
+var _ tools.ReferencingConfig = Config{}
+var _ tools.ValidatingConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return ToolKind
 }
 
+func (cfg Config) References() tools.ConfigReferences {
+	return tools.ConfigReferences{
+		Source:                cfg.Source,
+		CompatibleSourceKinds: compatibleSources[:],
+		AuthRequired:          cfg.AuthRequired,
+		Parameters:            cfg.Parameters,
+	}
+}
+
+// Validate verifies that the tool has no identifier parameters, which its
+// statement cannot substitute,
+// and that its output schema is valid.
+func (cfg Config) Validate() error {
+	if err := tools.CheckOutputSchema(cfg.OutputSchema); err != nil {
+		return err
+	}
+	return tools.CheckNoIdentifiers(cfg.Parameters)
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -65,10 +101,21 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", ToolKind, compatibleSources)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	outputSchema, err := tools.NewRowsOutputSchema(cfg.OutputSchema)
+	if err != nil {
+		return nil, err
+	}
+
 	mcpManifest := tools.McpManifest{
-		Name:        cfg.Name,
-		Description: cfg.Description,
-		InputSchema: cfg.Parameters.McpManifest(),
+		Name:         cfg.Name,
+		Description:  cfg.Description,
+		InputSchema:  cfg.Parameters.McpManifest(),
+		Annotations:  tools.MergeAnnotations(tools.ReadOnlyDatabaseAnnotations(), cfg.Annotations),
+		OutputSchema: outputSchema,
 	}
 
 	// finish tool setup
@@ -119,6 +166,8 @@ func getMapParamsType(tparams tools.Parameters, params tools.ParamValues) (map[s
 			btParams[p.Name] = bigtable.Float64SQLType{}
 		case "array":
 			btParams[p.Name] = bigtable.ArraySQLType{}
+		case "date", "time", "timestamp", "duration":
+			btParams[p.Name] = bigtable.StringSQLType{}
 		}
 	}
 
@@ -140,7 +189,8 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, erro
 		return nil, fmt.Errorf("unable to prepare statement: %w", err)
 	}
 
-	bs, err := ps.Bind(params.AsMap())
+	// times are bound in the format of their parameter
+	bs, err := ps.Bind(params.TimesAsText(t.Parameters).AsMap())
 	if err != nil {
 		return nil, fmt.Errorf("unable to bind: %w", err)
 	}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/couchbase/gocb/v2"
+	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/couchbase"
 	"github.com/googleapis/genai-toolbox/internal/tools"
@@ -27,6 +28,18 @@ import (
 
 const ToolKind string = "couchbase-sql"
 
+func init() {
+	tools.MustRegister(ToolKind, newConfig)
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
 type compatibleSource interface {
 	CouchbaseScope() *gocb.Scope
 	CouchbaseQueryScanConsistency() uint
@@ -38,22 +51,45 @@ var _ compatibleSource = &couchbase.Source{}
 var compatibleSources = [...]string{couchbase.SourceKind}
 
 type Config struct {
-	Name         string           `yaml:"name" validate:"required"`
-	Kind         string           `yaml:"kind" validate:"required"`
-	Source       string           `yaml:"source" validate:"required"`
-	Description  string           `yaml:"description" validate:"required"`
-	Statement    string           `yaml:"statement" validate:"required"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
+	Name         string                 `yaml:"name" validate:"required"`
+	Kind         string                 `yaml:"kind" validate:"required"`
+	Source       string                 `yaml:"source" validate:"required"`
+	Description  string                 `yaml:"description" validate:"required"`
+	Statement    string                 `yaml:"statement" validate:"required"`
+	AuthRequired []string               `yaml:"authRequired"`
+	Parameters   tools.Parameters       `yaml:"parameters"`
+	Annotations  *tools.ToolAnnotations `yaml:"annotations"`
+	OutputSchema map[string]any         `yaml:"outputSchema"`
 }
 
 // validate interface
 var _ tools.ToolConfig = Config{}
+var _ tools.ReferencingConfig = Config{}
+var _ tools.ValidatingConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return ToolKind
 }
 
+func (cfg Config) References() tools.ConfigReferences {
+	return tools.ConfigReferences{
+		Source:                cfg.Source,
+		CompatibleSourceKinds: compatibleSources[:],
+		AuthRequired:          cfg.AuthRequired,
+		Parameters:            cfg.Parameters,
+	}
+}
+
+// Validate verifies that the tool has no identifier parameters, which its
+// statement cannot substitute,
+// and that its output schema is valid.
+func (cfg Config) Validate() error {
+	if err := tools.CheckOutputSchema(cfg.OutputSchema); err != nil {
+		return err
+	}
+	return tools.CheckNoIdentifiers(cfg.Parameters)
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -67,10 +103,21 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", ToolKind, compatibleSources)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	outputSchema, err := tools.NewRowsOutputSchema(cfg.OutputSchema)
+	if err != nil {
+		return nil, err
+	}
+
 	mcpManifest := tools.McpManifest{
-		Name:        cfg.Name,
-		Description: cfg.Description,
-		InputSchema: cfg.Parameters.McpManifest(),
+		Name:         cfg.Name,
+		Description:  cfg.Description,
+		InputSchema:  cfg.Parameters.McpManifest(),
+		Annotations:  tools.MergeAnnotations(tools.DatabaseAnnotations(), cfg.Annotations),
+		OutputSchema: outputSchema,
 	}
 	// finish tool setup
 	t := Tool{
@@ -104,7 +151,8 @@ type Tool struct {
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	namedParams := params.AsMap()
+	// times are sent in the format of their parameter
+	namedParams := params.TimesAsText(t.Parameters).AsMap()
 	results, err := t.Scope.Query(t.Statement, &gocb.QueryOptions{
 		ScanConsistency: gocb.QueryScanConsistency(t.QueryScanConsistency),
 		NamedParameters: namedParams,
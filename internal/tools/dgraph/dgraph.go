@@ -18,7 +18,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 
+	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/dgraph"
 	"github.com/googleapis/genai-toolbox/internal/tools"
@@ -26,6 +28,18 @@ import (
 
 const ToolKind string = "dgraph-dql"
 
+func init() {
+	tools.MustRegister(ToolKind, newConfig)
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
 type compatibleSource interface {
 	DgraphClient() *dgraph.DgraphClient
 }
@@ -36,24 +50,47 @@ var _ compatibleSource = &dgraph.Source{}
 var compatibleSources = [...]string{dgraph.SourceKind}
 
 type Config struct {
-	Name         string           `yaml:"name" validate:"required"`
-	Kind         string           `yaml:"kind" validate:"required"`
-	Source       string           `yaml:"source" validate:"required"`
-	Description  string           `yaml:"description" validate:"required"`
-	Statement    string           `yaml:"statement" validate:"required"`
-	AuthRequired []string         `yaml:"authRequired"`
-	IsQuery      bool             `yaml:"isQuery"`
-	Timeout      string           `yaml:"timeout"`
-	Parameters   tools.Parameters `yaml:"parameters"`
+	Name         string                 `yaml:"name" validate:"required"`
+	Kind         string                 `yaml:"kind" validate:"required"`
+	Source       string                 `yaml:"source" validate:"required"`
+	Description  string                 `yaml:"description" validate:"required"`
+	Statement    string                 `yaml:"statement" validate:"required"`
+	AuthRequired []string               `yaml:"authRequired"`
+	IsQuery      bool                   `yaml:"isQuery"`
+	Timeout      string                 `yaml:"timeout"`
+	Parameters   tools.Parameters       `yaml:"parameters"`
+	Annotations  *tools.ToolAnnotations `yaml:"annotations"`
+	OutputSchema map[string]any         `yaml:"outputSchema"`
 }
 
 // validate interface
 var _ tools.ToolConfig = Config{}
+var _ tools.ReferencingConfig = Config{}
+var _ tools.ValidatingConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return ToolKind
 }
 
+func (cfg Config) References() tools.ConfigReferences {
+	return tools.ConfigReferences{
+		Source:                cfg.Source,
+		CompatibleSourceKinds: compatibleSources[:],
+		AuthRequired:          cfg.AuthRequired,
+		Parameters:            cfg.Parameters,
+	}
+}
+
+// Validate verifies that the tool has no identifier parameters, which its
+// statement cannot substitute,
+// and that its output schema is valid.
+func (cfg Config) Validate() error {
+	if err := tools.CheckOutputSchema(cfg.OutputSchema); err != nil {
+		return err
+	}
+	return tools.CheckNoIdentifiers(cfg.Parameters)
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -67,10 +104,25 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", ToolKind, compatibleSources)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	annotations := tools.DestructiveDatabaseAnnotations()
+	if cfg.IsQuery {
+		annotations = tools.ReadOnlyDatabaseAnnotations()
+	}
+	outputSchema, err := tools.NewRowsOutputSchema(cfg.OutputSchema)
+	if err != nil {
+		return nil, err
+	}
+
 	mcpManifest := tools.McpManifest{
-		Name:        cfg.Name,
-		Description: cfg.Description,
-		InputSchema: cfg.Parameters.McpManifest(),
+		Name:         cfg.Name,
+		Description:  cfg.Description,
+		InputSchema:  cfg.Parameters.McpManifest(),
+		Annotations:  tools.MergeAnnotations(annotations, cfg.Annotations),
+		OutputSchema: outputSchema,
 	}
 
 	// finish tool setup
@@ -106,7 +158,10 @@ type Tool struct {
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	paramsMap := params.AsMapWithDollarPrefix()
+	// times are sent in the format of their parameter
+	paramsMap := params.TimesAsText(t.Parameters).AsMapWithDollarPrefix()
+	// omitted optional variables take the default value of the query
+	maps.DeleteFunc(paramsMap, func(_ string, v any) bool { return v == nil })
 
 	resp, err := t.DgraphClient.ExecuteQuery(t.Statement, paramsMap, t.IsQuery, t.Timeout)
 	if err != nil {
@@ -27,6 +27,7 @@ import (
 	"maps"
 	"text/template"
 
+	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	httpsrc "github.com/googleapis/genai-toolbox/internal/sources/http"
 	"github.com/googleapis/genai-toolbox/internal/tools"
@@ -34,28 +35,70 @@ import (
 
 const ToolKind string = "http"
 
+func init() {
+	tools.MustRegister(ToolKind, newConfig)
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
 type Config struct {
-	Name         string            `yaml:"name" validate:"required"`
-	Kind         string            `yaml:"kind" validate:"required"`
-	Source       string            `yaml:"source" validate:"required"`
-	Description  string            `yaml:"description" validate:"required"`
-	AuthRequired []string          `yaml:"authRequired"`
-	Path         string            `yaml:"path" validate:"required"`
-	Method       tools.HTTPMethod  `yaml:"method" validate:"required"`
-	Headers      map[string]string `yaml:"headers"`
-	RequestBody  string            `yaml:"requestBody"`
-	QueryParams  tools.Parameters  `yaml:"queryParams"`
-	BodyParams   tools.Parameters  `yaml:"bodyParams"`
-	HeaderParams tools.Parameters  `yaml:"headerParams"`
+	Name         string                 `yaml:"name" validate:"required"`
+	Kind         string                 `yaml:"kind" validate:"required"`
+	Source       string                 `yaml:"source" validate:"required"`
+	Description  string                 `yaml:"description" validate:"required"`
+	AuthRequired []string               `yaml:"authRequired"`
+	Path         string                 `yaml:"path" validate:"required"`
+	Method       tools.HTTPMethod       `yaml:"method" validate:"required"`
+	Headers      map[string]string      `yaml:"headers"`
+	RequestBody  string                 `yaml:"requestBody"`
+	QueryParams  tools.Parameters       `yaml:"queryParams"`
+	BodyParams   tools.Parameters       `yaml:"bodyParams"`
+	HeaderParams tools.Parameters       `yaml:"headerParams"`
+	Annotations  *tools.ToolAnnotations `yaml:"annotations"`
+	OutputSchema map[string]any         `yaml:"outputSchema"`
 }
 
 // validate interface
 var _ tools.ToolConfig = Config{}
+var _ tools.ReferencingConfig = Config{}
+var _ tools.ValidatingConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return ToolKind
 }
 
+func (cfg Config) References() tools.ConfigReferences {
+	return tools.ConfigReferences{
+		Source:                cfg.Source,
+		CompatibleSourceKinds: []string{httpsrc.SourceKind},
+		AuthRequired:          cfg.AuthRequired,
+		Parameters:            slices.Concat(cfg.QueryParams, cfg.BodyParams, cfg.HeaderParams),
+	}
+}
+
+// Validate verifies there are no duplicate parameter names,
+// and that its output schema is valid.
+func (cfg Config) Validate() error {
+	if err := tools.CheckOutputSchema(cfg.OutputSchema); err != nil {
+		return err
+	}
+	seenNames := make(map[string]bool)
+	for _, param := range slices.Concat(cfg.QueryParams, cfg.BodyParams, cfg.HeaderParams) {
+		if _, exists := seenNames[param.GetName()]; exists {
+			return fmt.Errorf("parameter name must be unique across queryParams, bodyParams, and headerParams. Duplicate parameter: %s", param.GetName())
+		}
+		seenNames[param.GetName()] = true
+	}
+	// values are sent as is, so identifiers cannot be substituted
+	return tools.CheckNoIdentifiers(slices.Concat(cfg.QueryParams, cfg.BodyParams, cfg.HeaderParams))
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -69,6 +112,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be `http`", ToolKind)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Create URL based on BaseURL and Path
 	// Attach query parameters
 	u, err := url.Parse(s.BaseURL + cfg.Path)
@@ -132,19 +179,17 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		Required:   concatRequiredManifest,
 	}
 
-	// Verify there are no duplicate parameter names
-	seenNames := make(map[string]bool)
-	for _, param := range paramManifest {
-		if _, exists := seenNames[param.Name]; exists {
-			return nil, fmt.Errorf("parameter name must be unique across queryParams, bodyParams, and headerParams. Duplicate parameter: %s", param.Name)
-		}
-		seenNames[param.Name] = true
+	outputSchema, err := tools.NewMcpOutputSchema(cfg.OutputSchema)
+	if err != nil {
+		return nil, err
 	}
 
 	mcpManifest := tools.McpManifest{
-		Name:        cfg.Name,
-		Description: cfg.Description,
-		InputSchema: paramMcpManifest,
+		Name:         cfg.Name,
+		Description:  cfg.Description,
+		InputSchema:  paramMcpManifest,
+		Annotations:  tools.MergeAnnotations(cfg.Method.Annotations(), cfg.Annotations),
+		OutputSchema: outputSchema,
 	}
 
 	// finish tool setup
@@ -232,7 +277,12 @@ func getURL(u *url.URL, queryParams tools.Parameters, paramsMap map[string]any)
 	// Set dynamic query parameters
 	query := u.Query()
 	for _, p := range queryParams {
-		query.Add(p.GetName(), fmt.Sprintf("%v", paramsMap[p.GetName()]))
+		v := paramsMap[p.GetName()]
+		if v == nil {
+			// omitted optional parameters are not sent
+			continue
+		}
+		query.Add(p.GetName(), fmt.Sprintf("%v", v))
 	}
 	u.RawQuery = query.Encode()
 	return u.String(), nil
@@ -245,7 +295,7 @@ func getHeaders(headerParams tools.Parameters, defaultHeaders map[string]string,
 	maps.Copy(allHeaders, defaultHeaders)
 	for _, p := range headerParams {
 		headerValue, ok := paramsMap[p.GetName()]
-		if ok {
+		if ok && headerValue != nil {
 			if strValue, ok := headerValue.(string); ok {
 				allHeaders[p.GetName()] = strValue
 			} else {
@@ -257,7 +307,8 @@ func getHeaders(headerParams tools.Parameters, defaultHeaders map[string]string,
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	paramsMap := params.AsMap()
+	// times are sent in the format of their parameter
+	paramsMap := params.TimesAsText(t.AllParams).AsMap()
 
 	// Calculate request body
 	requestBody, err := getRequestBody(t.BodyParams, t.RequestBody, paramsMap)
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/jsonschema"
 )
 
 // HTTPMethod is a string of a valid HTTP method (e.g "GET")
@@ -35,6 +37,15 @@ func isValidHTTPMethod(method string) bool {
 	return false
 }
 
+// JSONSchema describes the HTTP methods accepted in tools files.
+func (HTTPMethod) JSONSchema(*jsonschema.Reflector) map[string]any {
+	return map[string]any{"type": "string", "enum": []string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete,
+		http.MethodPatch, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodConnect,
+	}}
+}
+
 func (i *HTTPMethod) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
 	var httpMethod string
 	if err := unmarshal(&httpMethod); err != nil {
@@ -47,3 +58,24 @@ func (i *HTTPMethod) UnmarshalYAML(ctx context.Context, unmarshal func(interface
 	*i = HTTPMethod(httpMethod)
 	return nil
 }
+
+// Annotations returns the annotations implied by the HTTP method of a tool.
+// Every HTTP tool interacts with an external service.
+func (i HTTPMethod) Annotations() ToolAnnotations {
+	a := ToolAnnotations{OpenWorldHint: boolPtr(true)}
+	switch string(i) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		a.ReadOnlyHint = boolPtr(true)
+	case http.MethodPut:
+		a.ReadOnlyHint = boolPtr(false)
+		a.DestructiveHint = boolPtr(true)
+		a.IdempotentHint = boolPtr(true)
+	case http.MethodDelete:
+		a.ReadOnlyHint = boolPtr(false)
+		a.DestructiveHint = boolPtr(true)
+		a.IdempotentHint = boolPtr(true)
+	default:
+		a.ReadOnlyHint = boolPtr(false)
+	}
+	return a
+}
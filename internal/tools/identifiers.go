@@ -0,0 +1,134 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// QuoteFunc quotes an identifier in the SQL dialect of a source, so that it
+// is read as a single name whatever characters it contains.
+type QuoteFunc func(name string) string
+
+// QuoteDoubleQuotes quotes identifiers of PostgreSQL and SQLite, such as
+// "my table".
+func QuoteDoubleQuotes(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QuoteBackticks quotes identifiers of MySQL, such as `my table`.
+func QuoteBackticks(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// QuoteGoogleSQL quotes identifiers of BigQuery and Spanner, such as
+// `my table`.
+func QuoteGoogleSQL(name string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(name)
+	return "`" + escaped + "`"
+}
+
+// QuoteBrackets quotes identifiers of SQL Server, such as [my table].
+func QuoteBrackets(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+// hasIdentifiers reports whether any of ps is an IdentifierParameter.
+func hasIdentifiers(ps Parameters) bool {
+	for _, p := range ps {
+		if _, ok := p.(*IdentifierParameter); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckNoIdentifiers returns an error if any of ps is an IdentifierParameter,
+// for tools whose statements do not substitute identifiers.
+func CheckNoIdentifiers(ps Parameters) error {
+	for _, p := range ps {
+		if _, ok := p.(*IdentifierParameter); ok {
+			return fmt.Errorf("identifier parameter %q is not supported by this tool", p.GetName())
+		}
+	}
+	return nil
+}
+
+// parseStatementTemplate parses a statement referring to identifier
+// parameters as a text/template, such as "SELECT * FROM {{.table}}".
+func parseStatementTemplate(statement string) (*template.Template, error) {
+	tmpl, err := template.New("statement").Option("missingkey=error").Parse(statement)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse statement template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// CheckStatementTemplate returns an error if the statement of a tool with
+// identifier parameters in ps is not a valid template. Statements without
+// identifier parameters are not templates.
+func CheckStatementTemplate(statement string, ps Parameters) error {
+	if !hasIdentifiers(ps) {
+		return nil
+	}
+	_, err := parseStatementTemplate(statement)
+	return err
+}
+
+// ResolveIdentifiers substitutes the values of the identifier parameters of ps
+// into the statement template, quoted with quote. It returns the statement and
+// the values of the other parameters, which are bound to it. Only identifier
+// parameters can be referred to by the template.
+func ResolveIdentifiers(statement string, ps Parameters, params ParamValues, quote QuoteFunc) (string, ParamValues, error) {
+	if !hasIdentifiers(ps) {
+		return statement, params, nil
+	}
+	identifiers := make(map[string]*IdentifierParameter)
+	for _, p := range ps {
+		if ip, ok := p.(*IdentifierParameter); ok {
+			identifiers[p.GetName()] = ip
+		}
+	}
+
+	data := make(map[string]any)
+	values := make(ParamValues, 0, len(params))
+	for _, p := range params {
+		ip, ok := identifiers[p.Name]
+		if !ok {
+			values = append(values, p)
+			continue
+		}
+		name, ok := p.Value.(string)
+		if !ok {
+			return "", nil, fmt.Errorf("identifier %q has no value", p.Name)
+		}
+		if ip.IsQuoted() {
+			name = quote(name)
+		}
+		data[p.Name] = name
+	}
+
+	tmpl, err := parseStatementTemplate(statement)
+	if err != nil {
+		return "", nil, err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", nil, fmt.Errorf("unable to substitute identifiers in statement: %w", err)
+	}
+	return b.String(), values, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestQuote(t *testing.T) {
+	tcs := []struct {
+		name  string
+		quote tools.QuoteFunc
+		in    string
+		want  string
+	}{
+		{"double quotes", tools.QuoteDoubleQuotes, "users", `"users"`},
+		{"double quotes escaped", tools.QuoteDoubleQuotes, `us"ers`, `"us""ers"`},
+		{"backticks", tools.QuoteBackticks, "users", "`users`"},
+		{"backticks escaped", tools.QuoteBackticks, "us`ers", "`us``ers`"},
+		{"googlesql", tools.QuoteGoogleSQL, "my-project.sales.users", "`my-project.sales.users`"},
+		{"googlesql escaped", tools.QuoteGoogleSQL, "us`er\\s", "`us\\`er\\\\s`"},
+		{"brackets", tools.QuoteBrackets, "users", "[users]"},
+		{"brackets escaped", tools.QuoteBrackets, "us]ers", "[us]]ers]"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.quote(tc.in); got != tc.want {
+				t.Fatalf("incorrect quoting: got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResolveIdentifiers(t *testing.T) {
+	order := tools.NewIdentifierParameter("order", "The sort order.", []string{"ASC", "DESC"})
+	unquoted := false
+	order.Quoted = &unquoted
+	ps := tools.Parameters{
+		tools.NewIdentifierParameter("column", "The column to sort on.", []string{"name", "created at"}),
+		tools.NewIntParameter("id", "The minimum id."),
+		order,
+	}
+	params := tools.ParamValues{
+		{Name: "column", Value: "created at"},
+		{Name: "id", Value: 1},
+		{Name: "order", Value: "DESC"},
+	}
+	statement := "SELECT * FROM users WHERE id > $1 ORDER BY {{.column}} {{.order}}"
+
+	got, values, err := tools.ResolveIdentifiers(statement, ps, params, tools.QuoteDoubleQuotes)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := `SELECT * FROM users WHERE id > $1 ORDER BY "created at" DESC`; got != want {
+		t.Fatalf("incorrect statement: got %s, want %s", got, want)
+	}
+	if diff := cmp.Diff(tools.ParamValues{{Name: "id", Value: 1}}, values); diff != "" {
+		t.Fatalf("incorrect values: diff %v", diff)
+	}
+
+	// values cannot be substituted
+	_, _, err = tools.ResolveIdentifiers("SELECT * FROM {{.id}}", ps, params, tools.QuoteDoubleQuotes)
+	if err == nil || !strings.Contains(err.Error(), `map has no entry for key "id"`) {
+		t.Fatalf("expected error for value parameter, got %v", err)
+	}
+
+	// statements without identifiers are not templates
+	plain := "SELECT '{{.column}}'"
+	got, _, err = tools.ResolveIdentifiers(plain, ps[1:2], params[1:2], tools.QuoteDoubleQuotes)
+	if err != nil || got != plain {
+		t.Fatalf("unexpected statement %q: %v", got, err)
+	}
+}
+
+func TestCheckNoIdentifiers(t *testing.T) {
+	if err := tools.CheckNoIdentifiers(tools.Parameters{tools.NewStringParameter("name", "The name.")}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ps := tools.Parameters{
+		tools.NewStringParameter("name", "The name."),
+		tools.NewIdentifierParameter("table", "The table.", []string{"users"}),
+	}
+	err := tools.CheckNoIdentifiers(ps)
+	if want := `identifier parameter "table" is not supported by this tool`; err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+func TestCheckStatementTemplate(t *testing.T) {
+	ps := tools.Parameters{tools.NewIdentifierParameter("table", "The table.", []string{"users"})}
+	if err := tools.CheckStatementTemplate("SELECT * FROM {{.table}}", ps); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	err := tools.CheckStatementTemplate("SELECT * FROM {{.table}", ps)
+	if err == nil || !strings.HasPrefix(err.Error(), "unable to parse statement template: ") {
+		t.Fatalf("expected template error, got %v", err)
+	}
+	if err := tools.CheckStatementTemplate("SELECT '{{'", nil); err != nil {
+		t.Fatalf("unexpected error for statement without identifiers: %s", err)
+	}
+}
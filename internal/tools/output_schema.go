@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/jsonschema"
+)
+
+// StructuredResultKey is the property of a structured tool result holding the
+// list of values returned by Tool.Invoke.
+const StructuredResultKey = "result"
+
+// McpOutputSchema is the representation of output schema for McpManifest.
+type McpOutputSchema struct {
+	Type       string         `json:"type"`
+	Properties map[string]any `json:"properties"`
+	Required   []string       `json:"required"`
+
+	// items is the compiled schema of a single value returned by Tool.Invoke.
+	items *jsonschema.Schema
+}
+
+// NewMcpOutputSchema returns the output schema of a tool whose results are
+// described by itemSchema, a JSON Schema of a single value returned by
+// Tool.Invoke. A nil itemSchema accepts any value.
+func NewMcpOutputSchema(itemSchema map[string]any) (*McpOutputSchema, error) {
+	if itemSchema == nil {
+		itemSchema = map[string]any{}
+	}
+	items, err := compileOutputSchema(itemSchema)
+	if err != nil {
+		return nil, err
+	}
+	return &McpOutputSchema{
+		Type: "object",
+		Properties: map[string]any{
+			StructuredResultKey: map[string]any{
+				"type":  "array",
+				"items": itemSchema,
+			},
+		},
+		Required: []string{StructuredResultKey},
+		items:    items,
+	}, nil
+}
+
+// NewRowsOutputSchema returns the output schema of a tool returning database
+// rows, each described by rowSchema. A nil rowSchema accepts any object.
+func NewRowsOutputSchema(rowSchema map[string]any) (*McpOutputSchema, error) {
+	if rowSchema == nil {
+		rowSchema = map[string]any{"type": "object"}
+	}
+	return NewMcpOutputSchema(rowSchema)
+}
+
+// CheckOutputSchema verifies that itemSchema, the outputSchema of a tool
+// config, is a JSON Schema supported by NewMcpOutputSchema.
+func CheckOutputSchema(itemSchema map[string]any) error {
+	_, err := compileOutputSchema(itemSchema)
+	return err
+}
+
+func compileOutputSchema(itemSchema map[string]any) (*jsonschema.Schema, error) {
+	items, err := jsonschema.Compile(itemSchema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outputSchema: %w", err)
+	}
+	return items, nil
+}
+
+// Validate verifies that every value of res, as returned by Tool.Invoke,
+// matches the schema of the items of the result.
+func (s *McpOutputSchema) Validate(res []any) error {
+	if s.items == nil {
+		return nil
+	}
+	for i, v := range res {
+		if err := s.items.Validate(v); err != nil {
+			return fmt.Errorf("%s %d does not match the output schema: %w", StructuredResultKey, i, err)
+		}
+	}
+	return nil
+}
+
+// StructuredResult returns the structured content of a tool result, matching
+// the schemas returned by NewMcpOutputSchema.
+func StructuredResult(res []any) map[string]any {
+	if res == nil {
+		res = []any{}
+	}
+	return map[string]any{StructuredResultKey: res}
+}
@@ -15,11 +15,19 @@
 package tools
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
+	"github.com/googleapis/genai-toolbox/internal/jsonschema"
 	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
@@ -29,8 +37,38 @@ const (
 	typeFloat  = "float"
 	typeBool   = "boolean"
 	typeArray  = "array"
+	typeObject = "object"
+
+	typeDate      = "date"
+	typeTime      = "time"
+	typeTimestamp = "timestamp"
+	typeDuration  = "duration"
+
+	typeIdentifier = "identifier"
 )
 
+// patterns caches the compiled patterns of parameters, which are compiled when
+// the parameters are loaded rather than when each value is parsed.
+var patterns sync.Map
+
+// compilePattern returns the compiled regular expression pattern of the
+// parameter named name.
+func compilePattern(name, pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("parameter %q has an invalid pattern: %w", name, err)
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+// TimeOfDayLayout is the layout of the values of "time" parameters bound as
+// strings, for databases without a type for times of day.
+const TimeOfDayLayout = "15:04:05.999999999"
+
 // ParamValues is an ordered list of ParamValue
 type ParamValues []ParamValue
 
@@ -97,6 +135,68 @@ func (p ParamValues) AsMapWithDollarPrefix() map[string]interface{} {
 	return params
 }
 
+// ObjectsAsJSON returns a copy of p where the values of object parameters are
+// encoded as JSON documents, for databases binding JSON as strings.
+func (p ParamValues) ObjectsAsJSON() (ParamValues, error) {
+	params := make(ParamValues, 0, len(p))
+	for _, param := range p {
+		if m, ok := param.Value.(map[string]any); ok {
+			b, err := json.Marshal(m)
+			if err != nil {
+				return nil, fmt.Errorf("unable to encode %q as JSON: %w", param.Name, err)
+			}
+			param.Value = string(b)
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// FormatTimes returns a copy of p where the values of the parameters of ps
+// whose type has a layout in layouts are formatted with it, for databases
+// without native binding of these types.
+func (p ParamValues) FormatTimes(ps Parameters, layouts map[string]string) ParamValues {
+	types := make(map[string]string, len(ps))
+	for _, param := range ps {
+		types[param.GetName()] = param.GetType()
+	}
+	params := make(ParamValues, 0, len(p))
+	for _, param := range p {
+		layout, ok := layouts[types[param.Name]]
+		if t, isTime := param.Value.(time.Time); ok && isTime {
+			param.Value = t.Format(layout)
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// TimesAsText returns a copy of p where the values of the date, time,
+// timestamp and duration parameters of ps are formatted as strings in the
+// format of the parameter, for tools passing values as text or JSON, where a
+// time of day would otherwise carry the date of year 0.
+func (p ParamValues) TimesAsText(ps Parameters) ParamValues {
+	byName := make(map[string]Parameter, len(ps))
+	for _, param := range ps {
+		byName[param.GetName()] = param
+	}
+	params := make(ParamValues, 0, len(p))
+	for _, param := range p {
+		switch v := param.Value.(type) {
+		case time.Time:
+			if tp, ok := byName[param.Name].(*TimeParameter); ok {
+				param.Value = v.Format(tp.layout())
+			}
+		case time.Duration:
+			if dp, ok := byName[param.Name].(*DurationParameter); ok {
+				param.Value = dp.format(v)
+			}
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
 func parseFromAuthService(paramAuthServices []ParamAuthService, claimsMap map[string]map[string]any) (any, error) {
 	// parse a parameter from claims using its specified auth services
 	for _, a := range paramAuthServices {
@@ -126,6 +226,11 @@ func ParseParams(ps Parameters, data map[string]any, claimsMap map[string]map[st
 			// parse non auth-required parameter
 			var ok bool
 			v, ok = data[name]
+			if (!ok || v == nil) && !p.IsRequired() {
+				// omitted optional parameters take their default value, or NULL
+				params = append(params, ParamValue{Name: name, Value: p.GetDefault()})
+				continue
+			}
 			if !ok {
 				return nil, fmt.Errorf("parameter %q is required", name)
 			}
@@ -152,6 +257,8 @@ type Parameter interface {
 	GetName() string
 	GetType() string
 	GetAuthServices() []ParamAuthService
+	IsRequired() bool
+	GetDefault() any
 	Parse(any) (any, error)
 	Manifest() ParameterManifest
 	McpManifest() ParameterMcpManifest
@@ -183,6 +290,51 @@ func (c *Parameters) UnmarshalYAML(ctx context.Context, unmarshal func(interface
 	return nil
 }
 
+// JSONSchema describes a list of parameters in a tools file.
+func (Parameters) JSONSchema(r *jsonschema.Reflector) map[string]any {
+	return map[string]any{"type": "array", "items": r.Define("parameter", parameterSchema)}
+}
+
+// parameterSchema describes a parameter of any type, discriminated by its
+// `type` field.
+func parameterSchema(r *jsonschema.Reflector) map[string]any {
+	prototypes := []struct {
+		typ   string
+		param Parameter
+	}{
+		{typeString, &StringParameter{}},
+		{typeInt, &IntParameter{}},
+		{typeFloat, &FloatParameter{}},
+		{typeBool, &BooleanParameter{}},
+		{typeArray, &ArrayParameter{}},
+		{typeObject, &ObjectParameter{}},
+		{typeDate, &TimeParameter{}},
+		{typeTime, &TimeParameter{}},
+		{typeTimestamp, &TimeParameter{}},
+		{typeDuration, &DurationParameter{}},
+		{typeIdentifier, &IdentifierParameter{}},
+	}
+	types := make([]string, 0, len(prototypes))
+	oneOf := make([]any, 0, len(prototypes))
+	for _, p := range prototypes {
+		s := r.Reflect(p.param)
+		properties := s["properties"].(map[string]any)
+		properties["type"] = map[string]any{"const": p.typ}
+		if p.typ == typeArray {
+			// items are parameters themselves
+			properties["items"] = r.Define("parameter", parameterSchema)
+		}
+		types = append(types, p.typ)
+		oneOf = append(oneOf, s)
+	}
+	return map[string]any{
+		"type":       "object",
+		"required":   []string{"type"},
+		"properties": map[string]any{"type": map[string]any{"enum": types}},
+		"oneOf":      oneOf,
+	}
+}
+
 // parseParamFromDelayedUnmarshaler is a helper function that is required to parse
 // parameters because there are multiple different types
 func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmarshaler) (Parameter, error) {
@@ -211,33 +363,51 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 		if err := dec.DecodeContext(ctx, a); err != nil {
 			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
 		}
+		if err := a.checkConstraints(); err != nil {
+			return nil, err
+		}
 		if a.AuthSources != nil {
 			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
 			a.AuthSources = nil
 		}
+		if err := a.parseDefault(a.Parse); err != nil {
+			return nil, err
+		}
 		return a, nil
 	case typeInt:
 		a := &IntParameter{}
 		if err := dec.DecodeContext(ctx, a); err != nil {
 			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
 		}
+		if err := a.checkConstraints(); err != nil {
+			return nil, err
+		}
 		if a.AuthSources != nil {
 			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
 			a.AuthSources = nil
 		}
+		if err := a.parseDefault(a.Parse); err != nil {
+			return nil, err
+		}
 		return a, nil
 	case typeFloat:
 		a := &FloatParameter{}
 		if err := dec.DecodeContext(ctx, a); err != nil {
 			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
 		}
+		if err := a.checkConstraints(); err != nil {
+			return nil, err
+		}
 		if a.AuthSources != nil {
 			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
 			a.AuthSources = nil
 		}
+		if err := a.parseDefault(a.Parse); err != nil {
+			return nil, err
+		}
 		return a, nil
 	case typeBool:
 		a := &BooleanParameter{}
@@ -249,17 +419,91 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
 			a.AuthSources = nil
 		}
+		if err := a.parseDefault(a.Parse); err != nil {
+			return nil, err
+		}
 		return a, nil
 	case typeArray:
 		a := &ArrayParameter{}
 		if err := dec.DecodeContext(ctx, a); err != nil {
 			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
 		}
+		if err := a.checkConstraints(); err != nil {
+			return nil, err
+		}
+		if a.AuthSources != nil {
+			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
+			a.AuthServices = append(a.AuthServices, a.AuthSources...)
+			a.AuthSources = nil
+		}
+		if err := a.parseDefault(a.Parse); err != nil {
+			return nil, err
+		}
+		return a, nil
+	case typeObject:
+		a := &ObjectParameter{}
+		if err := dec.DecodeContext(ctx, a); err != nil {
+			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
+		}
+		if err := a.checkConstraints(); err != nil {
+			return nil, err
+		}
+		if a.AuthSources != nil {
+			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
+			a.AuthServices = append(a.AuthServices, a.AuthSources...)
+			a.AuthSources = nil
+		}
+		if err := a.parseDefault(a.Parse); err != nil {
+			return nil, err
+		}
+		return a, nil
+	case typeDate, typeTime, typeTimestamp:
+		a := &TimeParameter{}
+		if err := dec.DecodeContext(ctx, a); err != nil {
+			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
+		}
+		if err := a.checkConstraints(); err != nil {
+			return nil, err
+		}
+		if a.AuthSources != nil {
+			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
+			a.AuthServices = append(a.AuthServices, a.AuthSources...)
+			a.AuthSources = nil
+		}
+		if err := a.parseDefault(a.Parse); err != nil {
+			return nil, err
+		}
+		return a, nil
+	case typeDuration:
+		a := &DurationParameter{}
+		if err := dec.DecodeContext(ctx, a); err != nil {
+			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
+		}
+		if a.AuthSources != nil {
+			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
+			a.AuthServices = append(a.AuthServices, a.AuthSources...)
+			a.AuthSources = nil
+		}
+		if err := a.parseDefault(a.Parse); err != nil {
+			return nil, err
+		}
+		return a, nil
+	case typeIdentifier:
+		a := &IdentifierParameter{}
+		if err := dec.DecodeContext(ctx, a); err != nil {
+			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
+		}
+		if err := a.checkConstraints(); err != nil {
+			return nil, err
+		}
 		if a.AuthSources != nil {
 			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
 			a.AuthServices = append(a.AuthServices, a.AuthSources...)
 			a.AuthSources = nil
 		}
+		if err := a.parseDefault(a.Parse); err != nil {
+			return nil, err
+		}
 		return a, nil
 	}
 	return nil, fmt.Errorf("%q is not valid type for a parameter!", t)
@@ -280,8 +524,9 @@ func (ps Parameters) McpManifest() McpToolsSchema {
 	for _, p := range ps {
 		name := p.GetName()
 		properties[name] = p.McpManifest()
-		// all parameters are added to the required field
-		required = append(required, name)
+		if p.IsRequired() {
+			required = append(required, name)
+		}
 	}
 
 	return McpToolsSchema{
@@ -293,18 +538,50 @@ func (ps Parameters) McpManifest() McpToolsSchema {
 
 // ParameterManifest represents parameters when served as part of a ToolManifest.
 type ParameterManifest struct {
-	Name         string             `json:"name"`
-	Type         string             `json:"type"`
-	Description  string             `json:"description"`
-	AuthServices []string           `json:"authSources"`
-	Items        *ParameterManifest `json:"items,omitempty"`
+	Name         string              `json:"name"`
+	Type         string              `json:"type"`
+	Description  string              `json:"description"`
+	AuthServices []string            `json:"authSources"`
+	Required     bool                `json:"required"`
+	Default      any                 `json:"default,omitempty"`
+	Format       string              `json:"format,omitempty"`
+	Items        *ParameterManifest  `json:"items,omitempty"`
+	Properties   []ParameterManifest `json:"properties,omitempty"`
+	ParameterConstraints
 }
 
 // ParameterMcpManifest represents properties when served as part of a ToolMcpManifest.
 type ParameterMcpManifest struct {
-	Type        string                `json:"type"`
-	Description string                `json:"description"`
-	Items       *ParameterMcpManifest `json:"items,omitempty"`
+	Type        string                          `json:"type"`
+	Description string                          `json:"description"`
+	Default     any                             `json:"default,omitempty"`
+	Format      string                          `json:"format,omitempty"`
+	Items       *ParameterMcpManifest           `json:"items,omitempty"`
+	Properties  map[string]ParameterMcpManifest `json:"properties,omitempty"`
+	Required    []string                        `json:"required,omitempty"`
+	ParameterConstraints
+}
+
+// ParameterConstraints are the constraints on the values of a parameter, as
+// published in manifests. Each type of parameter sets some of them.
+type ParameterConstraints struct {
+	Enum      []any    `json:"enum,omitempty"`
+	Minimum   *float64 `json:"minimum,omitempty"`
+	Maximum   *float64 `json:"maximum,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	MinLength *int     `json:"minLength,omitempty"`
+	MaxLength *int     `json:"maxLength,omitempty"`
+	MinItems  *int     `json:"minItems,omitempty"`
+	MaxItems  *int     `json:"maxItems,omitempty"`
+}
+
+// checkBounds returns an error if the lower bound of a constraint is greater
+// than its upper bound.
+func checkBounds[T int | float64](name, lowerName, upperName string, lower, upper *T) error {
+	if lower != nil && upper != nil && *lower > *upper {
+		return fmt.Errorf("parameter %q has a %s greater than its %s", name, lowerName, upperName)
+	}
+	return nil
 }
 
 // CommonParameter are default fields that are emebdding in most Parameter implementations. Embedding this stuct will give the object Name() and Type() functions.
@@ -314,6 +591,8 @@ type CommonParameter struct {
 	Desc         string             `yaml:"description" validate:"required"`
 	AuthServices []ParamAuthService `yaml:"authServices"`
 	AuthSources  []ParamAuthService `yaml:"authSources"` // Deprecated: Kept for compatibility.
+	Required     *bool              `yaml:"required"`
+	Default      any                `yaml:"default"`
 }
 
 // GetName returns the name specified for the Parameter.
@@ -326,6 +605,50 @@ func (p *CommonParameter) GetType() string {
 	return p.Type
 }
 
+// IsRequired returns whether a value must be provided for the Parameter.
+// Parameters are required unless they set `required: false` or a default
+// value.
+func (p *CommonParameter) IsRequired() bool {
+	if p.Required != nil {
+		return *p.Required
+	}
+	return p.Default == nil
+}
+
+// GetDefault returns the value of the Parameter when it is omitted. A nil
+// value is bound as NULL.
+func (p *CommonParameter) GetDefault() any {
+	return p.Default
+}
+
+// parseDefault converts the default value of the Parameter with parse, the
+// same way as values provided by clients.
+func (p *CommonParameter) parseDefault(parse func(any) (any, error)) error {
+	if p.Default == nil {
+		return nil
+	}
+	if p.Required != nil && *p.Required {
+		return fmt.Errorf("parameter %q is required and cannot have a default value", p.Name)
+	}
+	// values provided by clients are decoded from JSON
+	b, err := json.Marshal(p.Default)
+	if err != nil {
+		return fmt.Errorf("invalid default value for parameter %q: %w", p.Name, err)
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return fmt.Errorf("invalid default value for parameter %q: %w", p.Name, err)
+	}
+	v, err = parse(v)
+	if err != nil {
+		return fmt.Errorf("invalid default value for parameter %q: %w", p.Name, err)
+	}
+	p.Default = v
+	return nil
+}
+
 // Manifest returns the manifest for the Parameter.
 func (p *CommonParameter) Manifest() ParameterManifest {
 	// only list ParamAuthService names (without fields) in manifest
@@ -338,6 +661,8 @@ func (p *CommonParameter) Manifest() ParameterManifest {
 		Type:         p.Type,
 		Description:  p.Desc,
 		AuthServices: authNames,
+		Required:     p.IsRequired(),
+		Default:      p.Default,
 	}
 }
 
@@ -346,6 +671,7 @@ func (p *CommonParameter) McpManifest() ParameterMcpManifest {
 	return ParameterMcpManifest{
 		Type:        p.Type,
 		Description: p.Desc,
+		Default:     p.Default,
 	}
 }
 
@@ -394,6 +720,19 @@ var _ Parameter = &StringParameter{}
 // StringParameter is a parameter representing the "string" type.
 type StringParameter struct {
 	CommonParameter `yaml:",inline"`
+	Enum            []string `yaml:"enum"`
+	Pattern         string   `yaml:"pattern"`
+	MinLength       *int     `yaml:"minLength"`
+	MaxLength       *int     `yaml:"maxLength"`
+}
+
+// checkConstraints returns an error if the constraints of the StringParameter
+// are invalid.
+func (p *StringParameter) checkConstraints() error {
+	if _, err := compilePattern(p.Name, p.Pattern); err != nil {
+		return err
+	}
+	return checkBounds(p.Name, "minLength", "maxLength", p.MinLength, p.MaxLength)
 }
 
 // Parse casts the value "v" as a "string".
@@ -402,8 +741,50 @@ func (p *StringParameter) Parse(v any) (any, error) {
 	if !ok {
 		return nil, &ParseTypeError{p.Name, p.Type, v}
 	}
+	if len(p.Enum) > 0 && !slices.Contains(p.Enum, newV) {
+		return nil, fmt.Errorf("%q is not one of %q", newV, p.Enum)
+	}
+	if p.Pattern != "" {
+		// the pattern is compiled when the parameter is loaded
+		re, err := compilePattern(p.Name, p.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		if !re.MatchString(newV) {
+			return nil, fmt.Errorf("%q does not match the pattern %q", newV, p.Pattern)
+		}
+	}
+	length := utf8.RuneCountInString(newV)
+	if p.MinLength != nil && length < *p.MinLength {
+		return nil, fmt.Errorf("%q is shorter than the minimum length of %d", newV, *p.MinLength)
+	}
+	if p.MaxLength != nil && length > *p.MaxLength {
+		return nil, fmt.Errorf("%q is longer than the maximum length of %d", newV, *p.MaxLength)
+	}
 	return newV, nil
 }
+
+// Manifest returns the manifest for the StringParameter.
+func (p *StringParameter) Manifest() ParameterManifest {
+	m := p.CommonParameter.Manifest()
+	m.ParameterConstraints = p.constraints()
+	return m
+}
+
+// McpManifest returns the MCP manifest for the StringParameter.
+func (p *StringParameter) McpManifest() ParameterMcpManifest {
+	m := p.CommonParameter.McpManifest()
+	m.ParameterConstraints = p.constraints()
+	return m
+}
+
+func (p *StringParameter) constraints() ParameterConstraints {
+	c := ParameterConstraints{Pattern: p.Pattern, MinLength: p.MinLength, MaxLength: p.MaxLength}
+	for _, e := range p.Enum {
+		c.Enum = append(c.Enum, e)
+	}
+	return c
+}
 func (p *StringParameter) GetAuthServices() []ParamAuthService {
 	return p.AuthServices
 }
@@ -437,6 +818,15 @@ var _ Parameter = &IntParameter{}
 // IntParameter is a parameter representing the "int" type.
 type IntParameter struct {
 	CommonParameter `yaml:",inline"`
+	Enum            []int `yaml:"enum"`
+	Minimum         *int  `yaml:"minimum"`
+	Maximum         *int  `yaml:"maximum"`
+}
+
+// checkConstraints returns an error if the constraints of the IntParameter are
+// invalid.
+func (p *IntParameter) checkConstraints() error {
+	return checkBounds(p.Name, "minimum", "maximum", p.Minimum, p.Maximum)
 }
 
 func (p *IntParameter) Parse(v any) (any, error) {
@@ -457,6 +847,15 @@ func (p *IntParameter) Parse(v any) (any, error) {
 		}
 		out = int(newI)
 	}
+	if len(p.Enum) > 0 && !slices.Contains(p.Enum, out) {
+		return nil, fmt.Errorf("%d is not one of %v", out, p.Enum)
+	}
+	if p.Minimum != nil && out < *p.Minimum {
+		return nil, fmt.Errorf("%d is less than the minimum of %d", out, *p.Minimum)
+	}
+	if p.Maximum != nil && out > *p.Maximum {
+		return nil, fmt.Errorf("%d is greater than the maximum of %d", out, *p.Maximum)
+	}
 	return out, nil
 }
 
@@ -464,6 +863,36 @@ func (p *IntParameter) GetAuthServices() []ParamAuthService {
 	return p.AuthServices
 }
 
+// Manifest returns the manifest for the IntParameter.
+func (p *IntParameter) Manifest() ParameterManifest {
+	m := p.CommonParameter.Manifest()
+	m.ParameterConstraints = p.constraints()
+	return m
+}
+
+// McpManifest returns the MCP manifest for the IntParameter.
+func (p *IntParameter) McpManifest() ParameterMcpManifest {
+	m := p.CommonParameter.McpManifest()
+	m.ParameterConstraints = p.constraints()
+	return m
+}
+
+func (p *IntParameter) constraints() ParameterConstraints {
+	var c ParameterConstraints
+	for _, e := range p.Enum {
+		c.Enum = append(c.Enum, e)
+	}
+	if p.Minimum != nil {
+		minimum := float64(*p.Minimum)
+		c.Minimum = &minimum
+	}
+	if p.Maximum != nil {
+		maximum := float64(*p.Maximum)
+		c.Maximum = &maximum
+	}
+	return c
+}
+
 // NewFloatParameter is a convenience function for initializing a FloatParameter.
 func NewFloatParameter(name, desc string) *FloatParameter {
 	return &FloatParameter{
@@ -493,6 +922,15 @@ var _ Parameter = &FloatParameter{}
 // FloatParameter is a parameter representing the "float" type.
 type FloatParameter struct {
 	CommonParameter `yaml:",inline"`
+	Enum            []float64 `yaml:"enum"`
+	Minimum         *float64  `yaml:"minimum"`
+	Maximum         *float64  `yaml:"maximum"`
+}
+
+// checkConstraints returns an error if the constraints of the FloatParameter
+// are invalid.
+func (p *FloatParameter) checkConstraints() error {
+	return checkBounds(p.Name, "minimum", "maximum", p.Minimum, p.Maximum)
 }
 
 func (p *FloatParameter) Parse(v any) (any, error) {
@@ -511,6 +949,15 @@ func (p *FloatParameter) Parse(v any) (any, error) {
 		}
 		out = float64(newI)
 	}
+	if len(p.Enum) > 0 && !slices.Contains(p.Enum, out) {
+		return nil, fmt.Errorf("%v is not one of %v", out, p.Enum)
+	}
+	if p.Minimum != nil && out < *p.Minimum {
+		return nil, fmt.Errorf("%v is less than the minimum of %v", out, *p.Minimum)
+	}
+	if p.Maximum != nil && out > *p.Maximum {
+		return nil, fmt.Errorf("%v is greater than the maximum of %v", out, *p.Maximum)
+	}
 	return out, nil
 }
 
@@ -518,6 +965,28 @@ func (p *FloatParameter) GetAuthServices() []ParamAuthService {
 	return p.AuthServices
 }
 
+// Manifest returns the manifest for the FloatParameter.
+func (p *FloatParameter) Manifest() ParameterManifest {
+	m := p.CommonParameter.Manifest()
+	m.ParameterConstraints = p.constraints()
+	return m
+}
+
+// McpManifest returns the MCP manifest for the FloatParameter.
+func (p *FloatParameter) McpManifest() ParameterMcpManifest {
+	m := p.CommonParameter.McpManifest()
+	m.ParameterConstraints = p.constraints()
+	return m
+}
+
+func (p *FloatParameter) constraints() ParameterConstraints {
+	c := ParameterConstraints{Minimum: p.Minimum, Maximum: p.Maximum}
+	for _, e := range p.Enum {
+		c.Enum = append(c.Enum, e)
+	}
+	return c
+}
+
 // NewBooleanParameter is a convenience function for initializing a BooleanParameter.
 func NewBooleanParameter(name, desc string) *BooleanParameter {
 	return &BooleanParameter{
@@ -593,17 +1062,23 @@ var _ Parameter = &ArrayParameter{}
 type ArrayParameter struct {
 	CommonParameter `yaml:",inline"`
 	Items           Parameter `yaml:"items"`
+	MinItems        *int      `yaml:"minItems"`
+	MaxItems        *int      `yaml:"maxItems"`
 }
 
 func (p *ArrayParameter) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
 	var rawItem struct {
 		CommonParameter `yaml:",inline"`
 		Items           util.DelayedUnmarshaler `yaml:"items"`
+		MinItems        *int                    `yaml:"minItems"`
+		MaxItems        *int                    `yaml:"maxItems"`
 	}
 	if err := unmarshal(&rawItem); err != nil {
 		return err
 	}
 	p.CommonParameter = rawItem.CommonParameter
+	p.MinItems = rawItem.MinItems
+	p.MaxItems = rawItem.MaxItems
 	i, err := parseParamFromDelayedUnmarshaler(ctx, &rawItem.Items)
 	if err != nil {
 		return fmt.Errorf("unable to parse 'items' field: %w", err)
@@ -621,6 +1096,12 @@ func (p *ArrayParameter) Parse(v any) (any, error) {
 	if !ok {
 		return nil, &ParseTypeError{p.Name, p.Type, arrVal}
 	}
+	if p.MinItems != nil && len(arrVal) < *p.MinItems {
+		return nil, fmt.Errorf("array has fewer than the minimum of %d items", *p.MinItems)
+	}
+	if p.MaxItems != nil && len(arrVal) > *p.MaxItems {
+		return nil, fmt.Errorf("array has more than the maximum of %d items", *p.MaxItems)
+	}
 	rtn := make([]any, 0, len(arrVal))
 	for idx, val := range arrVal {
 		val, err := p.Items.Parse(val)
@@ -636,6 +1117,12 @@ func (p *ArrayParameter) GetAuthServices() []ParamAuthService {
 	return p.AuthServices
 }
 
+// checkConstraints returns an error if the constraints of the ArrayParameter
+// are invalid.
+func (p *ArrayParameter) checkConstraints() error {
+	return checkBounds(p.Name, "minItems", "maxItems", p.MinItems, p.MaxItems)
+}
+
 // Manifest returns the manifest for the ArrayParameter.
 func (p *ArrayParameter) Manifest() ParameterManifest {
 	// only list ParamAuthService names (without fields) in manifest
@@ -649,7 +1136,13 @@ func (p *ArrayParameter) Manifest() ParameterManifest {
 		Type:         p.Type,
 		Description:  p.Desc,
 		AuthServices: authNames,
+		Required:     p.IsRequired(),
+		Default:      p.Default,
 		Items:        &items,
+		ParameterConstraints: ParameterConstraints{
+			MinItems: p.MinItems,
+			MaxItems: p.MaxItems,
+		},
 	}
 }
 
@@ -664,6 +1157,482 @@ func (p *ArrayParameter) McpManifest() ParameterMcpManifest {
 	return ParameterMcpManifest{
 		Type:        p.Type,
 		Description: p.Desc,
+		Default:     p.Default,
 		Items:       &items,
+		ParameterConstraints: ParameterConstraints{
+			MinItems: p.MinItems,
+			MaxItems: p.MaxItems,
+		},
+	}
+}
+
+// NewObjectParameter is a convenience function for initializing an ObjectParameter.
+func NewObjectParameter(name, desc string, properties Parameters) *ObjectParameter {
+	return &ObjectParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeObject,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+		Properties: properties,
+	}
+}
+
+var _ Parameter = &ObjectParameter{}
+
+// ObjectParameter is a parameter representing the "object" type. Its values
+// are maps of the properties it declares.
+type ObjectParameter struct {
+	CommonParameter `yaml:",inline"`
+	Properties      Parameters `yaml:"properties" validate:"required"`
+}
+
+// checkConstraints returns an error if the properties of the ObjectParameter
+// are invalid.
+func (p *ObjectParameter) checkConstraints() error {
+	seen := make(map[string]bool)
+	for _, prop := range p.Properties {
+		if len(prop.GetAuthServices()) != 0 {
+			return fmt.Errorf("properties of parameter %q should not have auth services", p.Name)
+		}
+		if seen[prop.GetName()] {
+			return fmt.Errorf("parameter %q has several properties named %q", p.Name, prop.GetName())
+		}
+		seen[prop.GetName()] = true
+	}
+	return nil
+}
+
+// Parse casts the value "v" as a map of the properties of the ObjectParameter.
+// Omitted optional properties take their default value, or nil.
+func (p *ObjectParameter) Parse(v any) (any, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, &ParseTypeError{p.Name, p.Type, v}
+	}
+	for k := range m {
+		if !slices.ContainsFunc(p.Properties, func(prop Parameter) bool { return prop.GetName() == k }) {
+			return nil, fmt.Errorf("unknown property %q", k)
+		}
+	}
+	values, err := ParseParams(p.Properties, m, nil)
+	if err != nil {
+		return nil, err
+	}
+	return values.AsMap(), nil
+}
+
+func (p *ObjectParameter) GetAuthServices() []ParamAuthService {
+	return p.AuthServices
+}
+
+// Manifest returns the manifest for the ObjectParameter.
+func (p *ObjectParameter) Manifest() ParameterManifest {
+	m := p.CommonParameter.Manifest()
+	m.Properties = p.Properties.Manifest()
+	return m
+}
+
+// McpManifest returns the MCP manifest for the ObjectParameter, a nested JSON
+// Schema object.
+func (p *ObjectParameter) McpManifest() ParameterMcpManifest {
+	m := p.CommonParameter.McpManifest()
+	schema := p.Properties.McpManifest()
+	m.Properties = schema.Properties
+	m.Required = schema.Required
+	return m
+}
+
+// NewTimeParameter is a convenience function for initializing a TimeParameter
+// of type "date", "time" or "timestamp".
+func NewTimeParameter(name, typ, desc string) *TimeParameter {
+	return &TimeParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typ,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+	}
+}
+
+var _ Parameter = &TimeParameter{}
+
+// TimeParameter is a parameter representing the "date", "time" and
+// "timestamp" types. Its values are strings parsed as a time.Time.
+type TimeParameter struct {
+	CommonParameter `yaml:",inline"`
+	// Format is the layout of the values, in the syntax of the time package.
+	// It defaults to "2006-01-02" for dates, "15:04:05" for times and RFC
+	// 3339 for timestamps.
+	Format string `yaml:"format"`
+	// Timezone is the location of the values without a UTC offset. Values
+	// with an offset are converted to it. It defaults to "UTC".
+	Timezone string `yaml:"timezone"`
+}
+
+// layout returns the layout of the values of the TimeParameter.
+func (p *TimeParameter) layout() string {
+	if p.Format != "" {
+		return p.Format
+	}
+	switch p.Type {
+	case typeDate:
+		return time.DateOnly
+	case typeTime:
+		return time.TimeOnly
+	}
+	return time.RFC3339
+}
+
+// location returns the location of the values of the TimeParameter.
+func (p *TimeParameter) location() (*time.Location, error) {
+	if p.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(p.Timezone)
+}
+
+// checkConstraints returns an error if the timezone of the TimeParameter is
+// unknown.
+func (p *TimeParameter) checkConstraints() error {
+	if _, err := p.location(); err != nil {
+		return fmt.Errorf("parameter %q has an invalid timezone: %w", p.Name, err)
+	}
+	return nil
+}
+
+// Parse casts the value "v" as a time.Time, read with the layout and in the
+// timezone of the TimeParameter.
+func (p *TimeParameter) Parse(v any) (any, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, &ParseTypeError{p.Name, p.Type, v}
+	}
+	loc, err := p.location()
+	if err != nil {
+		return nil, err
+	}
+	t, err := time.ParseInLocation(p.layout(), s, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%q does not match the format %q", s, p.layout())
+	}
+	return t.In(loc), nil
+}
+
+func (p *TimeParameter) GetAuthServices() []ParamAuthService {
+	return p.AuthServices
+}
+
+// schemaFormat returns the JSON Schema format of the values of the
+// TimeParameter, if their layout matches one.
+func (p *TimeParameter) schemaFormat() string {
+	switch {
+	case p.Type == typeDate && p.layout() == time.DateOnly:
+		return "date"
+	case p.Type == typeTimestamp && p.layout() == time.RFC3339:
+		return "date-time"
+	}
+	// JSON Schema times require a UTC offset
+	return ""
+}
+
+// Manifest returns the manifest for the TimeParameter. Its values are
+// strings for clients.
+func (p *TimeParameter) Manifest() ParameterManifest {
+	m := p.CommonParameter.Manifest()
+	m.Type = typeString
+	m.Format = p.schemaFormat()
+	if t, ok := p.Default.(time.Time); ok {
+		m.Default = t.Format(p.layout())
+	}
+	return m
+}
+
+// McpManifest returns the MCP manifest for the TimeParameter.
+func (p *TimeParameter) McpManifest() ParameterMcpManifest {
+	m := p.CommonParameter.McpManifest()
+	m.Type = typeString
+	m.Format = p.schemaFormat()
+	if t, ok := p.Default.(time.Time); ok {
+		m.Default = t.Format(p.layout())
+	}
+	return m
+}
+
+// NewDurationParameter is a convenience function for initializing a
+// DurationParameter.
+func NewDurationParameter(name, desc string) *DurationParameter {
+	return &DurationParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeDuration,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+	}
+}
+
+var _ Parameter = &DurationParameter{}
+
+// DurationParameter is a parameter representing the "duration" type. Its
+// values are strings parsed as a time.Duration.
+type DurationParameter struct {
+	CommonParameter `yaml:",inline"`
+	// Format is the syntax of the values: "iso8601", the default, such as
+	// "PT1H30M", or "go", such as "1h30m".
+	Format string `yaml:"format" validate:"omitempty,oneof=iso8601 go"`
+}
+
+// Parse casts the value "v" as a time.Duration.
+func (p *DurationParameter) Parse(v any) (any, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, &ParseTypeError{p.Name, p.Type, v}
+	}
+	if p.Format == "go" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a duration such as \"1h30m\"", s)
+		}
+		return d, nil
+	}
+	d, err := parseISODuration(s)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not an ISO 8601 duration such as \"PT1H30M\"", s)
+	}
+	return d, nil
+}
+
+func (p *DurationParameter) GetAuthServices() []ParamAuthService {
+	return p.AuthServices
+}
+
+// format returns the string of the duration d in the format of the
+// DurationParameter.
+func (p *DurationParameter) format(d time.Duration) string {
+	if p.Format == "go" {
+		return d.String()
+	}
+	return formatISODuration(d)
+}
+
+// Manifest returns the manifest for the DurationParameter. Its values are
+// strings for clients.
+func (p *DurationParameter) Manifest() ParameterManifest {
+	m := p.CommonParameter.Manifest()
+	m.Type = typeString
+	if p.Format != "go" {
+		m.Format = typeDuration
+	}
+	if d, ok := p.Default.(time.Duration); ok {
+		m.Default = p.format(d)
+	}
+	return m
+}
+
+// McpManifest returns the MCP manifest for the DurationParameter.
+func (p *DurationParameter) McpManifest() ParameterMcpManifest {
+	m := p.CommonParameter.McpManifest()
+	m.Type = typeString
+	if p.Format != "go" {
+		m.Format = typeDuration
+	}
+	if d, ok := p.Default.(time.Duration); ok {
+		m.Default = p.format(d)
+	}
+	return m
+}
+
+// parseISODuration parses an ISO 8601 duration, such as "PT1H30M". Years and
+// months are not accepted, since their length varies.
+func parseISODuration(s string) (time.Duration, error) {
+	rest, neg := strings.CutPrefix(s, "-")
+	rest, ok := strings.CutPrefix(rest, "P")
+	if !ok || rest == "" || strings.HasSuffix(rest, "T") {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", s)
+	}
+	var d time.Duration
+	inTime := false
+	for rest != "" {
+		if rest[0] == 'T' && !inTime {
+			inTime = true
+			rest = rest[1:]
+			continue
+		}
+		i := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q", s)
+		}
+		n, err := strconv.ParseFloat(rest[:i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q", s)
+		}
+		var unit time.Duration
+		switch {
+		case !inTime && rest[i] == 'W':
+			unit = 7 * 24 * time.Hour
+		case !inTime && rest[i] == 'D':
+			unit = 24 * time.Hour
+		case inTime && rest[i] == 'H':
+			unit = time.Hour
+		case inTime && rest[i] == 'M':
+			unit = time.Minute
+		case inTime && rest[i] == 'S':
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q", s)
+		}
+		d += time.Duration(n * float64(unit))
+		rest = rest[i+1:]
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// formatISODuration returns the ISO 8601 duration of d, such as "PT1H30M".
+func formatISODuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	if d < 0 {
+		b.WriteString("-")
+		d = -d
+	}
+	b.WriteString("PT")
+	if h := d / time.Hour; h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+		d -= m * time.Minute
+	}
+	if d > 0 {
+		b.WriteString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S")
+	}
+	return b.String()
+}
+
+// NewIdentifierParameter is a convenience function for initializing an
+// IdentifierParameter restricted to the names of allowed.
+func NewIdentifierParameter(name, desc string, allowed []string) *IdentifierParameter {
+	return &IdentifierParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeIdentifier,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+		Enum: allowed,
+	}
+}
+
+var _ Parameter = &IdentifierParameter{}
+
+// IdentifierParameter is a parameter representing the "identifier" type: the
+// name of a table, a column or another object of the database. Its values are
+// substituted into the statement of a tool, quoted for the dialect of its
+// source, rather than bound to it.
+type IdentifierParameter struct {
+	CommonParameter `yaml:",inline"`
+	Enum            []string `yaml:"enum"`
+	// Pattern is a regular expression matching the whole value.
+	Pattern string `yaml:"pattern"`
+	// Quoted is whether values are quoted, which is the default. Values of
+	// identifiers restricted by Enum, such as sort orders, can be
+	// substituted as is.
+	Quoted *bool `yaml:"quoted"`
+}
+
+// IsQuoted returns whether the values of the IdentifierParameter are quoted.
+func (p *IdentifierParameter) IsQuoted() bool {
+	return p.Quoted == nil || *p.Quoted
+}
+
+// anchoredPattern returns the pattern of the IdentifierParameter, matching
+// whole values.
+func (p *IdentifierParameter) anchoredPattern() string {
+	if p.Pattern == "" {
+		return ""
+	}
+	return "^(?:" + p.Pattern + ")$"
+}
+
+// checkConstraints returns an error if the values of the IdentifierParameter
+// are not restricted.
+func (p *IdentifierParameter) checkConstraints() error {
+	if len(p.Enum) == 0 && p.Pattern == "" {
+		return fmt.Errorf("identifier parameter %q must set an enum or a pattern", p.Name)
+	}
+	if _, err := compilePattern(p.Name, p.anchoredPattern()); err != nil {
+		return err
+	}
+	if !p.IsQuoted() && len(p.Enum) == 0 {
+		return fmt.Errorf("identifier parameter %q must set an enum to be unquoted", p.Name)
+	}
+	if !p.IsRequired() && p.Default == nil {
+		// statements cannot refer to a NULL identifier
+		return fmt.Errorf("identifier parameter %q must have a default value to be optional", p.Name)
+	}
+	return nil
+}
+
+// Parse casts the value "v" as an identifier allowed by the
+// IdentifierParameter.
+func (p *IdentifierParameter) Parse(v any) (any, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, &ParseTypeError{p.Name, p.Type, v}
+	}
+	if s == "" {
+		return nil, fmt.Errorf("identifier cannot be empty")
+	}
+	if len(p.Enum) > 0 && !slices.Contains(p.Enum, s) {
+		return nil, fmt.Errorf("%q is not one of %q", s, p.Enum)
+	}
+	if p.Pattern != "" {
+		// the pattern is compiled when the parameter is loaded
+		re, err := compilePattern(p.Name, p.anchoredPattern())
+		if err != nil {
+			return nil, err
+		}
+		if !re.MatchString(s) {
+			return nil, fmt.Errorf("%q does not match the pattern %q", s, p.Pattern)
+		}
+	}
+	return s, nil
+}
+
+func (p *IdentifierParameter) GetAuthServices() []ParamAuthService {
+	return p.AuthServices
+}
+
+// Manifest returns the manifest for the IdentifierParameter. Its values are
+// strings for clients.
+func (p *IdentifierParameter) Manifest() ParameterManifest {
+	m := p.CommonParameter.Manifest()
+	m.Type = typeString
+	m.ParameterConstraints = p.constraints()
+	return m
+}
+
+// McpManifest returns the MCP manifest for the IdentifierParameter.
+func (p *IdentifierParameter) McpManifest() ParameterMcpManifest {
+	m := p.CommonParameter.McpManifest()
+	m.Type = typeString
+	m.ParameterConstraints = p.constraints()
+	return m
+}
+
+func (p *IdentifierParameter) constraints() ParameterConstraints {
+	c := ParameterConstraints{Pattern: p.anchoredPattern()}
+	for _, e := range p.Enum {
+		c.Enum = append(c.Enum, e)
 	}
+	return c
 }
@@ -17,9 +17,13 @@ package tools_test
 import (
 	"bytes"
 	"encoding/json"
+	"maps"
 	"math"
 	"reflect"
 	"testing"
+	"time"
+	// timezones of the tests
+	_ "time/tzdata"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
@@ -808,22 +812,22 @@ func TestParamManifest(t *testing.T) {
 		{
 			name: "string",
 			in:   tools.NewStringParameter("foo-string", "bar"),
-			want: tools.ParameterManifest{Name: "foo-string", Type: "string", Description: "bar", AuthServices: []string{}},
+			want: tools.ParameterManifest{Name: "foo-string", Type: "string", Description: "bar", AuthServices: []string{}, Required: true},
 		},
 		{
 			name: "int",
 			in:   tools.NewIntParameter("foo-int", "bar"),
-			want: tools.ParameterManifest{Name: "foo-int", Type: "integer", Description: "bar", AuthServices: []string{}},
+			want: tools.ParameterManifest{Name: "foo-int", Type: "integer", Description: "bar", AuthServices: []string{}, Required: true},
 		},
 		{
 			name: "float",
 			in:   tools.NewFloatParameter("foo-float", "bar"),
-			want: tools.ParameterManifest{Name: "foo-float", Type: "float", Description: "bar", AuthServices: []string{}},
+			want: tools.ParameterManifest{Name: "foo-float", Type: "float", Description: "bar", AuthServices: []string{}, Required: true},
 		},
 		{
 			name: "boolean",
 			in:   tools.NewBooleanParameter("foo-bool", "bar"),
-			want: tools.ParameterManifest{Name: "foo-bool", Type: "boolean", Description: "bar", AuthServices: []string{}},
+			want: tools.ParameterManifest{Name: "foo-bool", Type: "boolean", Description: "bar", AuthServices: []string{}, Required: true},
 		},
 		{
 			name: "array",
@@ -833,7 +837,8 @@ func TestParamManifest(t *testing.T) {
 				Type:         "array",
 				Description:  "bar",
 				AuthServices: []string{},
-				Items:        &tools.ParameterManifest{Name: "foo-string", Type: "string", Description: "bar", AuthServices: []string{}},
+				Required:     true,
+				Items:        &tools.ParameterManifest{Name: "foo-string", Type: "string", Description: "bar", AuthServices: []string{}, Required: true},
 			},
 		},
 	}
@@ -959,6 +964,160 @@ func TestFailParametersUnmarshal(t *testing.T) {
 			},
 			err: "unable to parse as \"array\": unable to parse 'items' field: unable to parse as \"string\": Key: 'CommonParameter.Name' Error:Field validation for 'Name' failed on the 'required' tag",
 		},
+		{
+			name: "default of the wrong type",
+			in: []map[string]any{
+				{
+					"name":        "my_int",
+					"type":        "integer",
+					"description": "this param is an int",
+					"default":     "abc",
+				},
+			},
+			err: "invalid default value for parameter \"my_int\": \"abc\" not type \"integer\"",
+		},
+		{
+			name: "required parameter with a default",
+			in: []map[string]any{
+				{
+					"name":        "my_string",
+					"type":        "string",
+					"description": "this param is a string",
+					"required":    true,
+					"default":     "foo",
+				},
+			},
+			err: "parameter \"my_string\" is required and cannot have a default value",
+		},
+		{
+			name: "invalid pattern",
+			in: []map[string]any{
+				{
+					"name":        "my_string",
+					"type":        "string",
+					"description": "this param is a string",
+					"pattern":     "[a-z",
+				},
+			},
+			err: "parameter \"my_string\" has an invalid pattern: error parsing regexp: missing closing ]: `[a-z`",
+		},
+		{
+			name: "minimum greater than maximum",
+			in: []map[string]any{
+				{
+					"name":        "my_int",
+					"type":        "integer",
+					"description": "this param is an int",
+					"minimum":     10,
+					"maximum":     1,
+				},
+			},
+			err: "parameter \"my_int\" has a minimum greater than its maximum",
+		},
+		{
+			name: "default violating constraints",
+			in: []map[string]any{
+				{
+					"name":        "my_string",
+					"type":        "string",
+					"description": "this param is a string",
+					"enum":        []string{"a", "b"},
+					"default":     "c",
+				},
+			},
+			err: "invalid default value for parameter \"my_string\": \"c\" is not one of [\"a\" \"b\"]",
+		},
+		{
+			name: "object parameter missing properties",
+			in: []map[string]any{
+				{
+					"name":        "my_object",
+					"type":        "object",
+					"description": "this param is an object",
+				},
+			},
+			err: "unable to parse as \"object\": Key: 'ObjectParameter.Properties' Error:Field validation for 'Properties' failed on the 'required' tag",
+		},
+		{
+			name: "object property with auth services",
+			in: []map[string]any{
+				{
+					"name":        "my_object",
+					"type":        "object",
+					"description": "this param is an object",
+					"properties": []map[string]any{
+						{
+							"name":         "email",
+							"type":         "string",
+							"description":  "the email",
+							"authServices": []map[string]string{{"name": "my-google-auth", "field": "email"}},
+						},
+					},
+				},
+			},
+			err: "properties of parameter \"my_object\" should not have auth services",
+		},
+		{
+			name: "invalid timezone",
+			in: []map[string]any{
+				{
+					"name":        "my_timestamp",
+					"type":        "timestamp",
+					"description": "this param is a timestamp",
+					"timezone":    "Mars/Olympus_Mons",
+				},
+			},
+			err: "parameter \"my_timestamp\" has an invalid timezone: unknown time zone Mars/Olympus_Mons",
+		},
+		{
+			name: "invalid duration format",
+			in: []map[string]any{
+				{
+					"name":        "my_duration",
+					"type":        "duration",
+					"description": "this param is a duration",
+					"format":      "seconds",
+				},
+			},
+			err: "unable to parse as \"duration\": [2:9] Key: 'DurationParameter.Format' Error:Field validation for 'Format' failed on the 'oneof' tag\n   1 | description: this param is a duration\n>  2 | format: seconds\n               ^\n   3 | name: my_duration\n   4 | type: duration",
+		},
+		{
+			name: "unrestricted identifier",
+			in: []map[string]any{
+				{
+					"name":        "my_table",
+					"type":        "identifier",
+					"description": "this param is a table",
+				},
+			},
+			err: "identifier parameter \"my_table\" must set an enum or a pattern",
+		},
+		{
+			name: "unquoted identifier without enum",
+			in: []map[string]any{
+				{
+					"name":        "my_table",
+					"type":        "identifier",
+					"description": "this param is a table",
+					"pattern":     "[a-z_]+",
+					"quoted":      false,
+				},
+			},
+			err: "identifier parameter \"my_table\" must set an enum to be unquoted",
+		},
+		{
+			name: "optional identifier without default",
+			in: []map[string]any{
+				{
+					"name":        "my_table",
+					"type":        "identifier",
+					"description": "this param is a table",
+					"enum":        []string{"users"},
+					"required":    false,
+				},
+			},
+			err: "identifier parameter \"my_table\" must have a default value to be optional",
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
@@ -980,3 +1139,572 @@ func TestFailParametersUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestOptionalParameters(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	- name: status
+	  type: string
+	  description: Filter on the status.
+	  required: false
+	- name: limit
+	  type: integer
+	  description: Maximum number of results.
+	  default: 10
+	- name: ratio
+	  type: float
+	  description: Minimum ratio.
+	  default: 1
+	- name: tags
+	  type: array
+	  description: Tags to match.
+	  default: ["a", "b"]
+	  items:
+	    name: tag
+	    type: string
+	    description: A tag.
+	- name: id
+	  type: integer
+	  description: The id.
+	`
+	var params tools.Parameters
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &params); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+
+	gotManifest := params.McpManifest()
+	if diff := cmp.Diff([]string{"id"}, gotManifest.Required); diff != "" {
+		t.Fatalf("incorrect required parameters: diff %v", diff)
+	}
+	if got := gotManifest.Properties["limit"].Default; got != 10 {
+		t.Fatalf("incorrect default in MCP manifest: got %v, want 10", got)
+	}
+	if got := params[0].Manifest(); got.Required || got.Default != nil {
+		t.Fatalf("incorrect manifest for optional parameter: %+v", got)
+	}
+
+	tcs := []struct {
+		name string
+		in   map[string]any
+		want tools.ParamValues
+	}{
+		{
+			name: "omitted",
+			in:   map[string]any{"id": 1},
+			want: tools.ParamValues{
+				{Name: "status", Value: nil},
+				{Name: "limit", Value: 10},
+				{Name: "ratio", Value: 1.0},
+				{Name: "tags", Value: []any{"a", "b"}},
+				{Name: "id", Value: 1},
+			},
+		},
+		{
+			name: "null",
+			in:   map[string]any{"id": 1, "status": nil, "limit": nil},
+			want: tools.ParamValues{
+				{Name: "status", Value: nil},
+				{Name: "limit", Value: 10},
+				{Name: "ratio", Value: 1.0},
+				{Name: "tags", Value: []any{"a", "b"}},
+				{Name: "id", Value: 1},
+			},
+		},
+		{
+			name: "provided",
+			in:   map[string]any{"id": 1, "status": "open", "limit": 5, "ratio": 0.5, "tags": []any{"c"}},
+			want: tools.ParamValues{
+				{Name: "status", Value: "open"},
+				{Name: "limit", Value: 5},
+				{Name: "ratio", Value: 0.5},
+				{Name: "tags", Value: []any{"c"}},
+				{Name: "id", Value: 1},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ParseParams(params, tc.in, nil)
+			if err != nil {
+				t.Fatalf("unexpected error from ParseParams: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect values: diff %v", diff)
+			}
+		})
+	}
+
+	if _, err := tools.ParseParams(params, map[string]any{}, nil); err == nil || err.Error() != `parameter "id" is required` {
+		t.Fatalf("unexpected error for missing required parameter: %v", err)
+	}
+}
+
+func TestParameterConstraints(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	- name: status
+	  type: string
+	  description: The status.
+	  enum: [open, closed]
+	- name: code
+	  type: string
+	  description: An airport code.
+	  pattern: ^[A-Z]{3}$
+	- name: comment
+	  type: string
+	  description: A comment.
+	  minLength: 2
+	  maxLength: 4
+	- name: limit
+	  type: integer
+	  description: The number of results.
+	  minimum: 1
+	  maximum: 100
+	- name: size
+	  type: integer
+	  description: The size.
+	  enum: [1, 2, 4]
+	- name: ratio
+	  type: float
+	  description: The ratio.
+	  minimum: 0
+	  maximum: 1.5
+	- name: tags
+	  type: array
+	  description: The tags.
+	  minItems: 1
+	  maxItems: 2
+	  items:
+	    name: tag
+	    type: string
+	    description: A tag.
+	    enum: [a, b, c]
+	`
+	var params tools.Parameters
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &params); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+
+	valid := map[string]any{
+		"status":  "open",
+		"code":    "SFO",
+		"comment": "héé",
+		"limit":   100,
+		"size":    4,
+		"ratio":   0.0,
+		"tags":    []any{"a", "c"},
+	}
+	if _, err := tools.ParseParams(params, valid, nil); err != nil {
+		t.Fatalf("unexpected error from ParseParams: %s", err)
+	}
+
+	tcs := []struct {
+		name  string
+		param string
+		value any
+		err   string
+	}{
+		{"enum", "status", "pending", `unable to parse value for "status": "pending" is not one of ["open" "closed"]`},
+		{"pattern", "code", "sfo", `unable to parse value for "code": "sfo" does not match the pattern "^[A-Z]{3}$"`},
+		{"min length", "comment", "a", `unable to parse value for "comment": "a" is shorter than the minimum length of 2`},
+		{"max length", "comment", "abcde", `unable to parse value for "comment": "abcde" is longer than the maximum length of 4`},
+		{"minimum", "limit", 0, `unable to parse value for "limit": 0 is less than the minimum of 1`},
+		{"maximum", "limit", 101, `unable to parse value for "limit": 101 is greater than the maximum of 100`},
+		{"integer enum", "size", 3, `unable to parse value for "size": 3 is not one of [1 2 4]`},
+		{"float maximum", "ratio", 1.6, `unable to parse value for "ratio": 1.6 is greater than the maximum of 1.5`},
+		{"min items", "tags", []any{}, `unable to parse value for "tags": array has fewer than the minimum of 1 items`},
+		{"max items", "tags", []any{"a", "b", "c"}, `unable to parse value for "tags": array has more than the maximum of 2 items`},
+		{"items", "tags", []any{"d"}, `unable to parse value for "tags": unable to parse element #0: "d" is not one of ["a" "b" "c"]`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			data := maps.Clone(valid)
+			data[tc.param] = tc.value
+			_, err := tools.ParseParams(params, data, nil)
+			if err == nil {
+				t.Fatalf("expected error for %v", tc.value)
+			}
+			if err.Error() != tc.err {
+				t.Fatalf("unexpected error: got %q, want %q", err, tc.err)
+			}
+		})
+	}
+
+	one, hundred := 1.0, 100.0
+	wantLimit := tools.ParameterMcpManifest{
+		Type:                 "integer",
+		Description:          "The number of results.",
+		ParameterConstraints: tools.ParameterConstraints{Minimum: &one, Maximum: &hundred},
+	}
+	if diff := cmp.Diff(wantLimit, params[3].McpManifest()); diff != "" {
+		t.Fatalf("incorrect MCP manifest: diff %v", diff)
+	}
+	got, err := json.Marshal(params[0].Manifest())
+	if err != nil {
+		t.Fatalf("unable to marshal manifest: %s", err)
+	}
+	want := `{"name":"status","type":"string","description":"The status.","authSources":[],"required":true,"enum":["open","closed"]}`
+	if string(got) != want {
+		t.Fatalf("incorrect manifest: got %s, want %s", got, want)
+	}
+}
+
+func TestInvalidPatternInCode(t *testing.T) {
+	p := tools.NewStringParameter("code", "The code.")
+	p.Pattern = "[A-Z"
+	_, err := tools.ParseParams(tools.Parameters{p}, map[string]any{"code": "ABC"}, nil)
+	want := "unable to parse value for \"code\": parameter \"code\" has an invalid pattern: error parsing regexp: missing closing ]: `[A-Z`"
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+func TestObjectParameters(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	- name: filter
+	  type: object
+	  description: The filter.
+	  properties:
+	    - name: city
+	      type: string
+	      description: The city.
+	    - name: stars
+	      type: integer
+	      description: The minimum number of stars.
+	      default: 3
+	    - name: location
+	      type: object
+	      description: The location.
+	      required: false
+	      properties:
+	        - name: lat
+	          type: float
+	          description: The latitude.
+	        - name: lng
+	          type: float
+	          description: The longitude.
+	`
+	var params tools.Parameters
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &params); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	stars := tools.NewIntParameter("stars", "The minimum number of stars.")
+	stars.Default = 3
+	location := tools.NewObjectParameter("location", "The location.", tools.Parameters{
+		tools.NewFloatParameter("lat", "The latitude."),
+		tools.NewFloatParameter("lng", "The longitude."),
+	})
+	optional := false
+	location.Required = &optional
+	want := tools.Parameters{
+		tools.NewObjectParameter("filter", "The filter.", tools.Parameters{
+			tools.NewStringParameter("city", "The city."),
+			stars,
+			location,
+		}),
+	}
+	if diff := cmp.Diff(want, params); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+
+	tcs := []struct {
+		name string
+		in   any
+		want any
+		err  string
+	}{
+		{
+			name: "nested",
+			in:   map[string]any{"city": "Paris", "stars": 4, "location": map[string]any{"lat": 48.8, "lng": 2.3}},
+			want: map[string]any{"city": "Paris", "stars": 4, "location": map[string]any{"lat": 48.8, "lng": 2.3}},
+		},
+		{
+			name: "defaults",
+			in:   map[string]any{"city": "Paris"},
+			want: map[string]any{"city": "Paris", "stars": 3, "location": nil},
+		},
+		{
+			name: "missing property",
+			in:   map[string]any{"stars": 4},
+			err:  `unable to parse value for "filter": parameter "city" is required`,
+		},
+		{
+			name: "unknown property",
+			in:   map[string]any{"city": "Paris", "country": "France"},
+			err:  `unable to parse value for "filter": unknown property "country"`,
+		},
+		{
+			name: "nested error",
+			in:   map[string]any{"city": "Paris", "location": map[string]any{"lat": "north", "lng": 2.3}},
+			err:  `unable to parse value for "filter": unable to parse value for "location": unable to parse value for "lat": "north" not type "float"`,
+		},
+		{
+			name: "not an object",
+			in:   "Paris",
+			err:  `unable to parse value for "filter": "Paris" not type "object"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tools.ParseParams(params, map[string]any{"filter": tc.in}, nil)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error from ParseParams: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got[0].Value); diff != "" {
+				t.Fatalf("incorrect value: diff %v", diff)
+			}
+		})
+	}
+
+	gotMcp, err := json.Marshal(params[0].McpManifest())
+	if err != nil {
+		t.Fatalf("unable to marshal MCP manifest: %s", err)
+	}
+	wantMcp := `{"type":"object","description":"The filter.","properties":{"city":{"type":"string","description":"The city."},"location":{"type":"object","description":"The location.","properties":{"lat":{"type":"float","description":"The latitude."},"lng":{"type":"float","description":"The longitude."}},"required":["lat","lng"]},"stars":{"type":"integer","description":"The minimum number of stars.","default":3}},"required":["city"]}`
+	if string(gotMcp) != wantMcp {
+		t.Fatalf("incorrect MCP manifest: got %s, want %s", gotMcp, wantMcp)
+	}
+	gotManifest := params[0].Manifest()
+	if len(gotManifest.Properties) != 3 || gotManifest.Properties[2].Name != "location" || len(gotManifest.Properties[2].Properties) != 2 {
+		t.Fatalf("incorrect manifest: %+v", gotManifest)
+	}
+}
+
+func TestTemporalParameters(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	- name: day
+	  type: date
+	  description: The day.
+	  default: 2024-01-31
+	- name: opening
+	  type: time
+	  description: The opening time.
+	- name: since
+	  type: timestamp
+	  description: The start of the period.
+	- name: departure
+	  type: timestamp
+	  description: The local departure time.
+	  format: 2006-01-02 15:04
+	  timezone: Europe/Paris
+	- name: window
+	  type: duration
+	  description: The length of the period.
+	  default: PT1H30M
+	- name: timeout
+	  type: duration
+	  description: The timeout.
+	  format: go
+	`
+	var params tools.Parameters
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &params); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+
+	data := map[string]any{
+		"opening":   "09:30:00",
+		"since":     "2024-07-01T12:00:00+02:00",
+		"departure": "2024-07-01 12:00",
+		"timeout":   "2m30s",
+	}
+	got, err := tools.ParseParams(params, data, nil)
+	if err != nil {
+		t.Fatalf("unexpected error from ParseParams: %s", err)
+	}
+	want := map[string]time.Time{
+		"day":       time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		"opening":   time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC),
+		"since":     time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC),
+		"departure": time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC),
+	}
+	for name, w := range want {
+		v, ok := got.AsMap()[name].(time.Time)
+		if !ok || !v.Equal(w) {
+			t.Fatalf("incorrect value for %q: got %v, want %v", name, got.AsMap()[name], w)
+		}
+	}
+	if v := got.AsMap()["window"]; v != 90*time.Minute {
+		t.Fatalf("incorrect default duration: got %v", v)
+	}
+	if v := got.AsMap()["timeout"]; v != 150*time.Second {
+		t.Fatalf("incorrect duration: got %v", v)
+	}
+
+	wantText := map[string]any{
+		"day":       "2024-01-31",
+		"opening":   "09:30:00",
+		"since":     "2024-07-01T10:00:00Z",
+		"departure": "2024-07-01 12:00",
+		"window":    "PT1H30M",
+		"timeout":   "2m30s",
+	}
+	if diff := cmp.Diff(wantText, got.TimesAsText(params).AsMap()); diff != "" {
+		t.Fatalf("incorrect text values: diff %v", diff)
+	}
+
+	tcs := []struct {
+		name  string
+		param string
+		value any
+		err   string
+	}{
+		{"date format", "day", "31/01/2024", `unable to parse value for "day": "31/01/2024" does not match the format "2006-01-02"`},
+		{"custom format", "departure", "2024-07-01T12:00:00Z", `unable to parse value for "departure": "2024-07-01T12:00:00Z" does not match the format "2006-01-02 15:04"`},
+		{"not a string", "since", true, `unable to parse value for "since": %!q(bool=true) not type "timestamp"`},
+		{"iso duration", "window", "90 minutes", `unable to parse value for "window": "90 minutes" is not an ISO 8601 duration such as "PT1H30M"`},
+		{"iso duration with months", "window", "P1M", `unable to parse value for "window": "P1M" is not an ISO 8601 duration such as "PT1H30M"`},
+		{"go duration", "timeout", "PT1M", `unable to parse value for "timeout": "PT1M" is not a duration such as "1h30m"`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			d := maps.Clone(data)
+			d[tc.param] = tc.value
+			_, err := tools.ParseParams(params, d, nil)
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+
+	gotMcp, err := json.Marshal(params.McpManifest().Properties)
+	if err != nil {
+		t.Fatalf("unable to marshal MCP manifest: %s", err)
+	}
+	wantMcp := `{"day":{"type":"string","description":"The day.","default":"2024-01-31","format":"date"},` +
+		`"departure":{"type":"string","description":"The local departure time."},` +
+		`"opening":{"type":"string","description":"The opening time."},` +
+		`"since":{"type":"string","description":"The start of the period.","format":"date-time"},` +
+		`"timeout":{"type":"string","description":"The timeout."},` +
+		`"window":{"type":"string","description":"The length of the period.","default":"PT1H30M","format":"duration"}}`
+	if string(gotMcp) != wantMcp {
+		t.Fatalf("incorrect MCP manifest: got %s, want %s", gotMcp, wantMcp)
+	}
+	if got := params[0].Manifest(); got.Type != "string" || got.Format != "date" || got.Default != "2024-01-31" {
+		t.Fatalf("incorrect manifest: %+v", got)
+	}
+}
+
+func TestISODurations(t *testing.T) {
+	tcs := []struct {
+		in   string
+		want time.Duration
+		out  string
+	}{
+		{"PT0S", 0, "PT0S"},
+		{"PT1H30M", 90 * time.Minute, "PT1H30M"},
+		{"PT90M", 90 * time.Minute, "PT1H30M"},
+		{"PT1.5S", 1500 * time.Millisecond, "PT1.5S"},
+		{"P1DT1H", 25 * time.Hour, "PT25H"},
+		{"P1W", 7 * 24 * time.Hour, "PT168H"},
+		{"-PT30S", -30 * time.Second, "-PT30S"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.in, func(t *testing.T) {
+			p := tools.NewDurationParameter("d", "a duration")
+			got, err := p.Parse(tc.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Fatalf("incorrect duration: got %v, want %v", got, tc.want)
+			}
+			p.Default = got
+			if out := p.McpManifest().Default; out != tc.out {
+				t.Fatalf("incorrect format: got %v, want %v", out, tc.out)
+			}
+		})
+	}
+	for _, in := range []string{"", "P", "PT", "P1H", "PT1D", "1H", "PT-1S", "P1Y"} {
+		if _, err := tools.NewDurationParameter("d", "a duration").Parse(in); err == nil {
+			t.Fatalf("expected error for %q", in)
+		}
+	}
+}
+
+func TestIdentifierParameters(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	- name: table
+	  type: identifier
+	  description: The table.
+	  pattern: "[a-z_]+"
+	- name: order
+	  type: identifier
+	  description: The sort order.
+	  enum: [ASC, DESC]
+	  quoted: false
+	  default: ASC
+	`
+	var params tools.Parameters
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &params); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	order := tools.NewIdentifierParameter("order", "The sort order.", []string{"ASC", "DESC"})
+	unquoted := false
+	order.Quoted = &unquoted
+	order.Default = "ASC"
+	table := tools.NewIdentifierParameter("table", "The table.", nil)
+	table.Pattern = "[a-z_]+"
+	if diff := cmp.Diff(tools.Parameters{table, order}, params); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+
+	got, err := tools.ParseParams(params, map[string]any{"table": "flights"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error from ParseParams: %s", err)
+	}
+	if diff := cmp.Diff(tools.ParamValues{{Name: "table", Value: "flights"}, {Name: "order", Value: "ASC"}}, got); diff != "" {
+		t.Fatalf("incorrect values: diff %v", diff)
+	}
+
+	tcs := []struct {
+		name string
+		in   map[string]any
+		err  string
+	}{
+		{"pattern", map[string]any{"table": "flights; DROP TABLE users"}, `unable to parse value for "table": "flights; DROP TABLE users" does not match the pattern "[a-z_]+"`},
+		{"enum", map[string]any{"table": "flights", "order": "ASC, id"}, `unable to parse value for "order": "ASC, id" is not one of ["ASC" "DESC"]`},
+		{"empty", map[string]any{"table": ""}, `unable to parse value for "table": identifier cannot be empty`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tools.ParseParams(params, tc.in, nil)
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.err)
+			}
+		})
+	}
+
+	gotMcp, err := json.Marshal(params.McpManifest().Properties)
+	if err != nil {
+		t.Fatalf("unable to marshal MCP manifest: %s", err)
+	}
+	wantMcp := `{"order":{"type":"string","description":"The sort order.","default":"ASC","enum":["ASC","DESC"]},` +
+		`"table":{"type":"string","description":"The table.","pattern":"^(?:[a-z_]+)$"}}`
+	if string(gotMcp) != wantMcp {
+		t.Fatalf("incorrect MCP manifest: got %s, want %s", gotMcp, wantMcp)
+	}
+}
@@ -21,10 +21,12 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/postgresexecutesql"
 )
 
 func TestParseFromYamlExecuteSql(t *testing.T) {
+	falseValue := false
 	ctx, err := testutils.ContextWithNewLogger()
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
@@ -56,6 +58,32 @@ func TestParseFromYamlExecuteSql(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with annotations",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-execute-sql
+					source: my-instance
+					description: some description
+					annotations:
+						title: Run SQL
+						destructiveHint: false
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgresexecutesql.Config{
+					Name:         "example_tool",
+					Kind:         postgresexecutesql.ToolKind,
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+					Annotations: &tools.ToolAnnotations{
+						Title:           "Run SQL",
+						DestructiveHint: &falseValue,
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -0,0 +1,52 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+)
+
+// ProgressReporter is called by tools to report the progress of an
+// invocation. total is 0 when it is unknown.
+type ProgressReporter func(progress, total float64, message string)
+
+type progressReporterKey struct{}
+
+// rowProgressInterval is the number of rows read between two progress reports.
+const rowProgressInterval = 1000
+
+// WithProgressReporter adds a ProgressReporter into the context as a value
+func WithProgressReporter(ctx context.Context, r ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterKey{}, r)
+}
+
+// ReportProgress reports the progress of the current invocation. It does
+// nothing if the caller did not ask for progress updates.
+func ReportProgress(ctx context.Context, progress, total float64, message string) {
+	if r, ok := ctx.Value(progressReporterKey{}).(ProgressReporter); ok && r != nil {
+		r(progress, total, message)
+	}
+}
+
+// ReportRowProgress reports the number of rows read so far by the current
+// invocation. total is 0 when the number of rows is unknown. To avoid
+// flooding clients, progress is only reported every rowProgressInterval rows.
+func ReportRowProgress(ctx context.Context, rows, total int) {
+	if rows == 0 || rows%rowProgressInterval != 0 {
+		return
+	}
+	ReportProgress(ctx, float64(rows), float64(total), fmt.Sprintf("read %d rows", rows))
+}
@@ -0,0 +1,40 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestReportRowProgress(t *testing.T) {
+	// reporting progress without a reporter is a no-op
+	tools.ReportRowProgress(context.Background(), 1000, 0)
+
+	var got []string
+	ctx := tools.WithProgressReporter(context.Background(), func(progress, total float64, message string) {
+		got = append(got, message)
+	})
+	for i := 1; i <= 2500; i++ {
+		tools.ReportRowProgress(ctx, i, 2500)
+	}
+	want := []string{"read 1000 rows", "read 2000 rows"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect progress reports: diff %v", diff)
+	}
+}
@@ -17,17 +17,35 @@ package spanner
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
+	"cloud.google.com/go/civil"
 	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	spannerdb "github.com/googleapis/genai-toolbox/internal/sources/spanner"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 const ToolKind string = "spanner-sql"
 
+func init() {
+	tools.MustRegister(ToolKind, newConfig)
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
 type compatibleSource interface {
 	SpannerClient() *spanner.Client
 	DatabaseDialect() string
@@ -39,22 +57,44 @@ var _ compatibleSource = &spannerdb.Source{}
 var compatibleSources = [...]string{spannerdb.SourceKind}
 
 type Config struct {
-	Name         string           `yaml:"name" validate:"required"`
-	Kind         string           `yaml:"kind" validate:"required"`
-	Source       string           `yaml:"source" validate:"required"`
-	Description  string           `yaml:"description" validate:"required"`
-	Statement    string           `yaml:"statement" validate:"required"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
+	Name         string                 `yaml:"name" validate:"required"`
+	Kind         string                 `yaml:"kind" validate:"required"`
+	Source       string                 `yaml:"source" validate:"required"`
+	Description  string                 `yaml:"description" validate:"required"`
+	Statement    string                 `yaml:"statement" validate:"required"`
+	AuthRequired []string               `yaml:"authRequired"`
+	Parameters   tools.Parameters       `yaml:"parameters"`
+	Annotations  *tools.ToolAnnotations `yaml:"annotations"`
+	OutputSchema map[string]any         `yaml:"outputSchema"`
 }
 
 // validate interface
 var _ tools.ToolConfig = Config{}
+var _ tools.ReferencingConfig = Config{}
+var _ tools.ValidatingConfig = Config{}
 
 func (cfg Config) ToolConfigKind() string {
 	return ToolKind
 }
 
+func (cfg Config) References() tools.ConfigReferences {
+	return tools.ConfigReferences{
+		Source:                cfg.Source,
+		CompatibleSourceKinds: compatibleSources[:],
+		AuthRequired:          cfg.AuthRequired,
+		Parameters:            cfg.Parameters,
+	}
+}
+
+// Validate verifies that the identifiers of the statement can be substituted,
+// and that its output schema is valid.
+func (cfg Config) Validate() error {
+	if err := tools.CheckOutputSchema(cfg.OutputSchema); err != nil {
+		return err
+	}
+	return tools.CheckStatementTemplate(cfg.Statement, cfg.Parameters)
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -68,10 +108,21 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", ToolKind, compatibleSources)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	outputSchema, err := tools.NewRowsOutputSchema(cfg.OutputSchema)
+	if err != nil {
+		return nil, err
+	}
+
 	mcpManifest := tools.McpManifest{
-		Name:        cfg.Name,
-		Description: cfg.Description,
-		InputSchema: cfg.Parameters.McpManifest(),
+		Name:         cfg.Name,
+		Description:  cfg.Description,
+		InputSchema:  cfg.Parameters.McpManifest(),
+		Annotations:  tools.MergeAnnotations(tools.DatabaseAnnotations(), cfg.Annotations),
+		OutputSchema: outputSchema,
 	}
 
 	// finish tool setup
@@ -105,6 +156,148 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
+// queryValues returns a copy of params where the values of object parameters
+// are bound as STRUCT values, or as JSONB values in the PostgreSQL dialect, and
+// the values of temporal parameters have the matching Spanner types.
+func queryValues(parameters tools.Parameters, params tools.ParamValues, dialect string) (tools.ParamValues, error) {
+	out := make(tools.ParamValues, 0, len(params))
+	// params holds the value of each parameter, in order
+	for i, p := range params {
+		switch param := parameters[i].(type) {
+		case *tools.ObjectParameter:
+			if strings.ToLower(dialect) == "postgresql" {
+				p.Value = spanner.PGJsonB{Value: p.Value, Valid: p.Value != nil}
+				break
+			}
+			t, err := spannerType(param)
+			if err != nil {
+				return nil, err
+			}
+			v, err := spannerValue(param, p.Value)
+			if err != nil {
+				return nil, err
+			}
+			p.Value = spanner.GenericColumnValue{Type: t, Value: v}
+		case *tools.TimeParameter:
+			t, ok := p.Value.(time.Time)
+			switch param.GetType() {
+			case "date":
+				p.Value = spanner.NullDate{Date: civil.DateOf(t), Valid: ok}
+			case "timestamp":
+				p.Value = spanner.NullTime{Time: t, Valid: ok}
+			default:
+				// Spanner has no type for times of day
+				p.Value = spanner.NullString{StringVal: t.Format(tools.TimeOfDayLayout), Valid: ok}
+			}
+		case *tools.DurationParameter:
+			// durations are bound as a number of nanoseconds
+			d, ok := p.Value.(time.Duration)
+			p.Value = spanner.NullInt64{Int64: int64(d), Valid: ok}
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// spannerType returns the Spanner type of the values of parameter p.
+func spannerType(p tools.Parameter) (*sppb.Type, error) {
+	switch p := p.(type) {
+	case *tools.ArrayParameter:
+		elem, err := spannerType(p.Items)
+		if err != nil {
+			return nil, err
+		}
+		return &sppb.Type{Code: sppb.TypeCode_ARRAY, ArrayElementType: elem}, nil
+	case *tools.ObjectParameter:
+		fields := make([]*sppb.StructType_Field, 0, len(p.Properties))
+		for _, prop := range p.Properties {
+			t, err := spannerType(prop)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, &sppb.StructType_Field{Name: prop.GetName(), Type: t})
+		}
+		return &sppb.Type{Code: sppb.TypeCode_STRUCT, StructType: &sppb.StructType{Fields: fields}}, nil
+	}
+	switch p.GetType() {
+	case "boolean":
+		return &sppb.Type{Code: sppb.TypeCode_BOOL}, nil
+	case "string", "time":
+		return &sppb.Type{Code: sppb.TypeCode_STRING}, nil
+	case "integer", "duration":
+		return &sppb.Type{Code: sppb.TypeCode_INT64}, nil
+	case "float":
+		return &sppb.Type{Code: sppb.TypeCode_FLOAT64}, nil
+	case "date":
+		return &sppb.Type{Code: sppb.TypeCode_DATE}, nil
+	case "timestamp":
+		return &sppb.Type{Code: sppb.TypeCode_TIMESTAMP}, nil
+	}
+	return nil, fmt.Errorf("parameter %q of type %q is not supported by Spanner", p.GetName(), p.GetType())
+}
+
+// spannerValue returns the encoding of the value v of parameter p expected by
+// Spanner. The fields of STRUCT values are listed in order.
+func spannerValue(p tools.Parameter, v any) (*structpb.Value, error) {
+	if v == nil {
+		return structpb.NewNullValue(), nil
+	}
+	switch p := p.(type) {
+	case *tools.ArrayParameter:
+		elems := v.([]any)
+		values := make([]*structpb.Value, 0, len(elems))
+		for _, e := range elems {
+			ev, err := spannerValue(p.Items, e)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, ev)
+		}
+		return structpb.NewListValue(&structpb.ListValue{Values: values}), nil
+	case *tools.ObjectParameter:
+		m := v.(map[string]any)
+		values := make([]*structpb.Value, 0, len(p.Properties))
+		for _, prop := range p.Properties {
+			fv, err := spannerValue(prop, m[prop.GetName()])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, fv)
+		}
+		return structpb.NewListValue(&structpb.ListValue{Values: values}), nil
+	}
+	switch v := v.(type) {
+	case bool:
+		return structpb.NewBoolValue(v), nil
+	case string:
+		return structpb.NewStringValue(v), nil
+	case int:
+		// INT64 values are encoded as decimal strings
+		return structpb.NewStringValue(strconv.Itoa(v)), nil
+	case float64:
+		return structpb.NewNumberValue(v), nil
+	case time.Duration:
+		return structpb.NewStringValue(strconv.FormatInt(int64(v), 10)), nil
+	case time.Time:
+		switch p.GetType() {
+		case "date":
+			return structpb.NewStringValue(v.Format(time.DateOnly)), nil
+		case "time":
+			return structpb.NewStringValue(v.Format(tools.TimeOfDayLayout)), nil
+		}
+		return structpb.NewStringValue(v.UTC().Format(time.RFC3339Nano)), nil
+	}
+	return nil, fmt.Errorf("unable to encode %v for parameter %q", v, p.GetName())
+}
+
+// quoteFunc returns the quoting of identifiers in dialect.
+func quoteFunc(dialect string) tools.QuoteFunc {
+	if strings.ToLower(dialect) == "postgresql" {
+		return tools.QuoteDoubleQuotes
+	}
+	return tools.QuoteGoogleSQL
+}
+
 func getMapParams(params tools.ParamValues, dialect string) (map[string]interface{}, error) {
 	switch strings.ToLower(dialect) {
 	case "googlesql":
@@ -117,6 +310,14 @@ func getMapParams(params tools.ParamValues, dialect string) (map[string]interfac
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	params, err := queryValues(t.Parameters, params, t.dialect)
+	if err != nil {
+		return nil, err
+	}
+	statement, params, err := tools.ResolveIdentifiers(t.Statement, t.Parameters, params, quoteFunc(t.dialect))
+	if err != nil {
+		return nil, err
+	}
 	mapParams, err := getMapParams(params, t.dialect)
 	if err != nil {
 		return nil, fmt.Errorf("fail to get map params: %w", err)
@@ -126,7 +327,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, erro
 
 	_, err = t.Client.ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
 		stmt := spanner.Statement{
-			SQL:    t.Statement,
+			SQL:    statement,
 			Params: mapParams,
 		}
 		iter := txn.Query(ctx, stmt)
@@ -148,6 +349,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, erro
 			}
 
 			out = append(out, vMap)
+			tools.ReportRowProgress(ctx, len(out), 0)
 		}
 	})
 	if err != nil {
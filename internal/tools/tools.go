@@ -18,6 +18,8 @@ import (
 	"context"
 	"slices"
 
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/registry"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 )
 
@@ -26,6 +28,63 @@ type ToolConfig interface {
 	Initialize(map[string]sources.Source) (Tool, error)
 }
 
+// ToolConfigFactory decodes the config of the tool named name from decoder. It
+// is registered for a kind of tool with Register.
+type ToolConfigFactory = registry.Factory[ToolConfig]
+
+var kinds = registry.New[ToolConfig]("tool")
+
+// Register makes a kind of tool available in tools files. It is usually called
+// from the init function of the package implementing the tool. It returns
+// false if the kind is already registered.
+func Register(kind string, factory ToolConfigFactory) bool {
+	return kinds.Register(kind, factory)
+}
+
+// MustRegister is like Register, but panics if the kind is already registered.
+func MustRegister(kind string, factory ToolConfigFactory) {
+	kinds.MustRegister(kind, factory)
+}
+
+// DecodeConfig decodes the config of the tool named name using the factory
+// registered for kind.
+func DecodeConfig(ctx context.Context, kind, name string, decoder *yaml.Decoder) (ToolConfig, error) {
+	return kinds.DecodeConfig(ctx, kind, name, decoder)
+}
+
+// Kinds returns the registered kinds of tools, sorted.
+func Kinds() []string {
+	return kinds.Kinds()
+}
+
+// ConfigReferences lists the resources a tool config refers to.
+type ConfigReferences struct {
+	// Source is the name of the source the tool runs against.
+	Source string
+	// CompatibleSourceKinds are the kinds of source the tool can run against.
+	CompatibleSourceKinds []string
+	// AuthRequired are the auth services allowed to invoke the tool.
+	AuthRequired []string
+	// Parameters are the parameters of the tool, which may refer to auth
+	// services.
+	Parameters Parameters
+}
+
+// ReferencingConfig is implemented by tool configs so that their references to
+// other resources can be checked without initializing any of them.
+type ReferencingConfig interface {
+	ToolConfig
+	References() ConfigReferences
+}
+
+// ValidatingConfig is implemented by tool configs with checks that do not need
+// their source, so that they run both when the tool is initialized and when
+// the configuration is validated.
+type ValidatingConfig interface {
+	ToolConfig
+	Validate() error
+}
+
 type Tool interface {
 	Invoke(context.Context, ParamValues) ([]any, error)
 	ParseParams(map[string]any, map[string]map[string]any) (ParamValues, error)
@@ -49,6 +108,11 @@ type McpManifest struct {
 	Description string `json:"description,omitempty"`
 	// A JSON Schema object defining the expected parameters for the tool.
 	InputSchema McpToolsSchema `json:"inputSchema,omitempty"`
+	// Additional properties describing the tool to clients.
+	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+	// A JSON Schema object defining the structured content of the tool's
+	// results.
+	OutputSchema *McpOutputSchema `json:"outputSchema,omitempty"`
 }
 
 // Helper function that returns if a tool invocation request is authorized
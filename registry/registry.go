@@ -0,0 +1,148 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package registry lets programs built on Toolbox add their own kinds of
+// sources, tools and auth services.
+//
+// A kind is registered with a factory decoding its config, usually from the
+// init function of the package implementing it:
+//
+//	func init() {
+//		registry.MustRegisterSource("my-database", newConfig)
+//	}
+//
+//	func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (registry.SourceConfig, error) {
+//		actual := Config{Name: name}
+//		if err := decoder.DecodeContext(ctx, &actual); err != nil {
+//			return nil, err
+//		}
+//		return actual, nil
+//	}
+//
+// A custom binary then imports the package next to the Toolbox command:
+//
+//	import (
+//		"github.com/googleapis/genai-toolbox/cmd"
+//		_ "example.com/toolbox-extensions/mydatabase"
+//	)
+//
+//	func main() {
+//		cmd.Execute()
+//	}
+//
+// The decoder passed to factories is strict and checks the `validate` tags of
+// the config. Tool configs must have an `authRequired` field, which is set to
+// an empty list when the tools file does not set it.
+package registry
+
+import (
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// Types used to implement sources.
+type (
+	SourceConfig        = sources.SourceConfig
+	Source              = sources.Source
+	SourceConfigFactory = sources.SourceConfigFactory
+)
+
+// Types used to implement tools.
+type (
+	ToolConfig        = tools.ToolConfig
+	Tool              = tools.Tool
+	ToolConfigFactory = tools.ToolConfigFactory
+	ConfigReferences  = tools.ConfigReferences
+	ReferencingConfig = tools.ReferencingConfig
+	Manifest          = tools.Manifest
+	McpManifest       = tools.McpManifest
+	McpToolsSchema    = tools.McpToolsSchema
+	ToolAnnotations   = tools.ToolAnnotations
+	Parameter         = tools.Parameter
+	Parameters        = tools.Parameters
+	ParameterManifest = tools.ParameterManifest
+	ParamValue        = tools.ParamValue
+	ParamValues       = tools.ParamValues
+)
+
+// Types used to implement auth services.
+type (
+	AuthServiceConfig        = auth.AuthServiceConfig
+	AuthService              = auth.AuthService
+	AuthServiceConfigFactory = auth.AuthServiceConfigFactory
+)
+
+// RegisterSource makes a kind of source available in tools files. It returns
+// false if the kind is already registered.
+func RegisterSource(kind string, factory SourceConfigFactory) bool {
+	return sources.Register(kind, factory)
+}
+
+// RegisterTool makes a kind of tool available in tools files. It returns false
+// if the kind is already registered.
+func RegisterTool(kind string, factory ToolConfigFactory) bool {
+	return tools.Register(kind, factory)
+}
+
+// RegisterAuthService makes a kind of auth service available in tools files.
+// It returns false if the kind is already registered.
+func RegisterAuthService(kind string, factory AuthServiceConfigFactory) bool {
+	return auth.Register(kind, factory)
+}
+
+// MustRegisterSource is like RegisterSource, but panics if the kind is already
+// registered.
+func MustRegisterSource(kind string, factory SourceConfigFactory) {
+	sources.MustRegister(kind, factory)
+}
+
+// MustRegisterTool is like RegisterTool, but panics if the kind is already
+// registered.
+func MustRegisterTool(kind string, factory ToolConfigFactory) {
+	tools.MustRegister(kind, factory)
+}
+
+// MustRegisterAuthService is like RegisterAuthService, but panics if the kind
+// is already registered.
+func MustRegisterAuthService(kind string, factory AuthServiceConfigFactory) {
+	auth.MustRegister(kind, factory)
+}
+
+// SourceKinds returns the registered kinds of sources, sorted.
+func SourceKinds() []string {
+	return sources.Kinds()
+}
+
+// ToolKinds returns the registered kinds of tools, sorted.
+func ToolKinds() []string {
+	return tools.Kinds()
+}
+
+// AuthServiceKinds returns the registered kinds of auth services, sorted.
+func AuthServiceKinds() []string {
+	return auth.Kinds()
+}
+
+// ParseParams parses the arguments of a tool invocation against its
+// parameters. claims are the claims of the verified auth services, by name.
+func ParseParams(ps Parameters, data map[string]any, claims map[string]map[string]any) (ParamValues, error) {
+	return tools.ParseParams(ps, data, claims)
+}
+
+// IsAuthorized reports whether a tool requiring one of authRequired may be
+// invoked by a request verified by verifiedAuthServices.
+func IsAuthorized(authRequired, verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(authRequired, verifiedAuthServices)
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/registry"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	echoSourceKind = "test-echo"
+	echoToolKind   = "test-echo-tool"
+)
+
+type echoSourceConfig struct {
+	Name   string `yaml:"name" validate:"required"`
+	Kind   string `yaml:"kind" validate:"required"`
+	Prefix string `yaml:"prefix" validate:"required"`
+}
+
+func (c echoSourceConfig) SourceConfigKind() string { return echoSourceKind }
+
+func (c echoSourceConfig) Initialize(context.Context, trace.Tracer) (registry.Source, error) {
+	return echoSource{prefix: c.Prefix}, nil
+}
+
+type echoSource struct {
+	prefix string
+}
+
+func (echoSource) SourceKind() string { return echoSourceKind }
+
+type echoToolConfig struct {
+	Name         string              `yaml:"name" validate:"required"`
+	Kind         string              `yaml:"kind" validate:"required"`
+	Source       string              `yaml:"source" validate:"required"`
+	Description  string              `yaml:"description" validate:"required"`
+	AuthRequired []string            `yaml:"authRequired"`
+	Parameters   registry.Parameters `yaml:"parameters"`
+}
+
+func (c echoToolConfig) ToolConfigKind() string { return echoToolKind }
+
+func (c echoToolConfig) Initialize(srcs map[string]registry.Source) (registry.Tool, error) {
+	return echoTool{cfg: c, source: srcs[c.Source].(echoSource)}, nil
+}
+
+type echoTool struct {
+	cfg    echoToolConfig
+	source echoSource
+}
+
+func (t echoTool) Invoke(_ context.Context, params registry.ParamValues) ([]any, error) {
+	return []any{t.source.prefix + params.AsMap()["message"].(string)}, nil
+}
+
+func (t echoTool) ParseParams(data map[string]any, claims map[string]map[string]any) (registry.ParamValues, error) {
+	return registry.ParseParams(t.cfg.Parameters, data, claims)
+}
+
+func (t echoTool) Manifest() registry.Manifest {
+	return registry.Manifest{Description: t.cfg.Description, Parameters: t.cfg.Parameters.Manifest(), AuthRequired: t.cfg.AuthRequired}
+}
+
+func (t echoTool) McpManifest() registry.McpManifest {
+	return registry.McpManifest{Name: t.cfg.Name, Description: t.cfg.Description, InputSchema: t.cfg.Parameters.McpManifest()}
+}
+
+func (t echoTool) Authorized(verified []string) bool {
+	return registry.IsAuthorized(t.cfg.AuthRequired, verified)
+}
+
+func init() {
+	if !registry.RegisterSource(echoSourceKind, func(ctx context.Context, name string, decoder *yaml.Decoder) (registry.SourceConfig, error) {
+		actual := echoSourceConfig{Name: name}
+		if err := decoder.DecodeContext(ctx, &actual); err != nil {
+			return nil, err
+		}
+		return actual, nil
+	}) {
+		panic("source kind already registered")
+	}
+	if !registry.RegisterTool(echoToolKind, func(ctx context.Context, name string, decoder *yaml.Decoder) (registry.ToolConfig, error) {
+		actual := echoToolConfig{Name: name}
+		if err := decoder.DecodeContext(ctx, &actual); err != nil {
+			return nil, err
+		}
+		return actual, nil
+	}) {
+		panic("tool kind already registered")
+	}
+}
+
+func TestCustomKinds(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	sources:
+		my-echo:
+			kind: test-echo
+			prefix: "echo: "
+	tools:
+		my-echo-tool:
+			kind: test-echo-tool
+			source: my-echo
+			description: Echoes a message.
+			parameters:
+				- name: message
+				  type: string
+				  description: The message to echo.
+	`
+	var cfg struct {
+		Sources server.SourceConfigs `yaml:"sources"`
+		Tools   server.ToolConfigs   `yaml:"tools"`
+	}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &cfg, yaml.Strict()); err != nil {
+		t.Fatalf("unable to parse tools file: %s", err)
+	}
+
+	src, err := cfg.Sources["my-echo"].Initialize(ctx, nil)
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	tool, err := cfg.Tools["my-echo-tool"].Initialize(map[string]registry.Source{"my-echo": src})
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	params, err := tool.ParseParams(map[string]any{"message": "hello"}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	got, err := tool.Invoke(ctx, params)
+	if err != nil {
+		t.Fatalf("unable to invoke tool: %s", err)
+	}
+	if diff := cmp.Diff([]any{"echo: hello"}, got); diff != "" {
+		t.Fatalf("incorrect result: diff %v", diff)
+	}
+
+	if !slices.Contains(registry.SourceKinds(), echoSourceKind) {
+		t.Fatalf("source kind %q is not registered", echoSourceKind)
+	}
+	if !slices.Contains(registry.ToolKinds(), echoToolKind) {
+		t.Fatalf("tool kind %q is not registered", echoToolKind)
+	}
+	if registry.RegisterSource(echoSourceKind, nil) {
+		t.Fatalf("kind registered twice")
+	}
+}
+
+func TestUnknownKind(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	sources:
+		my-source:
+			kind: does-not-exist
+	`
+	var cfg struct {
+		Sources server.SourceConfigs `yaml:"sources"`
+	}
+	err = yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &cfg, yaml.Strict())
+	if err == nil || !strings.Contains(err.Error(), `"does-not-exist" is not a valid kind of data source`) {
+		t.Fatalf("expected unknown kind error, got %v", err)
+	}
+}
@@ -120,6 +120,7 @@ func runAiNlToolGetTest(t *testing.T) {
 							"type":        "string",
 							"description": "The natural language question to ask.",
 							"authSources": []any{},
+							"required":    true,
 						},
 					},
 					"authRequired": []any{},
@@ -0,0 +1,28 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolbox
+
+import (
+	"github.com/googleapis/genai-toolbox/internal/jsonschema"
+)
+
+// Schema returns the JSON Schema of tools files. It describes every kind of
+// source, tool and auth service registered, including custom ones, and can be
+// used by editors to complete and check tools files.
+func Schema() map[string]any {
+	s := jsonschema.Generate(ToolsFile{})
+	s["title"] = "Toolbox tools file"
+	return s
+}
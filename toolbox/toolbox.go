@@ -0,0 +1,307 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package toolbox runs Toolbox inside other Go programs.
+//
+// A Server is built from a ToolsFile, parsed from YAML with ParseToolsFile or
+// built in code with NewSourceConfig, NewToolConfig and NewAuthServiceConfig.
+// Its tools can be invoked directly with Invoke, and its HTTP API and MCP
+// endpoints can be served by an existing HTTP server:
+//
+//	toolsFile, err := toolbox.ParseToolsFile(ctx, raw)
+//	if err != nil {
+//		return err
+//	}
+//	s, err := toolbox.New(ctx, toolsFile, toolbox.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	defer s.Close(ctx)
+//	mux.Handle("/toolbox/", http.StripPrefix("/toolbox", s.Handler()))
+//	res, err := s.Invoke(ctx, "search-hotels", map[string]any{"city": "Paris"}, nil)
+//
+// The configs of a ToolsFile built in code take the fields of their section
+// in a tools file:
+//
+//	src, err := toolbox.NewSourceConfig(ctx, "my-pg", map[string]any{
+//		"kind":     "postgres",
+//		"host":     "127.0.0.1",
+//		"port":     "5432",
+//		"database": "hotels",
+//		"user":     user,
+//		"password": password,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	toolsFile := toolbox.ToolsFile{Sources: toolbox.SourceConfigs{"my-pg": src}}
+//
+// Every built-in kind of source, tool and auth service is available. Other
+// kinds can be added with the registry package.
+package toolbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/secrets"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// Types of the sections of a ToolsFile.
+type (
+	SourceConfig       = sources.SourceConfig
+	SourceConfigs      = server.SourceConfigs
+	AuthServiceConfig  = auth.AuthServiceConfig
+	ToolConfig         = tools.ToolConfig
+	AuthServiceConfigs = server.AuthServiceConfigs
+	ToolConfigs        = server.ToolConfigs
+	ToolsetConfigs     = server.ToolsetConfigs
+	ToolsetConfig      = tools.ToolsetConfig
+	PromptConfigs      = server.PromptConfigs
+	PromptConfig       = prompts.PromptConfig
+)
+
+type (
+	// Logger is the interface of the loggers of Toolbox.
+	Logger = log.Logger
+	// McpManifest describes a tool to MCP clients and LLMs.
+	McpManifest = tools.McpManifest
+)
+
+var (
+	// ErrToolNotFound is returned when invoking a tool that does not exist.
+	ErrToolNotFound = server.ErrToolNotFound
+	// ErrToolsetNotFound is returned when listing a toolset that does not
+	// exist.
+	ErrToolsetNotFound = server.ErrToolsetNotFound
+	// ErrUnauthorized is returned when invoking a tool without the claims of
+	// any of the auth services it requires.
+	ErrUnauthorized = server.ErrUnauthorized
+)
+
+// ToolsFile is the configuration of Toolbox, as read from a tools file.
+type ToolsFile struct {
+	// Include lists the paths of other tools files to merge, relative to
+	// the file including them. Paths may be glob patterns. ParseToolsFile
+	// does not resolve them, and New returns an error if it is not empty.
+	Include      []string           `yaml:"include"`
+	Sources      SourceConfigs      `yaml:"sources"`
+	AuthSources  AuthServiceConfigs `yaml:"authSources"` // Deprecated: Kept for compatibility.
+	AuthServices AuthServiceConfigs `yaml:"authServices"`
+	Tools        ToolConfigs        `yaml:"tools"`
+	Toolsets     ToolsetConfigs     `yaml:"toolsets"`
+	Prompts      PromptConfigs      `yaml:"prompts"`
+}
+
+// ParseToolsFile parses the contents of a tools file. Environment variables
+// and secret references are replaced with their values.
+func ParseToolsFile(ctx context.Context, raw []byte) (ToolsFile, error) {
+	ctx = parseContext(ctx)
+	var toolsFile ToolsFile
+	// Replace environment variables and secret references
+	expanded, err := secrets.Expand(ctx, string(raw))
+	if err != nil {
+		return toolsFile, err
+	}
+	// Parse contents
+	err = yaml.UnmarshalContext(ctx, []byte(expanded), &toolsFile, yaml.Strict())
+	if err != nil {
+		// parsing errors quote the file, which may contain secrets
+		return toolsFile, errors.New(secrets.Redact(err.Error()))
+	}
+	return toolsFile, nil
+}
+
+// NewSourceConfig returns the config of the source named name from the fields
+// of its section in a tools file, including its kind. Every registered kind is
+// supported, and the fields are checked as in a tools file. Environment
+// variables and secret references are not replaced.
+func NewSourceConfig(ctx context.Context, name string, fields map[string]any) (SourceConfig, error) {
+	return server.DecodeSourceConfig(parseContext(ctx), name, fields)
+}
+
+// NewToolConfig returns the config of the tool named name from the fields of
+// its section in a tools file, as NewSourceConfig does for sources.
+func NewToolConfig(ctx context.Context, name string, fields map[string]any) (ToolConfig, error) {
+	return server.DecodeToolConfig(parseContext(ctx), name, fields)
+}
+
+// NewAuthServiceConfig returns the config of the auth service named name from
+// the fields of its section in a tools file, as NewSourceConfig does for
+// sources.
+func NewAuthServiceConfig(ctx context.Context, name string, fields map[string]any) (AuthServiceConfig, error) {
+	return server.DecodeAuthServiceConfig(parseContext(ctx), name, fields)
+}
+
+// parseContext returns ctx with a logger, which parsing parameters uses to
+// log warnings.
+func parseContext(ctx context.Context) context.Context {
+	if _, err := util.LoggerFromContext(ctx); err != nil {
+		return util.WithLogger(ctx, discardLogger())
+	}
+	return ctx
+}
+
+// NewLogger returns a Logger writing informational messages to out and errors
+// to err. level is the minimum level logged: "debug", "info", "warn" or
+// "error".
+func NewLogger(out, err io.Writer, level string) (Logger, error) {
+	return log.NewStdLogger(out, err, level)
+}
+
+func discardLogger() Logger {
+	// the level is always valid
+	l, _ := log.NewStdLogger(io.Discard, io.Discard, "error")
+	return l
+}
+
+// Options configures a Server.
+type Options struct {
+	// Logger receives the logs of the server. They are discarded if it is
+	// nil.
+	Logger Logger
+	// LogLevel is the minimum level of the HTTP requests logged to standard
+	// output: "debug", "info", "warn" or "error". It defaults to "info".
+	LogLevel string
+	// Version is the version reported to clients in tool manifests. It
+	// defaults to "embedded".
+	Version string
+	// SessionStore is where MCP sessions are stored: "memory", the
+	// default, or the URL of a Redis server.
+	SessionStore string
+}
+
+// Server serves the tools of a ToolsFile.
+type Server struct {
+	s      *server.Server
+	opts   Options
+	logger Logger
+}
+
+// New initializes the sources, auth services, tools, toolsets and prompts of
+// toolsFile and returns a Server serving them.
+func New(ctx context.Context, toolsFile ToolsFile, opts Options) (*Server, error) {
+	if opts.Version == "" {
+		opts.Version = "embedded"
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = discardLogger()
+	}
+	// keep the secrets referenced by the tools file out of the logs
+	logger = log.NewRedactingLogger(logger, secrets.Redact)
+	ctx = util.WithLogger(ctx, logger)
+
+	cfg, err := opts.serverConfig(toolsFile)
+	if err != nil {
+		return nil, err
+	}
+	s, err := server.NewServer(ctx, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{s: s, opts: opts, logger: logger}, nil
+}
+
+// serverConfig returns the configuration of a server with opts serving the
+// resources of toolsFile.
+func (opts Options) serverConfig(toolsFile ToolsFile) (server.ServerConfig, error) {
+	if len(toolsFile.Include) > 0 {
+		return server.ServerConfig{}, fmt.Errorf("unable to include %q: includes are only supported by the toolbox command", toolsFile.Include)
+	}
+	cfg := server.ServerConfig{
+		Version:            opts.Version,
+		SourceConfigs:      toolsFile.Sources,
+		AuthServiceConfigs: toolsFile.AuthServices,
+		ToolConfigs:        toolsFile.Tools,
+		ToolsetConfigs:     toolsFile.Toolsets,
+		PromptConfigs:      toolsFile.Prompts,
+		SseSessionStore:    opts.SessionStore,
+		// the defaults of the command line
+		SseKeepAliveInterval:  30 * time.Second,
+		SseSessionIdleTimeout: 5 * time.Minute,
+		SseReplayBufferSize:   100,
+	}
+	if len(toolsFile.AuthSources) > 0 {
+		// the configs of toolsFile are not modified
+		cfg.AuthServiceConfigs = maps.Clone(cfg.AuthServiceConfigs)
+		if cfg.AuthServiceConfigs == nil {
+			cfg.AuthServiceConfigs = make(AuthServiceConfigs)
+		}
+		for name, a := range toolsFile.AuthSources {
+			if _, ok := cfg.AuthServiceConfigs[name]; ok {
+				return cfg, fmt.Errorf("authService %q is defined in both authSources and authServices", name)
+			}
+			cfg.AuthServiceConfigs[name] = a
+		}
+	}
+	if opts.LogLevel != "" {
+		if err := cfg.LogLevel.Set(opts.LogLevel); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
+
+// Handler returns the handler serving the HTTP API and MCP endpoints of s.
+// Its routes are "/api/..." and "/mcp/...", so it is usually mounted with
+// http.StripPrefix.
+func (s *Server) Handler() http.Handler {
+	return s.s.Handler()
+}
+
+// Invoke invokes the tool named toolName with params, without going through
+// HTTP. claims maps the names of auth services to the claims of the caller
+// verified by them. They authorize the invocation of tools requiring auth
+// services and fill the parameters bound to them.
+func (s *Server) Invoke(ctx context.Context, toolName string, params map[string]any, claims map[string]map[string]any) ([]any, error) {
+	return s.s.InvokeTool(util.WithLogger(ctx, s.logger), toolName, params, claims)
+}
+
+// Tools returns the manifests of the tools of a toolset, sorted by name. The
+// empty name refers to the toolset of every tool.
+func (s *Server) Tools(toolset string) ([]McpManifest, error) {
+	return s.s.ListTools(toolset)
+}
+
+// Reload replaces the resources served with the ones of toolsFile. Only the
+// sources and auth services whose configuration changed are initialized
+// again. If toolsFile is invalid, an error is returned and the current
+// resources keep being served.
+func (s *Server) Reload(ctx context.Context, toolsFile ToolsFile) error {
+	cfg, err := s.opts.serverConfig(toolsFile)
+	if err != nil {
+		return err
+	}
+	return s.s.Reload(util.WithLogger(ctx, s.logger), cfg)
+}
+
+// Close ends the MCP sessions of s and closes its sources once the
+// invocations in progress are done. s must not be used afterwards.
+func (s *Server) Close(ctx context.Context) error {
+	return s.s.Shutdown(ctx)
+}
@@ -0,0 +1,348 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/toolbox"
+)
+
+func toolsFileYaml(database string) string {
+	return `
+	sources:
+		my-sqlite:
+			kind: sqlite
+			database: ` + database + `
+	authServices:
+		my-google-auth:
+			kind: google
+			clientId: my-client-id
+	tools:
+		add:
+			kind: sqlite-sql
+			source: my-sqlite
+			description: Adds two numbers.
+			statement: SELECT ? + ? AS sum;
+			parameters:
+				- name: a
+				  type: integer
+				  description: The first number.
+				- name: b
+				  type: integer
+				  description: The second number.
+		search:
+			kind: sqlite-sql
+			source: my-sqlite
+			description: Returns its filters.
+			statement: SELECT ? AS status, ? AS lim;
+			parameters:
+				- name: status
+				  type: string
+				  description: The status to filter on.
+				  required: false
+				- name: limit
+				  type: integer
+				  description: The maximum number of results.
+				  default: 10
+		locate:
+			kind: sqlite-sql
+			source: my-sqlite
+			description: Returns the city of an address.
+			statement: SELECT json_extract(?, '$.city') AS city;
+			parameters:
+				- name: address
+				  type: object
+				  description: The address.
+				  properties:
+					- name: city
+					  type: string
+					  description: The city.
+		sorted:
+			kind: sqlite-sql
+			source: my-sqlite
+			description: Returns the values of a column, sorted.
+			statement: SELECT {{.column}} AS v FROM (SELECT 1 AS a, 2 AS b UNION SELECT 3, 0) WHERE a > ? ORDER BY {{.column}} {{.order}};
+			parameters:
+				- name: column
+				  type: identifier
+				  description: The column.
+				  enum: [a, b]
+				- name: min
+				  type: integer
+				  description: The minimum value of a.
+				- name: order
+				  type: identifier
+				  description: The sort order.
+				  enum: [ASC, DESC]
+				  quoted: false
+				  default: ASC
+		weekday:
+			kind: sqlite-sql
+			source: my-sqlite
+			description: Returns the day of the week of a date.
+			statement: SELECT strftime('%w', ?) AS weekday;
+			parameters:
+				- name: day
+				  type: date
+				  description: The date.
+		whoami:
+			kind: sqlite-sql
+			source: my-sqlite
+			description: Returns the email of the caller.
+			statement: SELECT ? AS email;
+			authRequired:
+				- my-google-auth
+			parameters:
+				- name: email
+				  type: string
+				  description: The email of the caller.
+				  authServices:
+					- name: my-google-auth
+					  field: email
+	toolsets:
+		math:
+			- add
+	`
+}
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	toolsFile, err := toolbox.ParseToolsFile(ctx, testutils.FormatYaml(toolsFileYaml(filepath.Join(dir, "first.db"))))
+	if err != nil {
+		t.Fatalf("unable to parse tools file: %s", err)
+	}
+	s, err := toolbox.New(ctx, toolsFile, toolbox.Options{LogLevel: "error"})
+	if err != nil {
+		t.Fatalf("unable to create server: %s", err)
+	}
+	defer s.Close(ctx)
+
+	t.Run("invoke", func(t *testing.T) {
+		got, err := s.Invoke(ctx, "add", map[string]any{"a": 1, "b": 2}, nil)
+		if err != nil {
+			t.Fatalf("unable to invoke tool: %s", err)
+		}
+		want := []any{map[string]any{"sum": int64(3)}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("incorrect result: diff %v", diff)
+		}
+	})
+
+	t.Run("invoke with claims", func(t *testing.T) {
+		claims := map[string]map[string]any{"my-google-auth": {"email": "alice@example.com"}}
+		got, err := s.Invoke(ctx, "whoami", nil, claims)
+		if err != nil {
+			t.Fatalf("unable to invoke tool: %s", err)
+		}
+		want := []any{map[string]any{"email": "alice@example.com"}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("incorrect result: diff %v", diff)
+		}
+	})
+
+	t.Run("invoke with optional parameters", func(t *testing.T) {
+		got, err := s.Invoke(ctx, "search", nil, nil)
+		if err != nil {
+			t.Fatalf("unable to invoke tool: %s", err)
+		}
+		want := []any{map[string]any{"status": nil, "lim": int64(10)}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("incorrect result: diff %v", diff)
+		}
+	})
+
+	t.Run("invoke with object parameters", func(t *testing.T) {
+		got, err := s.Invoke(ctx, "locate", map[string]any{"address": map[string]any{"city": "Paris"}}, nil)
+		if err != nil {
+			t.Fatalf("unable to invoke tool: %s", err)
+		}
+		want := []any{map[string]any{"city": "Paris"}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("incorrect result: diff %v", diff)
+		}
+	})
+
+	t.Run("invoke with temporal parameters", func(t *testing.T) {
+		got, err := s.Invoke(ctx, "weekday", map[string]any{"day": "2024-01-31"}, nil)
+		if err != nil {
+			t.Fatalf("unable to invoke tool: %s", err)
+		}
+		want := []any{map[string]any{"weekday": "3"}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("incorrect result: diff %v", diff)
+		}
+	})
+
+	t.Run("invoke with identifier parameters", func(t *testing.T) {
+		got, err := s.Invoke(ctx, "sorted", map[string]any{"column": "b", "min": 0, "order": "DESC"}, nil)
+		if err != nil {
+			t.Fatalf("unable to invoke tool: %s", err)
+		}
+		want := []any{map[string]any{"v": int64(2)}, map[string]any{"v": int64(0)}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("incorrect result: diff %v", diff)
+		}
+	})
+
+	t.Run("invoke errors", func(t *testing.T) {
+		if _, err := s.Invoke(ctx, "missing", nil, nil); !errors.Is(err, toolbox.ErrToolNotFound) {
+			t.Fatalf("expected ErrToolNotFound, got %v", err)
+		}
+		if _, err := s.Invoke(ctx, "whoami", nil, nil); !errors.Is(err, toolbox.ErrUnauthorized) {
+			t.Fatalf("expected ErrUnauthorized, got %v", err)
+		}
+		if _, err := s.Invoke(ctx, "add", map[string]any{"a": 1}, nil); err == nil {
+			t.Fatalf("expected error for missing parameter")
+		}
+	})
+
+	t.Run("tools", func(t *testing.T) {
+		manifests, err := s.Tools("math")
+		if err != nil {
+			t.Fatalf("unable to list tools: %s", err)
+		}
+		if len(manifests) != 1 || manifests[0].Name != "add" {
+			t.Fatalf("unexpected tools: %v", manifests)
+		}
+		if _, err := s.Tools("missing"); !errors.Is(err, toolbox.ErrToolsetNotFound) {
+			t.Fatalf("expected ErrToolsetNotFound, got %v", err)
+		}
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.Handle("/toolbox/", http.StripPrefix("/toolbox", s.Handler()))
+		ts := httptest.NewServer(mux)
+		defer ts.Close()
+
+		resp, err := http.Post(ts.URL+"/toolbox/api/tool/add/invoke", "application/json", strings.NewReader(`{"a": 2, "b": 3}`))
+		if err != nil {
+			t.Fatalf("unable to invoke tool: %s", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response: %s", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status %d: %s", resp.StatusCode, body)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unable to parse response: %s", err)
+		}
+		if diff := cmp.Diff(map[string]any{"result": `[{"sum":5}]`}, got); diff != "" {
+			t.Fatalf("incorrect response: diff %v", diff)
+		}
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		toolsFile, err := toolbox.ParseToolsFile(ctx, testutils.FormatYaml(toolsFileYaml(filepath.Join(dir, "second.db"))))
+		if err != nil {
+			t.Fatalf("unable to parse tools file: %s", err)
+		}
+		toolsFile.Toolsets["math"] = toolbox.ToolsetConfig{Name: "math", ToolNames: []string{"add", "whoami"}}
+		if err := s.Reload(ctx, toolsFile); err != nil {
+			t.Fatalf("unable to reload: %s", err)
+		}
+		manifests, err := s.Tools("math")
+		if err != nil {
+			t.Fatalf("unable to list tools: %s", err)
+		}
+		if len(manifests) != 2 {
+			t.Fatalf("unexpected tools after reload: %v", manifests)
+		}
+	})
+}
+
+func TestParseToolsFileError(t *testing.T) {
+	_, err := toolbox.ParseToolsFile(context.Background(), []byte("sources:\n  my-source:\n    kind: does-not-exist\n"))
+	if err == nil || !strings.Contains(err.Error(), `"does-not-exist" is not a valid kind of data source`) {
+		t.Fatalf("expected unknown kind error, got %v", err)
+	}
+}
+
+func TestNewWithIncludes(t *testing.T) {
+	toolsFile := toolbox.ToolsFile{Include: []string{"more.yaml"}}
+	_, err := toolbox.New(context.Background(), toolsFile, toolbox.Options{})
+	if err == nil || !strings.Contains(err.Error(), "includes are only supported by the toolbox command") {
+		t.Fatalf("expected include error, got %v", err)
+	}
+}
+
+func TestNewWithConfigsBuiltInCode(t *testing.T) {
+	ctx := context.Background()
+	src, err := toolbox.NewSourceConfig(ctx, "my-sqlite", map[string]any{
+		"kind":     "sqlite",
+		"database": filepath.Join(t.TempDir(), "code.db"),
+	})
+	if err != nil {
+		t.Fatalf("unable to build source config: %s", err)
+	}
+	fields := map[string]any{
+		"kind":        "sqlite-sql",
+		"source":      "my-sqlite",
+		"description": "Doubles a number.",
+		"statement":   "SELECT ? * 2 AS doubled;",
+		"parameters": []any{
+			map[string]any{"name": "n", "type": "integer", "description": "The number."},
+		},
+	}
+	tool, err := toolbox.NewToolConfig(ctx, "double", fields)
+	if err != nil {
+		t.Fatalf("unable to build tool config: %s", err)
+	}
+	if _, ok := fields["authRequired"]; ok {
+		t.Fatalf("the fields of the tool were modified: %v", fields)
+	}
+
+	toolsFile := toolbox.ToolsFile{
+		Sources: toolbox.SourceConfigs{"my-sqlite": src},
+		Tools:   toolbox.ToolConfigs{"double": tool},
+	}
+	s, err := toolbox.New(ctx, toolsFile, toolbox.Options{})
+	if err != nil {
+		t.Fatalf("unable to create server: %s", err)
+	}
+	defer s.Close(ctx)
+	got, err := s.Invoke(ctx, "double", map[string]any{"n": 21}, nil)
+	if err != nil {
+		t.Fatalf("unable to invoke tool: %s", err)
+	}
+	if diff := cmp.Diff([]any{map[string]any{"doubled": int64(42)}}, got); diff != "" {
+		t.Fatalf("incorrect result: diff %v", diff)
+	}
+
+	_, err = toolbox.NewSourceConfig(ctx, "my-source", map[string]any{"kind": "does-not-exist"})
+	if err == nil || !strings.Contains(err.Error(), `"does-not-exist" is not a valid kind of data source`) {
+		t.Fatalf("expected unknown kind error, got %v", err)
+	}
+	_, err = toolbox.NewAuthServiceConfig(ctx, "my-auth", map[string]any{"kind": "google"})
+	if err == nil {
+		t.Fatalf("expected error for missing clientId")
+	}
+}